@@ -10,7 +10,15 @@ func setCLIClient(cmd *cobra.Command, _ []string) error {
 
 	kubeConfig.SetLogger(logger)
 
-	if cmd.Name() != "supported-annotations" {
+	if err := opts.Validate(); err != nil {
+		return err
+	}
+
+	if err := cliCfg.parsePlugins(); err != nil {
+		return err
+	}
+
+	if cmd.Name() != "supported-annotations" && !cliCfg.hasLocalSources() {
 		if err := kubeConfig.SetKubeClient(); err != nil {
 			return err
 		}
@@ -18,5 +26,14 @@ func setCLIClient(cmd *cobra.Command, _ []string) error {
 
 	kubeConfig.SetKubeNameSpace()
 
+	opts.ConfigMapData = func(namespace, name string) (map[string]string, error) {
+		configMap, err := getConfigMap(namespace + "/" + name)
+		if err != nil {
+			return nil, err
+		}
+
+		return configMap.Data, nil
+	}
+
 	return nil
 }
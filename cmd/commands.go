@@ -12,9 +12,11 @@ import (
 	"github.com/nikhilsbhat/nginx-traefik-converter/pkg/configs"
 	"github.com/nikhilsbhat/nginx-traefik-converter/pkg/convert"
 	"github.com/nikhilsbhat/nginx-traefik-converter/pkg/converters/models"
+	"github.com/nikhilsbhat/nginx-traefik-converter/pkg/ingress"
 	"github.com/nikhilsbhat/nginx-traefik-converter/pkg/render"
 	"github.com/nikhilsbhat/nginx-traefik-converter/version"
 	"github.com/spf13/cobra"
+	netv1 "k8s.io/api/networking/v1"
 )
 
 func getRootCommand() *cobra.Command {
@@ -43,37 +45,55 @@ func getVersionCommand() *cobra.Command {
 
 func getConvertCommand() *cobra.Command {
 	convertCommand := &cobra.Command{
-		Use:     "convert [flags]",
-		Short:   "Converts the ingress nginx to equivalent trafik configs",
-		Long:    "Command that reads the existing nginx ingress and creates an alternatives in traefik, it auto maps annotations",
-		Example: ``,
+		Use:   "convert [flags]",
+		Short: "Converts the ingress nginx to equivalent trafik configs",
+		Long:  "Command that reads the existing nginx ingress and creates an alternatives in traefik, it auto maps annotations",
+		Example: `nginx-traefik-converter convert -a
+nginx-traefik-converter convert -f ingress.yaml -f manifests/
+nginx-traefik-converter convert -a --tcp-services-configmap ingress-nginx/tcp-services
+nginx-traefik-converter convert -a --controller-configmap ingress-nginx/ingress-nginx-controller
+kubectl get ingress -A -o yaml | nginx-traefik-converter convert -f -`,
 		PreRunE: setCLIClient,
 		RunE: func(_ *cobra.Command, _ []string) error {
-			ingresses, err := kubeConfig.ListAllIngresses()
+			ingresses, err := listIngresses()
 			if err != nil {
 				return err
 			}
 
-			var globalReport configs.GlobalReport
+			var (
+				globalReport configs.GlobalReport
+				results      []configs.Result
+			)
+
+			controllerResult, controllerReport, err := convertController()
+			if err != nil {
+				return err
+			}
 
-			for _, ingress := range ingresses {
+			if controllerResult != nil {
+				results = append(results, *controllerResult)
+				globalReport.Ingresses = append(globalReport.Ingresses, *controllerReport)
+			}
+
+			canaries := convert.PairCanaries(ingresses)
+
+			for _, ing := range ingresses {
 				res := configs.NewResult()
-				ctx := configs.New(&ingress, res, opts, logger)
-				ctx.StartIngressReport(ingress.Namespace, ingress.Name)
+				ctx := configs.New(&ing, res, opts, logger)
+				canaries.Attach(ctx)
+				ctx.StartIngressReport(ing.Namespace, ing.Name)
 
 				if err = convert.Run(*ctx); err != nil {
 					logger.Error("converting ingress to traefik errored",
-						slog.Any("ingress", ingress.Name),
+						slog.Any("ingress", ing.Name),
 						slog.Any("error:", err.Error()))
 
 					continue
 				}
 
-				if err = render.WriteYAML(*res, filepath.Join("./out", ingress.Name)); err != nil {
-					logger.Error("writing converted traefik ingress errored",
-						slog.Any("ingress", ingress.Name),
-						slog.Any("error:", err.Error()))
+				results = append(results, *res)
 
+				if err = writeIngressResult(*res, ing.Namespace, ing.Name); err != nil {
 					return err
 				}
 
@@ -87,6 +107,28 @@ func getConvertCommand() *cobra.Command {
 				)
 			}
 
+			streamResults, streamReports, err := convertStreams()
+			if err != nil {
+				return err
+			}
+
+			results = append(results, streamResults...)
+			globalReport.Ingresses = append(globalReport.Ingresses, streamReports...)
+
+			if err = writeStaticConfig(results); err != nil {
+				return err
+			}
+
+			if cliCfg.ToFile != "" {
+				if err = render.WriteBundle(results, cliCfg.ToFile); err != nil {
+					logger.Error("writing converted traefik bundle errored",
+						slog.Any("file", cliCfg.ToFile),
+						slog.Any("error:", err.Error()))
+
+					return err
+				}
+			}
+
 			if err = printerConfig.PrintGlobalSummary(globalReport); err != nil {
 				return err
 			}
@@ -104,6 +146,52 @@ func getConvertCommand() *cobra.Command {
 	return convertCommand
 }
 
+// writeIngressResult writes the converted resources of a single ingress to <out-dir>/<namespace>/<name>.
+// Nothing is written per ingress when a bundle is requested via --to-file.
+func writeIngressResult(res configs.Result, namespace, name string) error {
+	if cliCfg.ToFile != "" {
+		return nil
+	}
+
+	if err := render.WriteYAML(res, filepath.Join(cliCfg.OutDir, namespace, name)); err != nil {
+		logger.Error("writing converted traefik ingress errored",
+			slog.Any("ingress", name),
+			slog.Any("namespace", namespace),
+			slog.Any("error:", err.Error()))
+
+		return err
+	}
+
+	return nil
+}
+
+// listIngresses returns the ingresses from the local manifests when any were passed,
+// otherwise it lists them from the cluster.
+func listIngresses() ([]netv1.Ingress, error) {
+	if !cliCfg.hasLocalSources() {
+		return kubeConfig.ListAllIngresses()
+	}
+
+	manifests, err := ingress.LoadManifests(cliCfg.sources()...)
+	if err != nil {
+		return nil, err
+	}
+
+	localManifests = manifests
+	ingresses := manifests.Ingresses
+
+	for index := range ingresses {
+		// Manifests kept in Git frequently omit the namespace, kubectl would apply them to the selected one.
+		if ingresses[index].Namespace == "" {
+			ingresses[index].Namespace = kubeConfig.NameSpace
+		}
+	}
+
+	logger.Debug("loaded ingresses from local manifests", slog.Int("count", len(ingresses)))
+
+	return ingresses, nil
+}
+
 func getSupportedAnnotationCommand() *cobra.Command {
 	supportedAnnotationsCommand := &cobra.Command{
 		Use:     "supported-annotations [flags]",
@@ -0,0 +1,167 @@
+package cmd
+
+import (
+	"fmt"
+	"log/slog"
+	"path/filepath"
+	"strings"
+
+	"github.com/nikhilsbhat/nginx-traefik-converter/pkg/configs"
+	"github.com/nikhilsbhat/nginx-traefik-converter/pkg/converters/controller"
+	"github.com/nikhilsbhat/nginx-traefik-converter/pkg/converters/streams"
+	"github.com/nikhilsbhat/nginx-traefik-converter/pkg/render"
+	corev1 "k8s.io/api/core/v1"
+)
+
+// convertStreams converts the tcp-services and udp-services ConfigMaps passed via the flags.
+// Each ConfigMap gets its own result and report, written to <out-dir>/<namespace>/<name>.
+func convertStreams() ([]configs.Result, []configs.IngressReport, error) {
+	sources := []struct {
+		protocol  string
+		configMap string
+	}{
+		{protocol: streams.ProtocolTCP, configMap: cliCfg.TCPServicesConfigMap},
+		{protocol: streams.ProtocolUDP, configMap: cliCfg.UDPServicesConfigMap},
+	}
+
+	results := make([]configs.Result, 0)
+	reports := make([]configs.IngressReport, 0)
+
+	for _, source := range sources {
+		if source.configMap == "" {
+			continue
+		}
+
+		configMap, err := getConfigMap(source.configMap)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		res := configs.NewResult()
+		ctx := &configs.Context{
+			IngressName: configMap.Name,
+			Namespace:   configMap.Namespace,
+			Result:      res,
+			Options:     opts,
+			Log:         logger,
+		}
+		ctx.StartIngressReport(configMap.Namespace, configMap.Name)
+
+		streams.Convert(*ctx, configMap, source.protocol)
+
+		results = append(results, *res)
+
+		if err = writeIngressResult(*res, configMap.Namespace, configMap.Name); err != nil {
+			return nil, nil, err
+		}
+
+		if err = printerConfig.PrintIngressSummary(ctx.Result.IngressReport); err != nil {
+			return nil, nil, err
+		}
+
+		reports = append(reports, ctx.Result.IngressReport)
+	}
+
+	return results, reports, nil
+}
+
+// getConfigMap returns the ConfigMap referenced as <namespace>/<name>, from the local manifests
+// when they were passed, otherwise from the cluster.
+func getConfigMap(ref string) (*corev1.ConfigMap, error) {
+	namespace, name, found := strings.Cut(ref, "/")
+	if !found {
+		namespace, name = kubeConfig.NameSpace, ref
+	}
+
+	if localManifests == nil {
+		return kubeConfig.GetConfigMap(namespace, name)
+	}
+
+	configMap := localManifests.ConfigMap(namespace, name)
+	if configMap == nil {
+		return nil, fmt.Errorf("ConfigMap %s/%s was not found in the local manifests", namespace, name)
+	}
+
+	if configMap.Namespace == "" {
+		configMap.Namespace = namespace
+	}
+
+	return configMap, nil
+}
+
+// convertController converts the controller ConfigMap passed via --controller-configmap before the ingresses,
+// so that its defaults apply to them. The result is written to <out-dir>/<namespace>/<name>.
+func convertController() (*configs.Result, *configs.IngressReport, error) {
+	if cliCfg.ControllerConfigMap == "" {
+		return nil, nil, nil
+	}
+
+	configMap, err := getConfigMap(cliCfg.ControllerConfigMap)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	res := configs.NewResult()
+	ctx := &configs.Context{
+		IngressName: configMap.Name,
+		Namespace:   configMap.Namespace,
+		Result:      res,
+		Options:     opts,
+		Log:         logger,
+	}
+	ctx.StartIngressReport(configMap.Namespace, configMap.Name)
+
+	controller.Convert(*ctx, configMap)
+
+	if err = writeIngressResult(*res, configMap.Namespace, configMap.Name); err != nil {
+		return nil, nil, err
+	}
+
+	if err = printerConfig.PrintIngressSummary(ctx.Result.IngressReport); err != nil {
+		return nil, nil, err
+	}
+
+	return res, &ctx.Result.IngressReport, nil
+}
+
+// writeStaticConfig writes the Traefik static configuration required by the converted resources and the
+// equivalent Helm values, next to the bundle when --to-file is set, otherwise to the out-dir.
+func writeStaticConfig(results []configs.Result) error {
+	static := configs.NewStaticConfig(cliCfg.plugins)
+
+	for _, res := range results {
+		static.Merge(res)
+	}
+
+	// The plugins without a known module are written with a placeholder module, which Traefik cannot load.
+	if len(static.MissingPlugins) > 0 {
+		logger.Warn("the generated middlewares use plugins without a known module, replace the placeholder "+
+			"modules of experimental.plugins or declare them with --plugin <name>=<module>@<version>",
+			slog.Any("plugins", strings.Join(static.MissingPlugins, ", ")))
+	}
+
+	dir := cliCfg.OutDir
+	if cliCfg.ToFile != "" {
+		dir = filepath.Dir(cliCfg.ToFile)
+	}
+
+	path := filepath.Join(dir, render.StaticConfigFile)
+	if err := render.WriteStaticConfig(static, path); err != nil {
+		logger.Error("writing traefik static configuration errored",
+			slog.Any("file", path),
+			slog.Any("error:", err.Error()))
+
+		return err
+	}
+
+	path = filepath.Join(dir, render.HelmValuesFile)
+	if err := render.WriteHelmValues(static, path); err != nil {
+		logger.Error("writing traefik helm values errored",
+			slog.Any("file", path),
+			slog.Any("error:", err.Error()))
+
+		return err
+	}
+
+	return nil
+}
@@ -4,6 +4,7 @@ import (
 	"log/slog"
 
 	"github.com/nikhilsbhat/nginx-traefik-converter/pkg/configs"
+	"github.com/nikhilsbhat/nginx-traefik-converter/pkg/ingress"
 	"github.com/nikhilsbhat/nginx-traefik-converter/pkg/kubernetes"
 	"github.com/nikhilsbhat/nginx-traefik-converter/pkg/render"
 	"github.com/spf13/cobra"
@@ -11,11 +12,17 @@ import (
 
 // Config holds the information of the cli config.
 type Config struct {
-	NoColor     bool
-	LogLevel    string
-	IngressFile string
-	ToFile      string
-	Files       []string
+	NoColor              bool
+	LogLevel             string
+	IngressFile          string
+	ToFile               string
+	OutDir               string
+	ControllerConfigMap  string
+	TCPServicesConfigMap string
+	UDPServicesConfigMap string
+	Files                []string
+	Plugins              []string
+	plugins              map[string]configs.Plugin
 }
 
 var (
@@ -24,16 +31,51 @@ var (
 	logger        *slog.Logger
 	kubeConfig    = kubernetes.New()
 	printerConfig = render.New()
+	// localManifests holds the resources read from the local manifests, nil when reading from the cluster.
+	localManifests *ingress.Manifests
 )
 
+// sources returns all local manifest paths passed via --ingress-file and --file.
+func (cfg *Config) sources() []string {
+	sources := make([]string, 0, len(cfg.Files)+1)
+
+	if cfg.IngressFile != "" {
+		sources = append(sources, cfg.IngressFile)
+	}
+
+	return append(sources, cfg.Files...)
+}
+
+// parsePlugins parses the plugins passed via --plugin.
+func (cfg *Config) parsePlugins() error {
+	cfg.plugins = make(map[string]configs.Plugin)
+
+	for _, val := range cfg.Plugins {
+		name, plugin, err := configs.ParsePlugin(val)
+		if err != nil {
+			return err
+		}
+
+		cfg.plugins[name] = plugin
+	}
+
+	return nil
+}
+
+// hasLocalSources reports whether ingresses should be read from local manifests instead of the cluster.
+func (cfg *Config) hasLocalSources() bool {
+	return len(cfg.sources()) > 0
+}
+
 // Registers all global flags to utility.
 func registerCommonFlags(cmd *cobra.Command) {
 	cmd.PersistentFlags().StringVarP(&cliCfg.LogLevel, "log-level", "", "INFO",
 		"log level for the nginx-traefik-converter")
 	cmd.PersistentFlags().StringVarP(&cliCfg.IngressFile, "ingress-file", "", "",
-		"path to ingress file")
+		"path to a file or directory containing ingress manifests, use '-' to read from stdin")
 	cmd.PersistentFlags().StringArrayVarP(&cliCfg.Files, "file", "f", nil,
-		"root yaml files to be used for importing")
+		"files or directories (walked recursively) containing ingress manifests, use '-' to read from stdin; "+
+			"when set, ingresses are not fetched from the cluster")
 	cmd.PersistentFlags().BoolVarP(&cliCfg.NoColor, "no-color", "", false,
 		"when enabled the output would not be color encoded")
 	cmd.PersistentFlags().StringVarP(&kubeConfig.Context, "context", "c", "",
@@ -46,11 +88,32 @@ func registerCommonFlags(cmd *cobra.Command) {
 
 func registerImportFlags(cmd *cobra.Command) {
 	cmd.PersistentFlags().StringVarP(&cliCfg.ToFile, "to-file", "", "",
-		"name of the file to which the final imported yaml should be written to")
+		"when set, all the converted resources are written to this file as a single ordered multi-document bundle")
+	cmd.PersistentFlags().StringVarP(&cliCfg.OutDir, "out-dir", "", "./out",
+		"directory to which the converted resources are written, laid out as <out-dir>/<namespace>/<ingress>/")
+	cmd.PersistentFlags().StringVarP(&cliCfg.ControllerConfigMap, "controller-configmap", "", "",
+		"<namespace>/<name> of the ingress-nginx controller ConfigMap, converted into the Traefik static configuration, "+
+			"default middlewares and TLSOption, its settings apply as defaults to the ingresses; "+
+			"read from the local manifests when --file is set")
+	cmd.PersistentFlags().StringVarP(&cliCfg.TCPServicesConfigMap, "tcp-services-configmap", "", "",
+		"<namespace>/<name> of the ingress-nginx tcp-services ConfigMap to convert into IngressRouteTCPs, "+
+			"read from the local manifests when --file is set")
+	cmd.PersistentFlags().StringVarP(&cliCfg.UDPServicesConfigMap, "udp-services-configmap", "", "",
+		"<namespace>/<name> of the ingress-nginx udp-services ConfigMap to convert into IngressRouteUDPs, "+
+			"read from the local manifests when --file is set")
 	cmd.PersistentFlags().BoolVarP(&printerConfig.Table, "table", "", false,
 		"when enabled prints output in table format")
 	cmd.PersistentFlags().BoolVarP(&opts.DisablePlugins, "disable-plugins", "", false,
 		"when enabled won't consider the plugins while creating middlewares")
+	cmd.PersistentFlags().StringArrayVarP(&cliCfg.Plugins, "plugin", "", nil,
+		"plugin declared in the generated Traefik static configuration, as <name>=<module>@<version>; "+
+			"overrides the pinned module of the plugins used by the middlewares or declares the ones without a known module")
+	cmd.PersistentFlags().StringVarP(&opts.RouteMode, "route-mode", "", configs.RouteModeAuto,
+		"how the routes are generated, 'auto' builds an IngressRoute only when required (backend-protocol, grpc-backend), "+
+			"'ingress' additionally emits a rewritten Ingress for the traefik class referencing the generated middlewares, "+
+			"'ingressroute' builds an IngressRoute for every ingress")
+	cmd.PersistentFlags().BoolVarP(&opts.TrustForwardedHeaders, "trust-forwarded-headers", "", false,
+		"set when ingress-nginx runs with use-forwarded-headers, client IP based middlewares then read the client IP from X-Forwarded-For")
 	cmd.PersistentFlags().BoolVarP(&opts.ProxyBufferHeuristic, "proxy-buffer-heuristic", "", false,
 		"when enabled, the nginx ingress annotation 'proxy-buffer-size' gets heuristically mapped to Traefik buffering")
 }
@@ -14,7 +14,14 @@ type Context struct {
 	Annotations map[string]string `yaml:"annotations,omitempty" json:"annotations,omitempty"`
 	Result      *Result           `yaml:"result,omitempty" json:"result,omitempty"`
 	Options     *Options          `yaml:"options,omitempty" json:"options,omitempty"`
-	Log         *slog.Logger
+	// Defaults holds the controller defaults of the annotations the ingress does not set, the converters fall back
+	// to them through Annotation. They are reported once with the controller ConfigMap, not with every ingress.
+	Defaults map[string]string `yaml:"defaults,omitempty" json:"defaults,omitempty"`
+	// Canaries holds the canary ingresses paired with this ingress, when it is a primary.
+	Canaries []*netv1.Ingress `yaml:"canaries,omitempty" json:"canaries,omitempty"`
+	// CanaryOf holds the primary ingress of this ingress, when it is a paired canary.
+	CanaryOf *netv1.Ingress `yaml:"canary_of,omitempty" json:"canary_of,omitempty"`
+	Log      *slog.Logger
 }
 
 // New returns a new instance of Context when invoked.
@@ -24,8 +31,31 @@ func New(ingress *netv1.Ingress, result *Result, options *Options, logger *slog.
 		IngressName: ingress.Name,
 		Namespace:   ingress.Namespace,
 		Annotations: ingress.Annotations,
+		Defaults:    options.ControllerDefaultsFor(ingress.Annotations),
 		Result:      result,
 		Options:     options,
 		Log:         logger,
 	}
 }
+
+// Annotation returns the value of an annotation of the ingress, falling back to the controller defaults.
+func (ctx *Context) Annotation(name string) (string, bool) {
+	if val, ok := ctx.Annotations[name]; ok {
+		return val, true
+	}
+
+	val, ok := ctx.Defaults[name]
+
+	return val, ok
+}
+
+// isDefault reports whether the annotation is not set by the ingress but comes from the controller defaults.
+func (ctx *Context) isDefault(name string) bool {
+	if _, ok := ctx.Annotations[name]; ok {
+		return false
+	}
+
+	_, ok := ctx.Defaults[name]
+
+	return ok
+}
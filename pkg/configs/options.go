@@ -1,12 +1,90 @@
 package configs
 
+import (
+	"fmt"
+	"strings"
+
+	"github.com/nikhilsbhat/nginx-traefik-converter/pkg/converters/models"
+	"github.com/nikhilsbhat/nginx-traefik-converter/pkg/errors"
+)
+
+const (
+	// RouteModeAuto generates an IngressRoute only for ingresses that cannot be expressed otherwise,
+	// for example the ones setting backend-protocol or grpc-backend.
+	RouteModeAuto = "auto"
+
+	// RouteModeIngress generates a rewritten copy of the source Ingress for the traefik ingress class,
+	// which references the generated middlewares through traefik router annotations.
+	RouteModeIngress = "ingress"
+
+	// RouteModeIngressRoute generates an IngressRoute for every ingress, wiring up the middleware chain,
+	// entry points and TLS.
+	RouteModeIngressRoute = "ingressroute"
+)
+
+// RouteModes lists all the supported route modes.
+var RouteModes = []string{RouteModeAuto, RouteModeIngress, RouteModeIngressRoute}
+
 // Options holds the options required to run the converters.
 type Options struct {
-	ProxyBufferHeuristic bool `yaml:"proxy_buffer_heuristic,omitempty" json:"proxy_buffer_heuristic,omitempty"`
-	DisablePlugins       bool `yaml:"disable_plugins,omitempty"        json:"disable_plugins,omitempty"`
+	ProxyBufferHeuristic bool   `yaml:"proxy_buffer_heuristic,omitempty" json:"proxy_buffer_heuristic,omitempty"`
+	DisablePlugins       bool   `yaml:"disable_plugins,omitempty"        json:"disable_plugins,omitempty"`
+	RouteMode            string `yaml:"route_mode,omitempty"             json:"route_mode,omitempty"`
+	// TrustForwardedHeaders mirrors the use-forwarded-headers setting of ingress-nginx,
+	// when set the client IP is taken from the X-Forwarded-For header instead of the remote address.
+	TrustForwardedHeaders bool `yaml:"trust_forwarded_headers,omitempty" json:"trust_forwarded_headers,omitempty"`
+	// ControllerDefaults holds the annotations derived from the ingress-nginx controller ConfigMap,
+	// they apply to every ingress which does not set the annotation itself.
+	ControllerDefaults map[string]string `yaml:"controller_defaults,omitempty" json:"controller_defaults,omitempty"`
+	// ConfigMapData returns the data of a ConfigMap referenced by an annotation, such as auth-proxy-set-headers.
+	// It is nil when the converter has no access to the ConfigMaps.
+	ConfigMapData func(namespace, name string) (map[string]string, error) `yaml:"-" json:"-"`
 }
 
 // NewOptions returns new instance of Options when invoked.
 func NewOptions() *Options {
-	return &Options{}
+	return &Options{
+		RouteMode: RouteModeAuto,
+	}
+}
+
+// Validate checks the options for unsupported values.
+func (opts *Options) Validate() error {
+	for _, mode := range RouteModes {
+		if opts.RouteMode == mode {
+			return nil
+		}
+	}
+
+	return &errors.ConverterError{
+		Message: fmt.Sprintf("unsupported route mode %q, supported modes are: %s", opts.RouteMode, strings.Join(RouteModes, ", ")),
+	}
+}
+
+// ControllerDefaultsFor returns the controller defaults applying to an ingress, for the annotations it does not set.
+// As in ingress-nginx, the global auth does not apply to ingresses setting their own auth-url
+// or opting out with enable-global-auth: "false".
+func (opts *Options) ControllerDefaultsFor(annotations map[string]string) map[string]string {
+	if len(opts.ControllerDefaults) == 0 {
+		return nil
+	}
+
+	skipGlobalAuth := annotations[string(models.AuthURL)] != "" ||
+		strings.TrimSpace(annotations[string(models.EnableGlobalAuth)]) == "false"
+
+	defaults := make(map[string]string, len(opts.ControllerDefaults))
+
+	for key, value := range opts.ControllerDefaults {
+		if _, ok := annotations[key]; ok {
+			continue
+		}
+
+		if skipGlobalAuth && strings.HasPrefix(key, models.NginxAnnotationPrefix+"auth-") {
+			continue
+		}
+
+		defaults[key] = value
+	}
+
+	return defaults
 }
@@ -0,0 +1,63 @@
+package configs
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/nikhilsbhat/nginx-traefik-converter/pkg/errors"
+)
+
+const (
+	// PluginRewriteResponseHeaders is the name under which the response header rewrite plugin is referenced,
+	// used by proxy-cookie-path and proxy-redirect.
+	PluginRewriteResponseHeaders = "rewriteResponseHeaders"
+	// PluginConditionalReturn is the name under which the plugin answering requests without reaching the
+	// upstream is referenced, used by the return directives of configuration-snippet.
+	// It has no published module, the plugin has to be declared with --plugin or in the static configuration.
+	PluginConditionalReturn = "conditionalReturn"
+	// PluginDenyIP is the name under which the deny-list plugin is referenced, used by denylist-source-range.
+	PluginDenyIP = "denyip"
+
+	// PlaceholderModule and PlaceholderVersion declare the plugins without a known module in the static configuration,
+	// Traefik cannot load them until they are replaced or the plugin is declared with --plugin.
+	PlaceholderModule  = "REPLACE-WITH-PLUGIN-MODULE"
+	PlaceholderVersion = "REPLACE-WITH-PLUGIN-VERSION"
+)
+
+// Plugin is a Traefik plugin loaded from the plugin catalog.
+type Plugin struct {
+	ModuleName string `yaml:"moduleName" json:"moduleName"`
+	Version    string `yaml:"version"    json:"version"`
+}
+
+// KnownPlugins holds the pinned modules of the plugins referenced by the generated middlewares.
+// Plugins missing here have no published module and have to be declared with --plugin.
+var KnownPlugins = map[string]Plugin{
+	PluginRewriteResponseHeaders: {
+		ModuleName: "github.com/jamesmcroft/traefik-plugin-rewrite-response-headers",
+		Version:    "v1.1.2",
+	},
+	PluginDenyIP: {
+		ModuleName: "github.com/kevtainer/denyip",
+		Version:    "v1.0.0",
+	},
+}
+
+// ParsePlugin parses a plugin declaration of the form <name>=<module>@<version>.
+func ParsePlugin(val string) (string, Plugin, error) {
+	name, module, found := strings.Cut(strings.TrimSpace(val), "=")
+	if !found || name == "" {
+		return "", Plugin{}, &errors.ConverterError{
+			Message: fmt.Sprintf("invalid plugin %q, expected <name>=<module>@<version>", val),
+		}
+	}
+
+	moduleName, version, found := strings.Cut(module, "@")
+	if !found || moduleName == "" || version == "" {
+		return "", Plugin{}, &errors.ConverterError{
+			Message: fmt.Sprintf("invalid plugin %q, expected <name>=<module>@<version>", val),
+		}
+	}
+
+	return name, Plugin{ModuleName: moduleName, Version: version}, nil
+}
@@ -6,8 +6,9 @@ package configs
 // It is used in reports to indicate whether an annotation was:
 //   - fully converted,
 //   - converted with warnings,
-//   - skipped because it cannot be safely migrated, or
-//   - ignored because it is not relevant for Traefik.
+//   - skipped because it cannot be safely migrated,
+//   - ignored because it is not relevant for Traefik, or
+//   - unhandled because no converter processed it.
 
 type AnnotationStatus string
 
@@ -29,6 +30,37 @@ const (
 	// intentionally ignored because it is not applicable or has no effect
 	// in Traefik.
 	AnnotationIgnored AnnotationStatus = "ignored"
+
+	// AnnotationUnhandled indicates that the annotation is an NGINX annotation
+	// that none of the converters processed, its behavior is dropped and it
+	// requires manual migration.
+	AnnotationUnhandled AnnotationStatus = "unhandled"
+)
+
+const (
+	// RoutedByIngressRoute indicates that the ingress was converted into a Traefik IngressRoute.
+	RoutedByIngressRoute = "ingressroute"
+
+	// RoutedByIngress indicates that the ingress was converted into an Ingress of the traefik class.
+	RoutedByIngress = "ingress"
+
+	// RoutedByCanary indicates that the ingress is a canary whose routes were merged into the routes of its primary.
+	RoutedByCanary = "canary"
+
+	// RoutedByPassthrough indicates that the ingress was converted into a Traefik IngressRouteTCP passing TLS through.
+	RoutedByPassthrough = "ingressroutetcp"
+
+	// RouteModeIngressRouteTCP indicates that the tcp-services ConfigMap was converted into Traefik IngressRouteTCPs.
+	RouteModeIngressRouteTCP = "ingressroutetcp"
+
+	// RouteModeIngressRouteUDP indicates that the udp-services ConfigMap was converted into Traefik IngressRouteUDPs.
+	RouteModeIngressRouteUDP = "ingressrouteudp"
+
+	// RoutedByStaticConfig indicates that a controller ConfigMap was converted into Traefik static configuration.
+	RoutedByStaticConfig = "static-config"
+
+	// RoutedByNone indicates that only middlewares were generated and no route references them.
+	RoutedByNone = "middlewares-only"
 )
 
 // AnnotationReportEntry represents the migration result of a single
@@ -54,6 +86,10 @@ type IngressReport struct {
 	// Name is the name of the Ingress resource.
 	Name string `yaml:"name,omitempty"      json:"name,omitempty"`
 
+	// RouteMode is the kind of route generated for the Ingress, one of
+	// "ingressroute", "ingress", "canary" or "middlewares-only".
+	RouteMode string `yaml:"route_mode,omitempty" json:"route_mode,omitempty"`
+
 	// Entries is the list of per-annotation migration results.
 	Entries []AnnotationReportEntry `yaml:"entries,omitempty"   json:"entries,omitempty"`
 }
@@ -75,9 +111,19 @@ func (ctx *Context) StartIngressReport(ns, name string) {
 	}
 }
 
+// ReportRouteMode records the kind of route generated for the current Ingress.
+func (ctx *Context) ReportRouteMode(mode string) {
+	ctx.Result.IngressReport.RouteMode = mode
+}
+
 // addReport appends a new annotation report entry to the current Ingress report.
-// It is an internal helper used by the public Report* methods.
+// It is an internal helper used by the public Report* methods. The controller defaults
+// are left out, the report of the controller ConfigMap lists them once.
 func (ctx *Context) addReport(name string, status AnnotationStatus, msg string) {
+	if ctx.isDefault(name) {
+		return
+	}
+
 	ctx.Result.IngressReport.Entries = append(
 		ctx.Result.IngressReport.Entries,
 		AnnotationReportEntry{
@@ -112,3 +158,33 @@ func (ctx *Context) ReportWarning(name, msg string) {
 func (ctx *Context) ReportIgnored(name string, msg string) {
 	ctx.addReport(name, AnnotationIgnored, msg)
 }
+
+// ReportUnhandled records that the given annotation was not processed by any
+// converter and its behavior is therefore dropped.
+func (ctx *Context) ReportUnhandled(name string, msg string) {
+	ctx.addReport(name, AnnotationUnhandled, msg)
+}
+
+// IsReported reports whether the given annotation already has an entry in the
+// current Ingress report.
+func (ctx *Context) IsReported(name string) bool {
+	for _, entry := range ctx.Result.IngressReport.Entries {
+		if entry.Name == name {
+			return true
+		}
+	}
+
+	return false
+}
+
+// IsSkipped reports whether the given annotation has a skipped entry in the
+// current Ingress report.
+func (ctx *Context) IsSkipped(name string) bool {
+	for _, entry := range ctx.Result.IngressReport.Entries {
+		if entry.Name == name && entry.Status == AnnotationSkipped {
+			return true
+		}
+	}
+
+	return false
+}
@@ -2,16 +2,26 @@ package configs
 
 import (
 	traefik "github.com/traefik/traefik/v3/pkg/provider/kubernetes/crd/traefikio/v1alpha1"
+	netv1 "k8s.io/api/networking/v1"
 )
 
 // Result holds the translated configs for a nginx ingress.
 type Result struct {
-	Middlewares   []*traefik.Middleware   `yaml:"middlewares,omitempty"     json:"middlewares,omitempty"`
-	IngressRoutes []*traefik.IngressRoute `yaml:"ingress_routes,omitempty"  json:"ingress_routes,omitempty"`
-	TLSOptions    []*traefik.TLSOption    `yaml:"tls_options,omitempty"     json:"tls_options,omitempty"`
-	TLSOptionRefs map[string]string       `yaml:"tls_option_refs,omitempty" json:"tls_option_refs,omitempty"`
-	Warnings      []string                `yaml:"warnings,omitempty"        json:"warnings,omitempty"`
-	IngressReport IngressReport           `yaml:"ingress_report,omitempty"  json:"ingress_report,omitempty"`
+	Middlewares          []*traefik.Middleware          `yaml:"middlewares,omitempty"     json:"middlewares,omitempty"`
+	IngressRoutes        []*traefik.IngressRoute        `yaml:"ingress_routes,omitempty"  json:"ingress_routes,omitempty"`
+	IngressRoutesTCP     []*traefik.IngressRouteTCP     `yaml:"ingress_routes_tcp,omitempty" json:"ingress_routes_tcp,omitempty"`
+	IngressRoutesUDP     []*traefik.IngressRouteUDP     `yaml:"ingress_routes_udp,omitempty" json:"ingress_routes_udp,omitempty"`
+	ServersTransportsTCP []*traefik.ServersTransportTCP `yaml:"servers_transports_tcp,omitempty" json:"servers_transports_tcp,omitempty"`
+	TraefikServices      []*traefik.TraefikService      `yaml:"traefik_services,omitempty" json:"traefik_services,omitempty"`
+	TLSOptions           []*traefik.TLSOption           `yaml:"tls_options,omitempty"     json:"tls_options,omitempty"`
+	ServersTransports    []*traefik.ServersTransport    `yaml:"servers_transports,omitempty" json:"servers_transports,omitempty"`
+	Ingresses            []*netv1.Ingress               `yaml:"ingresses,omitempty"       json:"ingresses,omitempty"`
+	TLSOptionRefs        map[string]string              `yaml:"tls_option_refs,omitempty" json:"tls_option_refs,omitempty"`
+	ServersTransportRefs map[string]string              `yaml:"servers_transport_refs,omitempty" json:"servers_transport_refs,omitempty"`
+	EntryPoints          map[string]*EntryPoint         `yaml:"entry_points,omitempty" json:"entry_points,omitempty"`
+	Tracing              *Tracing                       `yaml:"tracing,omitempty" json:"tracing,omitempty"`
+	Warnings             []string                       `yaml:"warnings,omitempty"        json:"warnings,omitempty"`
+	IngressReport        IngressReport                  `yaml:"ingress_report,omitempty"  json:"ingress_report,omitempty"`
 	// Report        GlobalReport      `yaml:"report,omitempty"         json:"report,omitempty"`
 }
 
@@ -0,0 +1,224 @@
+package configs
+
+import (
+	"maps"
+	"slices"
+	"strings"
+)
+
+const (
+	// EntryPointWeb is the entry point serving the plain HTTP routes.
+	EntryPointWeb = "web"
+	// EntryPointWebSecure is the entry point serving the TLS routes.
+	EntryPointWebSecure = "websecure"
+
+	// RouterEntryPointsAnnotation lists the entry points of the Ingresses generated for the traefik class.
+	RouterEntryPointsAnnotation = "traefik.ingress.kubernetes.io/router.entrypoints"
+)
+
+// StaticConfig holds the parts of the Traefik static (install) configuration
+// required by the converted resources.
+type StaticConfig struct {
+	EntryPoints  map[string]*EntryPoint `yaml:"entryPoints,omitempty"  json:"entryPoints,omitempty"`
+	Tracing      *Tracing               `yaml:"tracing,omitempty"      json:"tracing,omitempty"`
+	Experimental *Experimental          `yaml:"experimental,omitempty" json:"experimental,omitempty"`
+	// MissingPlugins lists the plugins referenced by the middlewares without a known module,
+	// they are declared with a placeholder module to be replaced before Traefik can load them.
+	MissingPlugins []string `yaml:"-" json:"-"`
+	plugins        map[string]Plugin
+}
+
+// Experimental holds the experimental features of Traefik, the plugins are declared there.
+type Experimental struct {
+	Plugins map[string]Plugin `yaml:"plugins,omitempty" json:"plugins,omitempty"`
+}
+
+// EntryPoint holds the static configuration of a Traefik entry point.
+type EntryPoint struct {
+	Address          string                   `yaml:"address"                    json:"address"`
+	ProxyProtocol    *EntryPointProxyProtocol `yaml:"proxyProtocol,omitempty"    json:"proxyProtocol,omitempty"`
+	ForwardedHeaders *ForwardedHeaders        `yaml:"forwardedHeaders,omitempty" json:"forwardedHeaders,omitempty"`
+	HTTP             *EntryPointHTTP          `yaml:"http,omitempty"             json:"http,omitempty"`
+}
+
+// EntryPointProxyProtocol holds the PROXY protocol settings of an entry point.
+type EntryPointProxyProtocol struct {
+	Insecure   bool     `yaml:"insecure,omitempty"   json:"insecure,omitempty"`
+	TrustedIPs []string `yaml:"trustedIPs,omitempty" json:"trustedIPs,omitempty"`
+}
+
+// ForwardedHeaders holds the sources trusted to set the X-Forwarded-* headers on an entry point.
+type ForwardedHeaders struct {
+	Insecure   bool     `yaml:"insecure,omitempty"   json:"insecure,omitempty"`
+	TrustedIPs []string `yaml:"trustedIPs,omitempty" json:"trustedIPs,omitempty"`
+}
+
+// EntryPointHTTP holds the HTTP settings applied to all the routers of an entry point.
+type EntryPointHTTP struct {
+	// Middlewares are applied to every router of the entry point, referenced as <namespace>-<name>@kubernetescrd.
+	Middlewares    []string `yaml:"middlewares,omitempty"    json:"middlewares,omitempty"`
+	MaxHeaderBytes int64    `yaml:"maxHeaderBytes,omitempty" json:"maxHeaderBytes,omitempty"`
+}
+
+// Tracing holds the tracing settings of Traefik.
+type Tracing struct {
+	ServiceName string       `yaml:"serviceName,omitempty" json:"serviceName,omitempty"`
+	SampleRate  *float64     `yaml:"sampleRate,omitempty"  json:"sampleRate,omitempty"`
+	OTLP        *TracingOTLP `yaml:"otlp,omitempty"        json:"otlp,omitempty"`
+}
+
+// TracingOTLP holds the OpenTelemetry exporter of the traces.
+type TracingOTLP struct {
+	GRPC *TracingOTLPGRPC `yaml:"grpc,omitempty" json:"grpc,omitempty"`
+}
+
+// TracingOTLPGRPC holds the gRPC settings of the OpenTelemetry exporter.
+// Traefik sends the traces to localhost:4317 when no endpoint is set.
+type TracingOTLPGRPC struct {
+	Endpoint string `yaml:"endpoint,omitempty" json:"endpoint,omitempty"`
+	Insecure bool   `yaml:"insecure,omitempty" json:"insecure,omitempty"`
+}
+
+// NewStaticConfig returns new instance of StaticConfig, the given plugins complete or override the KnownPlugins.
+func NewStaticConfig(plugins map[string]Plugin) *StaticConfig {
+	known := maps.Clone(KnownPlugins)
+	maps.Copy(known, plugins)
+
+	return &StaticConfig{
+		EntryPoints: make(map[string]*EntryPoint),
+		plugins:     known,
+	}
+}
+
+// DefaultEntryPoint returns the web or websecure entry point listening on the standard HTTP or HTTPS port.
+func DefaultEntryPoint(name string) *EntryPoint {
+	if name == EntryPointWebSecure {
+		return &EntryPoint{Address: ":443"}
+	}
+
+	return &EntryPoint{Address: ":80"}
+}
+
+// Merge adds the entry points and tracing declared by the result to the static configuration, along with the
+// entry points its routes are served on and the plugins its middlewares use.
+// Settings of an entry point declared by several results are combined, the largest maxHeaderBytes wins.
+func (cfg *StaticConfig) Merge(res Result) {
+	for name, entryPoint := range res.EntryPoints {
+		cfg.entryPoint(name).merge(entryPoint)
+	}
+
+	for _, name := range routeEntryPoints(res) {
+		if name == EntryPointWeb || name == EntryPointWebSecure {
+			cfg.entryPoint(name).merge(DefaultEntryPoint(name))
+		}
+	}
+
+	for _, middleware := range res.Middlewares {
+		for _, name := range slices.Sorted(maps.Keys(middleware.Spec.Plugin)) {
+			cfg.addPlugin(name)
+		}
+	}
+
+	// A tracing configuration with an endpoint, as derived from the controller ConfigMap, wins over the default one.
+	if res.Tracing != nil && (cfg.Tracing == nil || !cfg.Tracing.hasEndpoint()) {
+		cfg.Tracing = res.Tracing
+	}
+}
+
+// IsEmpty reports whether the static configuration holds no setting.
+func (cfg *StaticConfig) IsEmpty() bool {
+	return len(cfg.EntryPoints) == 0 && cfg.Tracing == nil && cfg.Experimental == nil
+}
+
+func (cfg *StaticConfig) entryPoint(name string) *EntryPoint {
+	entryPoint, ok := cfg.EntryPoints[name]
+	if !ok {
+		entryPoint = &EntryPoint{}
+		cfg.EntryPoints[name] = entryPoint
+	}
+
+	return entryPoint
+}
+
+// addPlugin declares the plugin with its pinned module. A plugin with an unknown module is recorded as missing
+// and declared with a placeholder, which keeps the entry visible where the module has to be filled in.
+func (cfg *StaticConfig) addPlugin(name string) {
+	plugin, ok := cfg.plugins[name]
+	if !ok {
+		if !slices.Contains(cfg.MissingPlugins, name) {
+			cfg.MissingPlugins = append(cfg.MissingPlugins, name)
+		}
+
+		plugin = Plugin{ModuleName: PlaceholderModule, Version: PlaceholderVersion}
+	}
+
+	if cfg.Experimental == nil {
+		cfg.Experimental = &Experimental{Plugins: make(map[string]Plugin)}
+	}
+
+	cfg.Experimental.Plugins[name] = plugin
+}
+
+// routeEntryPoints returns the entry points the routes of the result are served on.
+func routeEntryPoints(res Result) []string {
+	names := make([]string, 0)
+
+	for _, ingressRoute := range res.IngressRoutes {
+		names = append(names, ingressRoute.Spec.EntryPoints...)
+	}
+
+	for _, ingressRoute := range res.IngressRoutesTCP {
+		names = append(names, ingressRoute.Spec.EntryPoints...)
+	}
+
+	for _, ingressRoute := range res.IngressRoutesUDP {
+		names = append(names, ingressRoute.Spec.EntryPoints...)
+	}
+
+	for _, ingress := range res.Ingresses {
+		for _, name := range strings.Split(ingress.Annotations[RouterEntryPointsAnnotation], ",") {
+			if name = strings.TrimSpace(name); name != "" {
+				names = append(names, name)
+			}
+		}
+	}
+
+	return names
+}
+
+func (entryPoint *EntryPoint) merge(other *EntryPoint) {
+	if entryPoint.Address == "" {
+		entryPoint.Address = other.Address
+	}
+
+	if entryPoint.ProxyProtocol == nil {
+		entryPoint.ProxyProtocol = other.ProxyProtocol
+	}
+
+	if entryPoint.ForwardedHeaders == nil {
+		entryPoint.ForwardedHeaders = other.ForwardedHeaders
+	}
+
+	if other.HTTP == nil {
+		return
+	}
+
+	merged := &EntryPointHTTP{}
+	if entryPoint.HTTP != nil {
+		merged.Middlewares = slices.Clone(entryPoint.HTTP.Middlewares)
+		merged.MaxHeaderBytes = entryPoint.HTTP.MaxHeaderBytes
+	}
+
+	for _, middleware := range other.HTTP.Middlewares {
+		if !slices.Contains(merged.Middlewares, middleware) {
+			merged.Middlewares = append(merged.Middlewares, middleware)
+		}
+	}
+
+	merged.MaxHeaderBytes = max(merged.MaxHeaderBytes, other.HTTP.MaxHeaderBytes)
+	entryPoint.HTTP = merged
+}
+
+func (tracing *Tracing) hasEndpoint() bool {
+	return tracing.OTLP != nil && tracing.OTLP.GRPC != nil && tracing.OTLP.GRPC.Endpoint != ""
+}
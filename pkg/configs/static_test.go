@@ -0,0 +1,129 @@
+package configs_test
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/nikhilsbhat/nginx-traefik-converter/pkg/configs"
+	traefik "github.com/traefik/traefik/v3/pkg/provider/kubernetes/crd/traefikio/v1alpha1"
+	netv1 "k8s.io/api/networking/v1"
+	apiextv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func pluginMiddleware(name string) *traefik.Middleware {
+	return &traefik.Middleware{
+		Spec: traefik.MiddlewareSpec{
+			Plugin: map[string]apiextv1.JSON{name: {Raw: []byte("{}")}},
+		},
+	}
+}
+
+func TestStaticConfigMergeEntryPoints(t *testing.T) {
+	static := configs.NewStaticConfig(nil)
+
+	static.Merge(configs.Result{
+		IngressRoutes: []*traefik.IngressRoute{
+			{Spec: traefik.IngressRouteSpec{EntryPoints: []string{configs.EntryPointWebSecure}}},
+		},
+		Ingresses: []*netv1.Ingress{
+			{ObjectMeta: metav1.ObjectMeta{Annotations: map[string]string{configs.RouterEntryPointsAnnotation: "web"}}},
+		},
+	})
+
+	static.Merge(configs.Result{
+		EntryPoints: map[string]*configs.EntryPoint{
+			configs.EntryPointWeb: {HTTP: &configs.EntryPointHTTP{MaxHeaderBytes: 1024, Middlewares: []string{"a"}}},
+			"tcp-5432":            {Address: ":5432/tcp"},
+		},
+	})
+
+	static.Merge(configs.Result{
+		EntryPoints: map[string]*configs.EntryPoint{
+			configs.EntryPointWeb: {HTTP: &configs.EntryPointHTTP{MaxHeaderBytes: 4096, Middlewares: []string{"a", "b"}}},
+		},
+	})
+
+	if len(static.EntryPoints) != 3 {
+		t.Fatalf("entry points = %v, want web, websecure and tcp-5432", static.EntryPoints)
+	}
+
+	web := static.EntryPoints[configs.EntryPointWeb]
+	if web.Address != ":80" || web.HTTP.MaxHeaderBytes != 4096 || !slices.Equal(web.HTTP.Middlewares, []string{"a", "b"}) {
+		t.Errorf("web = %+v, http %+v", web, web.HTTP)
+	}
+
+	if websecure := static.EntryPoints[configs.EntryPointWebSecure]; websecure.Address != ":443" {
+		t.Errorf("websecure address = %q, want :443", websecure.Address)
+	}
+
+	if static.IsEmpty() {
+		t.Error("IsEmpty() = true, want false")
+	}
+}
+
+func TestStaticConfigMergePlugins(t *testing.T) {
+	static := configs.NewStaticConfig(map[string]configs.Plugin{
+		configs.PluginDenyIP: {ModuleName: "example.com/denyip", Version: "v2.0.0"},
+	})
+
+	static.Merge(configs.Result{
+		Middlewares: []*traefik.Middleware{
+			pluginMiddleware(configs.PluginDenyIP),
+			pluginMiddleware(configs.PluginRewriteResponseHeaders),
+			pluginMiddleware(configs.PluginConditionalReturn),
+			pluginMiddleware(configs.PluginConditionalReturn),
+		},
+	})
+
+	if static.Experimental == nil {
+		t.Fatal("no plugin was declared")
+	}
+
+	plugins := static.Experimental.Plugins
+
+	// the plugins passed with --plugin override the pinned ones
+	if plugins[configs.PluginDenyIP].ModuleName != "example.com/denyip" {
+		t.Errorf("denyip = %+v, want the declared module", plugins[configs.PluginDenyIP])
+	}
+
+	if plugins[configs.PluginRewriteResponseHeaders] != configs.KnownPlugins[configs.PluginRewriteResponseHeaders] {
+		t.Errorf("rewriteResponseHeaders = %+v, want the pinned module", plugins[configs.PluginRewriteResponseHeaders])
+	}
+
+	// conditionalReturn has no known module, its entry is a placeholder to fill in
+	if plugin := plugins[configs.PluginConditionalReturn]; plugin.ModuleName != configs.PlaceholderModule ||
+		plugin.Version != configs.PlaceholderVersion {
+		t.Errorf("conditionalReturn = %+v, want the placeholder module", plugin)
+	}
+
+	if !slices.Equal(static.MissingPlugins, []string{configs.PluginConditionalReturn}) {
+		t.Errorf("missing plugins = %v, want [conditionalReturn]", static.MissingPlugins)
+	}
+}
+
+func TestStaticConfigEmpty(t *testing.T) {
+	static := configs.NewStaticConfig(nil)
+	static.Merge(configs.Result{})
+
+	if !static.IsEmpty() {
+		t.Errorf("IsEmpty() = false for %+v", static)
+	}
+}
+
+func TestParsePlugin(t *testing.T) {
+	name, plugin, err := configs.ParsePlugin("conditionalReturn=example.com/return@v1.2.3")
+	if err != nil {
+		t.Fatalf("ParsePlugin() error = %v", err)
+	}
+
+	if name != configs.PluginConditionalReturn || plugin.ModuleName != "example.com/return" || plugin.Version != "v1.2.3" {
+		t.Errorf("ParsePlugin() = %s, %+v", name, plugin)
+	}
+
+	for _, invalid := range []string{"", "example.com/return@v1", "conditionalReturn=example.com/return"} {
+		if _, _, err = configs.ParsePlugin(invalid); err == nil {
+			t.Errorf("ParsePlugin(%q) expected an error", invalid)
+		}
+	}
+}
@@ -0,0 +1,87 @@
+package convert
+
+import (
+	"github.com/nikhilsbhat/nginx-traefik-converter/pkg/configs"
+	"github.com/nikhilsbhat/nginx-traefik-converter/pkg/converters/ingressroute"
+	netv1 "k8s.io/api/networking/v1"
+)
+
+// CanaryPairs holds the ingress-nginx canary ingresses paired with their primary ingresses.
+// A canary pairs with the non-canary ingress of the same namespace serving the same host and path.
+type CanaryPairs struct {
+	canaries  map[string][]*netv1.Ingress
+	primaries map[string]*netv1.Ingress
+}
+
+// PairCanaries runs a pass over all the listed ingresses and pairs the canaries with their primaries.
+// It has to run before the ingresses are converted, as canaries cannot be expressed per ingress.
+func PairCanaries(ingresses []netv1.Ingress) *CanaryPairs {
+	pairs := &CanaryPairs{
+		canaries:  make(map[string][]*netv1.Ingress),
+		primaries: make(map[string]*netv1.Ingress),
+	}
+
+	backends := make(map[string]*netv1.Ingress)
+
+	for index := range ingresses {
+		ing := &ingresses[index]
+		if ingressroute.IsCanary(ing.Annotations) {
+			continue
+		}
+
+		for _, key := range backendKeys(ing) {
+			if _, exists := backends[key]; !exists {
+				backends[key] = ing
+			}
+		}
+	}
+
+	for index := range ingresses {
+		canary := &ingresses[index]
+		if !ingressroute.IsCanary(canary.Annotations) {
+			continue
+		}
+
+		for _, key := range backendKeys(canary) {
+			primary, ok := backends[key]
+			if !ok {
+				continue
+			}
+
+			pairs.primaries[ingressKey(canary)] = primary
+			pairs.canaries[ingressKey(primary)] = append(pairs.canaries[ingressKey(primary)], canary)
+
+			break
+		}
+	}
+
+	return pairs
+}
+
+// Attach sets the canaries or the primary paired with the ingress of the context.
+func (pairs *CanaryPairs) Attach(ctx *configs.Context) {
+	key := ingressKey(ctx.Ingress)
+
+	ctx.Canaries = pairs.canaries[key]
+	ctx.CanaryOf = pairs.primaries[key]
+}
+
+func backendKeys(ing *netv1.Ingress) []string {
+	keys := make([]string, 0)
+
+	for _, rule := range ing.Spec.Rules {
+		if rule.HTTP == nil {
+			continue
+		}
+
+		for _, path := range rule.HTTP.Paths {
+			keys = append(keys, ing.Namespace+"|"+rule.Host+"|"+path.Path)
+		}
+	}
+
+	return keys
+}
+
+func ingressKey(ing *netv1.Ingress) string {
+	return ing.Namespace + "/" + ing.Name
+}
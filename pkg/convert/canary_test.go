@@ -0,0 +1,167 @@
+package convert_test
+
+import (
+	"io"
+	"log/slog"
+	"strings"
+	"testing"
+
+	"github.com/nikhilsbhat/nginx-traefik-converter/pkg/configs"
+	"github.com/nikhilsbhat/nginx-traefik-converter/pkg/convert"
+	netv1 "k8s.io/api/networking/v1"
+)
+
+// newCanary returns a canary ingress serving the host and path of newIngress with the app-canary Service.
+func newCanary(name string, annotations map[string]string) *netv1.Ingress {
+	canary := newIngress(name, annotations)
+	canary.Annotations[annotationPrefix+"canary"] = "true"
+	canary.Spec.Rules[0].HTTP.Paths[0].Backend.Service.Name = "app-canary"
+
+	return canary
+}
+
+// runConvertAll pairs the canaries of the ingresses and converts all of them as the convert command does.
+func runConvertAll(t *testing.T, ingresses []netv1.Ingress, opts *configs.Options) map[string]*configs.Result {
+	t.Helper()
+
+	canaries := convert.PairCanaries(ingresses)
+	results := make(map[string]*configs.Result, len(ingresses))
+
+	for index := range ingresses {
+		ing := &ingresses[index]
+
+		res := configs.NewResult()
+		ctx := configs.New(ing, res, opts, slog.New(slog.NewTextHandler(io.Discard, nil)))
+		canaries.Attach(ctx)
+		ctx.StartIngressReport(ing.Namespace, ing.Name)
+
+		if err := convert.Run(*ctx); err != nil {
+			t.Fatalf("Run(%s) error = %v", ing.Name, err)
+		}
+
+		results[ing.Name] = res
+	}
+
+	return results
+}
+
+func TestRunCanaryWeight(t *testing.T) {
+	results := runConvertAll(t, []netv1.Ingress{
+		*newIngress("app", nil),
+		*newCanary("app-canary", map[string]string{"canary-weight": "20"}),
+	}, configs.NewOptions())
+
+	primary, canary := results["app"], results["app-canary"]
+
+	if canary.IngressReport.RouteMode != configs.RoutedByCanary {
+		t.Errorf("canary route mode = %q, want %q", canary.IngressReport.RouteMode, configs.RoutedByCanary)
+	}
+
+	if len(canary.IngressRoutes) != 0 || len(canary.TraefikServices) != 0 {
+		t.Error("the canary ingress must not produce resources of its own")
+	}
+
+	if len(primary.IngressRoutes) != 1 || len(primary.TraefikServices) != 1 {
+		t.Fatalf("primary generated %d IngressRoutes and %d TraefikServices, want 1 and 1",
+			len(primary.IngressRoutes), len(primary.TraefikServices))
+	}
+
+	weighted := primary.TraefikServices[0]
+	if weighted.Name != "app-canary-app-canary-80" {
+		t.Errorf("TraefikService name = %q", weighted.Name)
+	}
+
+	services := weighted.Spec.Weighted.Services
+	if len(services) != 2 || services[0].Name != "app" || services[1].Name != "app-canary" {
+		t.Fatalf("weighted services = %+v, want app and app-canary", services)
+	}
+
+	if *services[0].Weight != 80 || *services[1].Weight != 20 {
+		t.Errorf("weights = %d/%d, want 80/20", *services[0].Weight, *services[1].Weight)
+	}
+
+	route := primary.IngressRoutes[0].Spec.Routes[0]
+	if route.Services[0].Kind != "TraefikService" || route.Services[0].Name != weighted.Name {
+		t.Errorf("route service = %+v, want a reference to %s", route.Services[0], weighted.Name)
+	}
+}
+
+func TestRunCanaryMatchers(t *testing.T) {
+	tests := []struct {
+		name        string
+		annotations map[string]string
+		want        []string
+	}{
+		{
+			name:        "header value",
+			annotations: map[string]string{"canary-by-header": "X-Canary", "canary-by-header-value": "yes"},
+			want:        []string{"Header(`X-Canary`, `yes`) -> app-canary"},
+		},
+		{
+			name:        "header pattern",
+			annotations: map[string]string{"canary-by-header": "X-Canary", "canary-by-header-pattern": "^(a|b)$"},
+			want:        []string{"HeaderRegexp(`X-Canary`, `^(a|b)$`) -> app-canary"},
+		},
+		{
+			name:        "header with weight pins never to the primary",
+			annotations: map[string]string{"canary-by-header": "X-Canary", "canary-weight": "10"},
+			want: []string{
+				"Header(`X-Canary`, `always`) -> app-canary",
+				"Header(`X-Canary`, `never`) -> app",
+			},
+		},
+		{
+			name:        "cookie",
+			annotations: map[string]string{"canary-by-cookie": "canary"},
+			want:        []string{"HeaderRegexp(`Cookie`, `(^|;\\s*)canary=always(;|$)`) -> app-canary"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			results := runConvertAll(t, []netv1.Ingress{
+				*newIngress("app", nil),
+				*newCanary("app-canary", tt.annotations),
+			}, configs.NewOptions())
+
+			routes := results["app"].IngressRoutes[0].Spec.Routes
+			base := routes[len(routes)-1]
+
+			got := make([]string, 0)
+
+			for _, route := range routes[:len(routes)-1] {
+				matcher, _ := strings.CutPrefix(route.Match, base.Match+" && ")
+				got = append(got, matcher+" -> "+route.Services[0].Name)
+
+				if route.Priority <= len(base.Match) {
+					t.Errorf("canary route priority %d does not take precedence over the base route", route.Priority)
+				}
+			}
+
+			if strings.Join(got, "\n") != strings.Join(tt.want, "\n") {
+				t.Errorf("canary routes =\n%s\nwant\n%s", strings.Join(got, "\n"), strings.Join(tt.want, "\n"))
+			}
+		})
+	}
+}
+
+func TestRunCanaryWithoutPrimary(t *testing.T) {
+	other := newIngress("other", nil)
+	other.Spec.Rules[0].Host = "other.example.com"
+
+	results := runConvertAll(t, []netv1.Ingress{
+		*other,
+		*newCanary("app-canary", map[string]string{"canary-weight": "20"}),
+	}, configs.NewOptions())
+
+	for _, annotation := range []string{"canary", "canary-weight"} {
+		entries := reportEntries(results["app-canary"], annotation)
+		if len(entries) != 1 || entries[0].Status != configs.AnnotationSkipped {
+			t.Errorf("%s report = %+v, want a single skipped entry", annotation, entries)
+		}
+	}
+
+	if len(results["other"].TraefikServices) != 0 {
+		t.Error("a canary must only pair with the ingress serving its host and path")
+	}
+}
@@ -10,7 +10,9 @@ import (
 type middlewareCategory int
 
 const (
-	catShortCircuit     middlewareCategory = iota // A: return-status plugin (future)
+	catErrorPages       middlewareCategory = iota // 0: custom error pages, wrapping every other middleware
+	catShortCircuit                               // A: return-status plugin (future)
+	catAccessControl                              // A2: IP allow/deny lists
 	catResponseHeaders                            // B: CORS, headers, cookie rewrites, upstream-vhost
 	catAuth                                       // C: BasicAuth, ForwardAuth
 	catRequestTransform                           // D: rewrite, redirect, bodysize, proxy-redirect
@@ -21,10 +23,19 @@ func classifyMiddleware(middleware *traefik.Middleware) middlewareCategory {
 	name := strings.ToLower(middleware.GetName())
 
 	switch {
+	// 0: error pages must see the statuses returned by the other middlewares too
+	case middleware.Spec.Errors != nil:
+		return catErrorPages
+
 	// A: short-circuit responders (future plugin)
 	case strings.Contains(name, "conditional-return"):
 		return catShortCircuit
 
+	// A2: client IP filtering, evaluated before any other processing as NGINX does in its access phase
+	case middleware.Spec.IPAllowList != nil,
+		strings.Contains(name, "ip-denylist"):
+		return catAccessControl
+
 	// B: response header injectors
 	case middleware.Spec.Headers != nil,
 		strings.Contains(name, "cors"),
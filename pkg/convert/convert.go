@@ -5,12 +5,25 @@ import (
 	"github.com/nikhilsbhat/nginx-traefik-converter/pkg/converters/ingressroute"
 	"github.com/nikhilsbhat/nginx-traefik-converter/pkg/converters/middleware"
 	"github.com/nikhilsbhat/nginx-traefik-converter/pkg/converters/tls"
+	"github.com/nikhilsbhat/nginx-traefik-converter/pkg/converters/transport"
 )
 
 // Run processes ingress annotations using the available converters.
 // It is the core function responsible for converting NGINX Ingress
 // annotations into their Traefik equivalents.
 func Run(ctx configs.Context) error {
+	if ingressroute.IsCanary(ctx.Annotations) {
+		ingressroute.ReportCanary(ctx)
+
+		return nil
+	}
+
+	if ingressroute.IsPassthrough(ctx.Annotations) {
+		ingressroute.BuildPassthrough(ctx)
+
+		return nil
+	}
+
 	if err := middleware.CORS(ctx); err != nil {
 		return err
 	}
@@ -19,6 +32,7 @@ func Run(ctx configs.Context) error {
 		return err
 	}
 
+	middleware.SourceRange(ctx)
 	middleware.UpstreamVHost(ctx)
 	middleware.BasicAuth(ctx)
 
@@ -28,6 +42,7 @@ func Run(ctx configs.Context) error {
 
 	middleware.RewriteTargets(ctx)
 	middleware.SSLRedirect(ctx)
+	middleware.Redirects(ctx)
 
 	if err := middleware.RateLimit(ctx); err != nil {
 		return err
@@ -47,16 +62,50 @@ func Run(ctx configs.Context) error {
 	middleware.ExtraAnnotations(ctx)
 	middleware.ProxyBuffering(ctx)
 	middleware.HandleAuthURL(ctx)
+	middleware.CustomHTTPErrors(ctx)
 
 	sortMiddlewares(ctx.Result.Middlewares)
 
-	if ingressroute.NeedsIngressRoute(ctx.Annotations) {
+	// TLS options and servers transports are resolved before the routes, so that the routes can reference them.
+	tls.HandleAuthTLSVerifyClient(ctx)
+	transport.HandleServersTransport(ctx)
+
+	buildRoutes(ctx)
+	reportUnhandled(ctx)
+
+	return nil
+}
+
+// buildRoutes generates the routes for the ingress as per the selected route mode
+// and records the chosen mode in the ingress report.
+func buildRoutes(ctx configs.Context) {
+	switch {
+	case ctx.Options.RouteMode == configs.RouteModeIngressRoute,
+		ingressroute.NeedsIngressRoute(ctx.Annotations),
+		len(ctx.Canaries) > 0:
 		if err := ingressroute.BuildIngressRoute(ctx); err != nil {
 			ctx.Result.Warnings = append(ctx.Result.Warnings, err.Error())
 		}
+
+		if len(ctx.Result.IngressRoutes) > 0 {
+			ctx.ReportRouteMode(configs.RoutedByIngressRoute)
+
+			return
+		}
+
+	case ctx.Options.RouteMode == configs.RouteModeIngress:
+		ingressroute.BuildIngress(ctx)
+		ctx.ReportRouteMode(configs.RoutedByIngress)
+
+		return
 	}
 
-	tls.HandleAuthTLSVerifyClient(ctx)
+	ctx.ReportRouteMode(configs.RoutedByNone)
 
-	return nil
+	if len(ctx.Result.Middlewares) > 0 || len(ctx.Result.ServersTransports) > 0 {
+		ctx.Result.Warnings = append(ctx.Result.Warnings,
+			"the generated middlewares and servers transports are not referenced by any route; "+
+				"use --route-mode ingressroute or --route-mode ingress to attach them",
+		)
+	}
 }
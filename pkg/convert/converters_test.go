@@ -1,6 +1,183 @@
 package convert_test
 
-// func Test_bodySize(t *testing.T) {
-//	t.Run("", func(t *testing.T) {
-//	})
-// }
+import (
+	"io"
+	"log/slog"
+	"slices"
+	"testing"
+
+	"github.com/nikhilsbhat/nginx-traefik-converter/pkg/configs"
+	"github.com/nikhilsbhat/nginx-traefik-converter/pkg/convert"
+	netv1 "k8s.io/api/networking/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const annotationPrefix = "nginx.ingress.kubernetes.io/"
+
+// newIngress returns an ingress routing app.example.com/ to the app Service with the given nginx annotations,
+// whose keys are given without the nginx.ingress.kubernetes.io/ prefix.
+func newIngress(name string, annotations map[string]string) *netv1.Ingress {
+	pathType := netv1.PathTypePrefix
+
+	prefixed := make(map[string]string, len(annotations))
+	for key, value := range annotations {
+		prefixed[annotationPrefix+key] = value
+	}
+
+	return &netv1.Ingress{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        name,
+			Namespace:   "default",
+			Annotations: prefixed,
+		},
+		Spec: netv1.IngressSpec{
+			Rules: []netv1.IngressRule{
+				{
+					Host: "app.example.com",
+					IngressRuleValue: netv1.IngressRuleValue{
+						HTTP: &netv1.HTTPIngressRuleValue{
+							Paths: []netv1.HTTPIngressPath{
+								{
+									Path:     "/",
+									PathType: &pathType,
+									Backend: netv1.IngressBackend{
+										Service: &netv1.IngressServiceBackend{
+											Name: "app",
+											Port: netv1.ServiceBackendPort{Number: 80},
+										},
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+// runConvert converts the ingress as the convert command does.
+func runConvert(t *testing.T, ing *netv1.Ingress, opts *configs.Options) *configs.Result {
+	t.Helper()
+
+	res := configs.NewResult()
+	ctx := configs.New(ing, res, opts, slog.New(slog.NewTextHandler(io.Discard, nil)))
+	ctx.StartIngressReport(ing.Namespace, ing.Name)
+
+	if err := convert.Run(*ctx); err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+
+	return res
+}
+
+func ingressRouteOptions() *configs.Options {
+	opts := configs.NewOptions()
+	opts.RouteMode = configs.RouteModeIngressRoute
+
+	return opts
+}
+
+// routeMiddlewares returns the middlewares referenced by the routes of the first IngressRoute.
+func routeMiddlewares(t *testing.T, res *configs.Result) []string {
+	t.Helper()
+
+	if len(res.IngressRoutes) == 0 || len(res.IngressRoutes[0].Spec.Routes) == 0 {
+		t.Fatal("no IngressRoute was generated")
+	}
+
+	names := make([]string, 0)
+	for _, ref := range res.IngressRoutes[0].Spec.Routes[0].Middlewares {
+		names = append(names, ref.Name)
+	}
+
+	return names
+}
+
+// reportEntries returns the report entries of the annotation, given without the nginx prefix.
+func reportEntries(res *configs.Result, annotation string) []configs.AnnotationReportEntry {
+	entries := make([]configs.AnnotationReportEntry, 0)
+
+	for _, entry := range res.IngressReport.Entries {
+		if entry.Name == annotationPrefix+annotation {
+			entries = append(entries, entry)
+		}
+	}
+
+	return entries
+}
+
+func TestRunMiddlewareOrder(t *testing.T) {
+	tests := []struct {
+		name        string
+		ingressName string
+		annotations map[string]string
+		want        []string
+	}{
+		{
+			name:        "access control runs before CORS and rate limiting",
+			ingressName: "app",
+			annotations: map[string]string{
+				"enable-cors":             "true",
+				"whitelist-source-range":  "10.0.0.0/8",
+				"limit-rps":               "10",
+				"custom-http-errors":      "404,503",
+				"default-backend":         "error-pages",
+				"configuration-snippet":   "add_header X-Frame-Options DENY;",
+				"auth-type":               "basic",
+				"auth-secret":             "basic-auth",
+				"permanent-redirect":      "https://example.com",
+				"permanent-redirect-code": "301",
+			},
+			want: []string{
+				"app-custom-errors",
+				"app-ip-allowlist",
+				"app-cors",
+				"app-configuration-snippet",
+				"app-basicauth",
+				"app-url-redirect",
+				"app-ratelimit",
+			},
+		},
+		{
+			name:        "error pages wrap every middleware and no headers middleware is dropped",
+			ingressName: "headers",
+			annotations: map[string]string{
+				"custom-http-errors":    "502",
+				"default-backend":       "error-pages",
+				"enable-cors":           "true",
+				"configuration-snippet": "add_header X-Frame-Options DENY;",
+				"auth-url":              "https://auth.example.com/verify",
+				"auth-request-redirect": "https://app.example.com/login",
+			},
+			want: []string{
+				"headers-custom-errors",
+				"headers-cors",
+				"headers-configuration-snippet",
+				"headers-auth-request-redirect",
+				"headers-auth-url",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			res := runConvert(t, newIngress(tt.ingressName, tt.annotations), ingressRouteOptions())
+
+			got := routeMiddlewares(t, res)
+
+			generated := make([]string, 0, len(res.Middlewares))
+			for _, middleware := range res.Middlewares {
+				generated = append(generated, middleware.Name)
+			}
+
+			if !slices.Equal(got, generated) {
+				t.Errorf("route middlewares = %v, want the generated order %v", got, generated)
+			}
+
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("route middlewares = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,273 @@
+package convert_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/nikhilsbhat/nginx-traefik-converter/pkg/configs"
+	netv1 "k8s.io/api/networking/v1"
+)
+
+func ingressOptions() *configs.Options {
+	opts := configs.NewOptions()
+	opts.RouteMode = configs.RouteModeIngress
+
+	return opts
+}
+
+func TestRunIngressMode(t *testing.T) {
+	ing := newIngress("app", map[string]string{
+		"enable-cors": "true",
+		"limit-rps":   "10",
+	})
+	ing.Annotations["kubernetes.io/ingress.class"] = "nginx"
+	ing.Annotations["team"] = "payments"
+
+	res := runConvert(t, ing, ingressOptions())
+
+	if len(res.IngressRoutes) != 0 {
+		t.Errorf("no IngressRoute should be generated in ingress mode, got %d", len(res.IngressRoutes))
+	}
+
+	if len(res.Ingresses) != 1 {
+		t.Fatalf("expected a single rewritten Ingress, got %d", len(res.Ingresses))
+	}
+
+	rewritten := res.Ingresses[0]
+
+	if rewritten.Spec.IngressClassName == nil || *rewritten.Spec.IngressClassName != "traefik" {
+		t.Errorf("ingressClassName = %v, want traefik", rewritten.Spec.IngressClassName)
+	}
+
+	want := map[string]string{
+		"team": "payments",
+		"traefik.ingress.kubernetes.io/router.middlewares": "default-app-cors@kubernetescrd,default-app-ratelimit@kubernetescrd",
+		"traefik.ingress.kubernetes.io/router.entrypoints": "web",
+	}
+
+	if len(rewritten.Annotations) != len(want) {
+		t.Errorf("annotations = %v, want %v", rewritten.Annotations, want)
+	}
+
+	for key, value := range want {
+		if rewritten.Annotations[key] != value {
+			t.Errorf("annotation %s = %q, want %q", key, rewritten.Annotations[key], value)
+		}
+	}
+
+	// the source ingress is left untouched
+	if ing.Spec.IngressClassName != nil || ing.Annotations[annotationPrefix+"enable-cors"] != "true" {
+		t.Error("the source ingress was modified")
+	}
+}
+
+func TestRunIngressModeTLS(t *testing.T) {
+	ing := newIngress("app", nil)
+	ing.Spec.TLS = []netv1.IngressTLS{{Hosts: []string{"app.example.com"}, SecretName: "app-tls"}}
+
+	res := runConvert(t, ing, ingressOptions())
+
+	if len(res.Ingresses) != 1 {
+		t.Fatalf("expected a single rewritten Ingress, got %d", len(res.Ingresses))
+	}
+
+	annotations := res.Ingresses[0].Annotations
+
+	if annotations["traefik.ingress.kubernetes.io/router.entrypoints"] != "websecure" {
+		t.Errorf("entrypoints = %q, want websecure", annotations["traefik.ingress.kubernetes.io/router.entrypoints"])
+	}
+
+	if annotations["traefik.ingress.kubernetes.io/router.tls"] != "true" {
+		t.Errorf("router.tls = %q, want true", annotations["traefik.ingress.kubernetes.io/router.tls"])
+	}
+
+	if _, ok := annotations["traefik.ingress.kubernetes.io/router.middlewares"]; ok {
+		t.Error("router.middlewares should not be set when no middleware is generated")
+	}
+}
+
+func TestRunRouteMode(t *testing.T) {
+	tests := []struct {
+		name          string
+		mode          string
+		annotations   map[string]string
+		want          string
+		ingressRoutes int
+		ingresses     int
+	}{
+		{
+			name:        "auto mode leaves plain middlewares unrouted",
+			mode:        configs.RouteModeAuto,
+			annotations: map[string]string{"limit-rps": "10"},
+			want:        configs.RoutedByNone,
+		},
+		{
+			name:          "auto mode routes backend protocols through an IngressRoute",
+			mode:          configs.RouteModeAuto,
+			annotations:   map[string]string{"backend-protocol": "HTTPS"},
+			want:          configs.RoutedByIngressRoute,
+			ingressRoutes: 1,
+		},
+		{
+			name:          "ingressroute mode covers every ingress",
+			mode:          configs.RouteModeIngressRoute,
+			annotations:   map[string]string{"limit-rps": "10"},
+			want:          configs.RoutedByIngressRoute,
+			ingressRoutes: 1,
+		},
+		{
+			name:        "ingress mode",
+			mode:        configs.RouteModeIngress,
+			annotations: map[string]string{"limit-rps": "10"},
+			want:        configs.RoutedByIngress,
+			ingresses:   1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			opts := configs.NewOptions()
+			opts.RouteMode = tt.mode
+
+			res := runConvert(t, newIngress("app", tt.annotations), opts)
+
+			if res.IngressReport.RouteMode != tt.want {
+				t.Errorf("route mode = %q, want %q", res.IngressReport.RouteMode, tt.want)
+			}
+
+			if len(res.IngressRoutes) != tt.ingressRoutes {
+				t.Errorf("IngressRoutes = %d, want %d", len(res.IngressRoutes), tt.ingressRoutes)
+			}
+
+			if len(res.Ingresses) != tt.ingresses {
+				t.Errorf("Ingresses = %d, want %d", len(res.Ingresses), tt.ingresses)
+			}
+
+			if tt.want == configs.RoutedByNone && len(res.Warnings) == 0 {
+				t.Error("unreferenced middlewares should be warned about")
+			}
+		})
+	}
+}
+
+func TestRunIngressRouteModeWiresMiddlewares(t *testing.T) {
+	res := runConvert(t, newIngress("app", map[string]string{"enable-cors": "true"}), ingressRouteOptions())
+
+	if len(res.IngressRoutes) != 1 {
+		t.Fatalf("expected a single IngressRoute, got %d", len(res.IngressRoutes))
+	}
+
+	spec := res.IngressRoutes[0].Spec
+
+	if len(spec.EntryPoints) != 1 || spec.EntryPoints[0] != "web" {
+		t.Errorf("entry points = %v, want [web]", spec.EntryPoints)
+	}
+
+	if spec.TLS != nil {
+		t.Errorf("tls = %+v, want none for an ingress without spec.tls", spec.TLS)
+	}
+
+	if got := routeMiddlewares(t, res); len(got) != 1 || got[0] != "app-cors" {
+		t.Errorf("route middlewares = %v, want [app-cors]", got)
+	}
+
+	if want := "Host(`app.example.com`) && PathPrefix(`/`)"; spec.Routes[0].Match != want {
+		t.Errorf("match = %q, want %q", spec.Routes[0].Match, want)
+	}
+}
+
+// withHosts returns a copy of the ingress whose rule is repeated for every host.
+func withHosts(ing *netv1.Ingress, hosts ...string) *netv1.Ingress {
+	out := ing.DeepCopy()
+	out.Spec.Rules = nil
+
+	for _, host := range hosts {
+		rule := *ing.Spec.Rules[0].DeepCopy()
+		rule.Host = host
+
+		out.Spec.Rules = append(out.Spec.Rules, rule)
+	}
+
+	return out
+}
+
+func TestRunIngressRouteTLS(t *testing.T) {
+	type route struct {
+		name        string
+		entryPoints string
+		secretName  string
+		domains     string
+	}
+
+	tests := []struct {
+		name  string
+		hosts []string
+		tls   []netv1.IngressTLS
+		want  []route
+	}{
+		{
+			name:  "single certificate keeps the ingress name",
+			hosts: []string{"app.example.com", "www.example.com"},
+			tls:   []netv1.IngressTLS{{Hosts: []string{"app.example.com", "www.example.com"}, SecretName: "app-tls"}},
+			want: []route{
+				{name: "app", entryPoints: "websecure", secretName: "app-tls", domains: "app.example.com,www.example.com"},
+			},
+		},
+		{
+			name:  "hosts with different certificates are split",
+			hosts: []string{"a.example.com", "b.example.com", "plain.example.com"},
+			tls: []netv1.IngressTLS{
+				{Hosts: []string{"a.example.com"}, SecretName: "a-tls"},
+				{Hosts: []string{"b.example.com"}, SecretName: "b-tls"},
+			},
+			want: []route{
+				{name: "app-a-tls", entryPoints: "websecure", secretName: "a-tls", domains: "a.example.com"},
+				{name: "app-b-tls", entryPoints: "websecure", secretName: "b-tls", domains: "b.example.com"},
+				{name: "app-http", entryPoints: "web"},
+			},
+		},
+		{
+			name:  "wildcard certificate covers a single label",
+			hosts: []string{"app.example.com", "deep.app.example.com"},
+			tls:   []netv1.IngressTLS{{Hosts: []string{"*.example.com"}, SecretName: "wildcard-tls"}},
+			want: []route{
+				{name: "app-wildcard-tls", entryPoints: "websecure", secretName: "wildcard-tls", domains: "app.example.com"},
+				{name: "app-http", entryPoints: "web"},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ing := withHosts(newIngress("app", nil), tt.hosts...)
+			ing.Spec.TLS = tt.tls
+
+			res := runConvert(t, ing, ingressRouteOptions())
+
+			if len(res.IngressRoutes) != len(tt.want) {
+				t.Fatalf("IngressRoutes = %d, want %d", len(res.IngressRoutes), len(tt.want))
+			}
+
+			for index, want := range tt.want {
+				ingressRoute := res.IngressRoutes[index]
+
+				got := route{
+					name:        ingressRoute.Name,
+					entryPoints: strings.Join(ingressRoute.Spec.EntryPoints, ","),
+				}
+
+				if routeTLS := ingressRoute.Spec.TLS; routeTLS != nil {
+					got.secretName = routeTLS.SecretName
+
+					for _, domain := range routeTLS.Domains {
+						got.domains = strings.Join(append([]string{domain.Main}, domain.SANs...), ",")
+					}
+				}
+
+				if got != want {
+					t.Errorf("IngressRoute %d = %+v, want %+v", index, got, want)
+				}
+			}
+		})
+	}
+}
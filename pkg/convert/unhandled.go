@@ -0,0 +1,32 @@
+package convert
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/nikhilsbhat/nginx-traefik-converter/pkg/configs"
+	"github.com/nikhilsbhat/nginx-traefik-converter/pkg/converters/models"
+)
+
+// reportUnhandled records every NGINX annotation of the ingress that none of the
+// converters reported on, so that dropped behavior never goes unnoticed.
+func reportUnhandled(ctx configs.Context) {
+	unhandled := make([]string, 0)
+
+	for annotation := range ctx.Annotations {
+		if !strings.HasPrefix(annotation, models.NginxAnnotationPrefix) || ctx.IsReported(annotation) {
+			continue
+		}
+
+		unhandled = append(unhandled, annotation)
+	}
+
+	sort.Strings(unhandled)
+
+	for _, annotation := range unhandled {
+		msg := "no converter handled this annotation; its behavior is dropped and must be migrated manually"
+
+		ctx.Result.Warnings = append(ctx.Result.Warnings, annotation+": "+msg)
+		ctx.ReportUnhandled(annotation, msg)
+	}
+}
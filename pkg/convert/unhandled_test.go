@@ -0,0 +1,100 @@
+package convert_test
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/nikhilsbhat/nginx-traefik-converter/pkg/configs"
+)
+
+func TestRunReportsUnhandled(t *testing.T) {
+	ing := newIngress("app", map[string]string{
+		"enable-cors":         "true",
+		"some-future-setting": "on",
+		"proxy-next-upstream": "error timeout",
+	})
+	ing.Annotations["example.com/owner"] = "payments"
+
+	res := runConvert(t, ing, configs.NewOptions())
+
+	for _, annotation := range []string{"some-future-setting", "proxy-next-upstream"} {
+		entries := reportEntries(res, annotation)
+		if len(entries) != 1 || entries[0].Status != configs.AnnotationUnhandled {
+			t.Errorf("%s report = %+v, want a single unhandled entry", annotation, entries)
+		}
+	}
+
+	for _, entry := range reportEntries(res, "enable-cors") {
+		if entry.Status == configs.AnnotationUnhandled {
+			t.Error("enable-cors is handled by the CORS converter")
+		}
+	}
+
+	for _, entry := range res.IngressReport.Entries {
+		if entry.Name == "example.com/owner" {
+			t.Error("annotations other than the nginx ones must not be reported")
+		}
+	}
+}
+
+func TestRunControllerDefaults(t *testing.T) {
+	opts := ingressRouteOptions()
+	opts.ControllerDefaults = map[string]string{
+		annotationPrefix + "proxy-body-size":        "8m",
+		annotationPrefix + "whitelist-source-range": "10.0.0.0/8",
+	}
+
+	tests := []struct {
+		name        string
+		annotations map[string]string
+		bodySize    int64
+		reported    []string
+	}{
+		{name: "defaults only", bodySize: 8 << 20},
+		{
+			name:        "annotation of the ingress wins",
+			annotations: map[string]string{"proxy-body-size": "1m"},
+			bodySize:    1 << 20,
+			reported:    []string{"proxy-body-size"},
+		},
+		{name: "passthrough", annotations: map[string]string{"ssl-passthrough": "true"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			res := runConvert(t, newIngress("app", tt.annotations), opts)
+
+			// the defaults are reported once with the controller ConfigMap
+			for _, annotation := range []string{"proxy-body-size", "whitelist-source-range"} {
+				want := 0
+				if slices.Contains(tt.reported, annotation) {
+					want = 1
+				}
+
+				if entries := reportEntries(res, annotation); len(entries) != want {
+					t.Errorf("%s report = %+v, want %d entries", annotation, entries, want)
+				}
+			}
+
+			if tt.bodySize == 0 {
+				return
+			}
+
+			found := false
+
+			for _, generated := range res.Middlewares {
+				if generated.Name == "app-bodysize" {
+					found = true
+
+					if got := generated.Spec.Buffering.MaxRequestBodyBytes; got != tt.bodySize {
+						t.Errorf("maxRequestBodyBytes = %d, want %d", got, tt.bodySize)
+					}
+				}
+			}
+
+			if !found {
+				t.Error("the proxy-body-size default was not applied")
+			}
+		})
+	}
+}
@@ -0,0 +1,214 @@
+// Package controller converts the ConfigMap of the ingress-nginx controller, which holds the global settings
+// of NGINX, into the Traefik static configuration, default middlewares and TLSOption, and into the defaults
+// applied to the annotations of every converted ingress.
+package controller
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+
+	"github.com/nikhilsbhat/nginx-traefik-converter/pkg/configs"
+	"github.com/nikhilsbhat/nginx-traefik-converter/pkg/converters/models"
+	corev1 "k8s.io/api/core/v1"
+)
+
+// annotationDefaults maps the ConfigMap keys setting the global value of an annotation to that annotation.
+var annotationDefaults = map[string]models.Annotation{
+	"proxy-body-size":                   models.ProxyBodySize,
+	"proxy-connect-timeout":             models.ProxyConnectTimeout,
+	"proxy-read-timeout":                models.ProxyReadTimeout,
+	"proxy-send-timeout":                models.ProxySendTimeout,
+	"proxy-buffering":                   models.ProxyBuffering,
+	"proxy-buffer-size":                 models.ProxyBufferSize,
+	"proxy-http-version":                models.ProxyHTTPVersion,
+	"whitelist-source-range":            models.WhitelistSourceRange,
+	"denylist-source-range":             models.DenylistSourceRange,
+	"custom-http-errors":                models.CustomHTTPErrors,
+	"enable-underscores-in-headers":     models.UnderscoresInHeaders,
+	"upstream-keepalive-connections":    models.UpstreamKeepaliveConnections,
+	"upstream-keepalive-timeout":        models.UpstreamKeepaliveTimeout,
+	"upstream-keepalive-requests":       models.UpstreamKeepaliveRequests,
+	"upstream-keepalive-time":           models.UpstreamKeepaliveTime,
+	"global-auth-url":                   models.AuthURL,
+	"global-auth-response-headers":      models.AuthResponseHeaders,
+	"global-auth-method":                models.AuthMethod,
+	"global-auth-signin":                models.AuthSignin,
+	"global-auth-signin-redirect-param": models.AuthSigninRedirectParam,
+	"global-auth-request-redirect":      models.AuthRequestRedirect,
+	"global-auth-snippet":               models.AuthSnippet,
+	"global-auth-cache-key":             models.AuthCacheKey,
+	"global-auth-cache-duration":        models.AuthCacheDuration,
+}
+
+// nginxDefaults holds the values ingress-nginx uses for the converted keys missing from the ConfigMap.
+var nginxDefaults = map[string]string{
+	"hsts":                    "true",
+	"hsts-max-age":            "31536000",
+	"hsts-include-subdomains": "true",
+	"hsts-preload":            "false",
+	"ssl-protocols":           "TLSv1.2 TLSv1.3",
+	"ssl-ciphers": "ECDHE-ECDSA-AES128-GCM-SHA256:ECDHE-RSA-AES128-GCM-SHA256:ECDHE-ECDSA-AES256-GCM-SHA384:" +
+		"ECDHE-RSA-AES256-GCM-SHA384:ECDHE-ECDSA-CHACHA20-POLY1305:ECDHE-RSA-CHACHA20-POLY1305:" +
+		"DHE-RSA-AES128-GCM-SHA256:DHE-RSA-AES256-GCM-SHA384",
+	"ssl-ecdh-curve":       "auto",
+	"forwarded-for-header": "X-Forwarded-For",
+	"proxy-real-ip-cidr":   "0.0.0.0/0",
+	"otlp-collector-port":  "4317",
+	"otel-service-name":    "nginx",
+	"gzip-min-length":      "256",
+}
+
+// Convert converts the controller ConfigMap. The static configuration is added to the entry points and tracing
+// of the result, the default middlewares and TLSOption to its resources, and the annotation defaults to the
+// options, so that they apply to the ingresses converted afterwards. The report of the ConfigMap lists one
+// entry per key.
+func Convert(ctx configs.Context, configMap *corev1.ConfigMap) {
+	ctx.Log.Debug("running converter Controller")
+
+	if ctx.Result.EntryPoints == nil {
+		ctx.Result.EntryPoints = make(map[string]*configs.EntryPoint)
+	}
+
+	settings := &controllerSettings{ctx: ctx, data: configMap.Data}
+
+	settings.annotationDefaults()
+	settings.forwardedHeaders()
+	settings.proxyProtocol()
+	settings.headerBuffers()
+	settings.hsts()
+	settings.compression()
+	settings.tracing()
+	settings.defaultTLSOption()
+	settings.sslRedirect()
+	settings.reportUnhandled()
+
+	ctx.ReportRouteMode(configs.RoutedByStaticConfig)
+}
+
+// controllerSettings gives access to the keys of the controller ConfigMap.
+type controllerSettings struct {
+	ctx  configs.Context
+	data map[string]string
+}
+
+// value returns the value of the key, or the ingress-nginx default when the ConfigMap does not set it.
+func (settings *controllerSettings) value(key string) string {
+	if val, ok := settings.data[key]; ok {
+		return strings.TrimSpace(val)
+	}
+
+	return nginxDefaults[key]
+}
+
+// isSet reports whether the ConfigMap sets the key.
+func (settings *controllerSettings) isSet(key string) bool {
+	_, ok := settings.data[key]
+
+	return ok
+}
+
+// enabled reports whether the boolean key is true, taking the ingress-nginx default into account.
+func (settings *controllerSettings) enabled(key string) bool {
+	return strings.EqualFold(settings.value(key), "true")
+}
+
+// presentKeys returns the given keys set in the ConfigMap.
+func (settings *controllerSettings) presentKeys(keys ...string) []string {
+	present := make([]string, 0)
+
+	for _, key := range keys {
+		if settings.isSet(key) {
+			present = append(present, key)
+		}
+	}
+
+	return present
+}
+
+// entryPoint returns the web or websecure entry point of the result, creating it on first use.
+func (settings *controllerSettings) entryPoint(name string) *configs.EntryPoint {
+	entryPoint, ok := settings.ctx.Result.EntryPoints[name]
+	if !ok {
+		entryPoint = configs.DefaultEntryPoint(name)
+		settings.ctx.Result.EntryPoints[name] = entryPoint
+	}
+
+	return entryPoint
+}
+
+// entryPointHTTP returns the HTTP settings of the web or websecure entry point, creating them on first use.
+func (settings *controllerSettings) entryPointHTTP(name string) *configs.EntryPointHTTP {
+	entryPoint := settings.entryPoint(name)
+	if entryPoint.HTTP == nil {
+		entryPoint.HTTP = &configs.EntryPointHTTP{}
+	}
+
+	return entryPoint.HTTP
+}
+
+func (settings *controllerSettings) warn(key, msg string) {
+	settings.ctx.Result.Warnings = append(settings.ctx.Result.Warnings, key+": "+msg)
+	settings.ctx.ReportWarning(key, msg)
+}
+
+func (settings *controllerSettings) skip(key, msg string) {
+	settings.ctx.Result.Warnings = append(settings.ctx.Result.Warnings, key+": "+msg)
+	settings.ctx.ReportSkipped(key, msg)
+}
+
+// annotationDefaults records the keys setting the global value of an annotation, the converters
+// apply them to the ingresses which do not set the annotation.
+func (settings *controllerSettings) annotationDefaults() {
+	defaults := make(map[string]string)
+
+	for _, key := range slices.Sorted(maps.Keys(annotationDefaults)) {
+		val, ok := settings.data[key]
+		if !ok {
+			continue
+		}
+
+		defaults[string(annotationDefaults[key])] = val
+
+		settings.ctx.ReportConverted(key)
+	}
+
+	if len(defaults) == 0 {
+		return
+	}
+
+	settings.ctx.Options.ControllerDefaults = defaults
+
+	settings.ctx.Result.Warnings = append(settings.ctx.Result.Warnings,
+		fmt.Sprintf("%d settings of the ConfigMap are applied as annotation defaults to the ingresses which do not set them",
+			len(defaults)),
+	)
+}
+
+// sslRedirect reports the global ssl-redirect, which the converted routes cover through the entry points.
+func (settings *controllerSettings) sslRedirect() {
+	for _, key := range settings.presentKeys("ssl-redirect", "force-ssl-redirect") {
+		settings.skip(key, "ingress-nginx redirects the TLS hosts to HTTPS globally; "+
+			"use the ssl-redirect annotation on the ingresses or configure http.redirections on the web entry point")
+	}
+}
+
+// reportUnhandled reports the keys of the ConfigMap none of the settings reported on.
+func (settings *controllerSettings) reportUnhandled() {
+	for _, key := range slices.Sorted(maps.Keys(settings.data)) {
+		if settings.ctx.IsReported(key) {
+			continue
+		}
+
+		msg := "no converter handled this setting; its behavior is dropped and must be migrated manually"
+
+		settings.ctx.Result.Warnings = append(settings.ctx.Result.Warnings, key+": "+msg)
+		settings.ctx.ReportUnhandled(key, msg)
+	}
+}
+
+// crdReference returns the reference to a middleware of the kubernetesCRD provider, as used by the entry points.
+func crdReference(namespace, name string) string {
+	return fmt.Sprintf("%s-%s@kubernetescrd", namespace, name)
+}
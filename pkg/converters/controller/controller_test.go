@@ -0,0 +1,186 @@
+package controller_test
+
+import (
+	"io"
+	"log/slog"
+	"slices"
+	"testing"
+
+	"github.com/nikhilsbhat/nginx-traefik-converter/pkg/configs"
+	"github.com/nikhilsbhat/nginx-traefik-converter/pkg/converters/controller"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// convert converts the ConfigMap data as the convert command does with --controller-configmap.
+func convert(data map[string]string) *configs.Context {
+	configMap := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Name: "ingress-nginx-controller", Namespace: "ingress-nginx"},
+		Data:       data,
+	}
+
+	ctx := &configs.Context{
+		IngressName: configMap.Name,
+		Namespace:   configMap.Namespace,
+		Result:      configs.NewResult(),
+		Options:     configs.NewOptions(),
+		Log:         slog.New(slog.NewTextHandler(io.Discard, nil)),
+	}
+	ctx.StartIngressReport(configMap.Namespace, configMap.Name)
+
+	controller.Convert(*ctx, configMap)
+
+	return ctx
+}
+
+func status(ctx *configs.Context, key string) configs.AnnotationStatus {
+	for _, entry := range ctx.Result.IngressReport.Entries {
+		if entry.Name == key {
+			return entry.Status
+		}
+	}
+
+	return ""
+}
+
+func TestConvertEntryPoints(t *testing.T) {
+	ctx := convert(map[string]string{
+		"use-forwarded-headers":       "true",
+		"proxy-real-ip-cidr":          "10.0.0.0/8, 192.168.0.0/16",
+		"use-proxy-protocol":          "true",
+		"large-client-header-buffers": "4 16k",
+		"forwarded-for-header":        "X-Real-IP",
+	})
+
+	if !ctx.Options.TrustForwardedHeaders {
+		t.Error("use-forwarded-headers should trust the forwarded headers of the converted ingresses")
+	}
+
+	for _, name := range []string{configs.EntryPointWeb, configs.EntryPointWebSecure} {
+		entryPoint := ctx.Result.EntryPoints[name]
+		if entryPoint == nil {
+			t.Fatalf("entry point %s was not configured", name)
+		}
+
+		want := []string{"10.0.0.0/8", "192.168.0.0/16"}
+
+		if entryPoint.ForwardedHeaders == nil || entryPoint.ForwardedHeaders.Insecure ||
+			!slices.Equal(entryPoint.ForwardedHeaders.TrustedIPs, want) {
+			t.Errorf("%s forwardedHeaders = %+v, want trustedIPs %v", name, entryPoint.ForwardedHeaders, want)
+		}
+
+		if entryPoint.ProxyProtocol == nil || !slices.Equal(entryPoint.ProxyProtocol.TrustedIPs, want) {
+			t.Errorf("%s proxyProtocol = %+v, want trustedIPs %v", name, entryPoint.ProxyProtocol, want)
+		}
+
+		if entryPoint.HTTP == nil || entryPoint.HTTP.MaxHeaderBytes != 4*16*1024 {
+			t.Errorf("%s http = %+v, want maxHeaderBytes of 4 buffers of 16k", name, entryPoint.HTTP)
+		}
+	}
+
+	want := map[string]configs.AnnotationStatus{
+		"use-forwarded-headers":       configs.AnnotationConverted,
+		"proxy-real-ip-cidr":          configs.AnnotationConverted,
+		"use-proxy-protocol":          configs.AnnotationConverted,
+		"large-client-header-buffers": configs.AnnotationConverted,
+		"forwarded-for-header":        configs.AnnotationSkipped,
+	}
+
+	for key, wantStatus := range want {
+		if got := status(ctx, key); got != wantStatus {
+			t.Errorf("%s status = %q, want %q", key, got, wantStatus)
+		}
+	}
+}
+
+func TestConvertDefaults(t *testing.T) {
+	ctx := convert(map[string]string{
+		"proxy-body-size":      "8m",
+		"global-auth-url":      "https://auth.example.com/verify",
+		"some-unknown-setting": "on",
+	})
+
+	want := map[string]string{
+		"nginx.ingress.kubernetes.io/proxy-body-size": "8m",
+		"nginx.ingress.kubernetes.io/auth-url":        "https://auth.example.com/verify",
+	}
+
+	if len(ctx.Options.ControllerDefaults) != len(want) {
+		t.Errorf("controller defaults = %v, want %v", ctx.Options.ControllerDefaults, want)
+	}
+
+	for annotation, value := range want {
+		if ctx.Options.ControllerDefaults[annotation] != value {
+			t.Errorf("default of %s = %q, want %q", annotation, ctx.Options.ControllerDefaults[annotation], value)
+		}
+	}
+
+	// the annotations of an ingress take precedence over the defaults
+	defaults := ctx.Options.ControllerDefaultsFor(map[string]string{"nginx.ingress.kubernetes.io/proxy-body-size": "1m"})
+	if _, ok := defaults["nginx.ingress.kubernetes.io/proxy-body-size"]; ok || defaults["nginx.ingress.kubernetes.io/auth-url"] == "" {
+		t.Errorf("defaults of the ingress = %v", defaults)
+	}
+
+	if got := status(ctx, "some-unknown-setting"); got != configs.AnnotationUnhandled {
+		t.Errorf("some-unknown-setting status = %q, want unhandled", got)
+	}
+
+	// HSTS is enabled by ingress-nginx unless disabled
+	if len(ctx.Result.Middlewares) != 1 || ctx.Result.Middlewares[0].Name != "ingress-nginx-controller-hsts" {
+		t.Fatalf("middlewares = %v, want the default HSTS middleware", ctx.Result.Middlewares)
+	}
+
+	if headers := ctx.Result.Middlewares[0].Spec.Headers; headers.STSSeconds != 31536000 || !headers.STSIncludeSubdomains {
+		t.Errorf("hsts = %+v", headers)
+	}
+
+	websecure := ctx.Result.EntryPoints[configs.EntryPointWebSecure]
+	if websecure == nil || websecure.HTTP == nil ||
+		!slices.Equal(websecure.HTTP.Middlewares, []string{"ingress-nginx-ingress-nginx-controller-hsts@kubernetescrd"}) {
+		t.Errorf("websecure = %+v, want the HSTS middleware applied", websecure)
+	}
+}
+
+func TestConvertDefaultTLSOption(t *testing.T) {
+	ctx := convert(map[string]string{
+		"hsts":           "false",
+		"ssl-protocols":  "TLSv1.2 TLSv1.3 SSLv3",
+		"ssl-ciphers":    "ECDHE-RSA-AES128-GCM-SHA256:!aNULL:UNKNOWN-CIPHER",
+		"ssl-ecdh-curve": "X25519:prime256v1",
+	})
+
+	if len(ctx.Result.Middlewares) != 0 {
+		t.Errorf("no HSTS middleware should be generated when hsts is disabled, got %d", len(ctx.Result.Middlewares))
+	}
+
+	if len(ctx.Result.TLSOptions) != 1 || ctx.Result.TLSOptions[0].Name != "default" {
+		t.Fatalf("TLSOptions = %v, want the default TLSOption", ctx.Result.TLSOptions)
+	}
+
+	spec := ctx.Result.TLSOptions[0].Spec
+
+	if spec.MinVersion != "VersionTLS12" || spec.MaxVersion != "" {
+		t.Errorf("versions = %q-%q, want VersionTLS12 without a max version", spec.MinVersion, spec.MaxVersion)
+	}
+
+	if !slices.Equal(spec.CipherSuites, []string{"TLS_ECDHE_RSA_WITH_AES_128_GCM_SHA256"}) {
+		t.Errorf("cipherSuites = %v", spec.CipherSuites)
+	}
+
+	if !slices.Equal(spec.CurvePreferences, []string{"X25519", "CurveP256"}) {
+		t.Errorf("curvePreferences = %v", spec.CurvePreferences)
+	}
+
+	want := map[string]configs.AnnotationStatus{
+		"hsts":           configs.AnnotationConverted,
+		"ssl-protocols":  configs.AnnotationWarned,
+		"ssl-ciphers":    configs.AnnotationWarned,
+		"ssl-ecdh-curve": configs.AnnotationConverted,
+	}
+
+	for key, wantStatus := range want {
+		if got := status(ctx, key); got != wantStatus {
+			t.Errorf("%s status = %q, want %q", key, got, wantStatus)
+		}
+	}
+}
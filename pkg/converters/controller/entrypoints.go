@@ -0,0 +1,161 @@
+package controller
+
+import (
+	"fmt"
+	"net/http"
+	"slices"
+	"strings"
+
+	"github.com/nikhilsbhat/nginx-traefik-converter/pkg/configs"
+	"github.com/nikhilsbhat/nginx-traefik-converter/pkg/converters/middleware"
+)
+
+// anyAddress is the proxy-real-ip-cidr default, trusting every client.
+const anyAddress = "0.0.0.0/0"
+
+var httpEntryPoints = []string{configs.EntryPointWeb, configs.EntryPointWebSecure}
+
+/* ---------------- FORWARDED HEADERS ---------------- */
+
+// forwardedHeaders handles the below keys.
+// Keys:
+//   - "use-forwarded-headers"
+//   - "forwarded-for-header"
+//   - "compute-full-forwarded-for"
+//   - "proxy-real-ip-cidr"
+func (settings *controllerSettings) forwardedHeaders() {
+	const key = "use-forwarded-headers"
+
+	if !settings.enabled(key) {
+		for _, present := range settings.presentKeys(key, "forwarded-for-header", "compute-full-forwarded-for") {
+			settings.ctx.ReportIgnored(present, "use-forwarded-headers is not enabled, "+
+				"Traefik drops the X-Forwarded-* headers of untrusted clients as well")
+		}
+
+		return
+	}
+
+	// Client IP based middlewares read the client IP from X-Forwarded-For, as with --trust-forwarded-headers.
+	settings.ctx.Options.TrustForwardedHeaders = true
+
+	trusted, insecure := settings.trustedIPs()
+
+	for _, name := range httpEntryPoints {
+		settings.entryPoint(name).ForwardedHeaders = &configs.ForwardedHeaders{
+			Insecure:   insecure,
+			TrustedIPs: trusted,
+		}
+	}
+
+	settings.ctx.ReportConverted(key)
+
+	if header := settings.value("forwarded-for-header"); !strings.EqualFold(header, "X-Forwarded-For") {
+		settings.skip("forwarded-for-header",
+			fmt.Sprintf("Traefik only reads the client IP from X-Forwarded-For, the %s header is not used",
+				http.CanonicalHeaderKey(header)))
+	} else if settings.isSet("forwarded-for-header") {
+		settings.ctx.ReportConverted("forwarded-for-header")
+	}
+
+	if settings.enabled("compute-full-forwarded-for") {
+		settings.ctx.ReportConverted("compute-full-forwarded-for")
+	} else if settings.isSet("compute-full-forwarded-for") {
+		settings.warn("compute-full-forwarded-for",
+			"Traefik appends the client address to the trusted X-Forwarded-For header instead of replacing it")
+	}
+
+	if insecure {
+		settings.ctx.Result.Warnings = append(settings.ctx.Result.Warnings,
+			"the web and websecure entry points trust the X-Forwarded-* headers of any client as ingress-nginx does "+
+				"with the default proxy-real-ip-cidr; set forwardedHeaders.trustedIPs to the addresses of the load balancer",
+		)
+	}
+}
+
+// trustedIPs returns the proxy-real-ip-cidr ranges, or insecure when any client is trusted.
+func (settings *controllerSettings) trustedIPs() ([]string, bool) {
+	trusted := make([]string, 0)
+
+	for _, cidr := range strings.Split(settings.value("proxy-real-ip-cidr"), ",") {
+		if cidr = strings.TrimSpace(cidr); cidr != "" {
+			trusted = append(trusted, cidr)
+		}
+	}
+
+	if settings.isSet("proxy-real-ip-cidr") {
+		settings.ctx.ReportConverted("proxy-real-ip-cidr")
+	}
+
+	if len(trusted) == 0 || slices.Contains(trusted, anyAddress) {
+		return nil, true
+	}
+
+	return trusted, false
+}
+
+/* ---------------- PROXY PROTOCOL ---------------- */
+
+// proxyProtocol handles the below keys.
+// Keys:
+//   - "use-proxy-protocol"
+func (settings *controllerSettings) proxyProtocol() {
+	const key = "use-proxy-protocol"
+
+	if !settings.enabled(key) {
+		if settings.isSet(key) {
+			settings.ctx.ReportConverted(key)
+		}
+
+		return
+	}
+
+	trusted, insecure := settings.trustedIPs()
+
+	for _, name := range httpEntryPoints {
+		settings.entryPoint(name).ProxyProtocol = &configs.EntryPointProxyProtocol{
+			Insecure:   insecure,
+			TrustedIPs: trusted,
+		}
+	}
+
+	settings.ctx.ReportConverted(key)
+
+	if insecure {
+		settings.ctx.Result.Warnings = append(settings.ctx.Result.Warnings,
+			"the web and websecure entry points accept the PROXY protocol from any client as ingress-nginx does "+
+				"with the default proxy-real-ip-cidr; set proxyProtocol.trustedIPs to the addresses of the load balancer",
+		)
+	}
+}
+
+/* ---------------- HEADER BUFFERS ---------------- */
+
+// headerBuffers handles the below keys.
+// Keys:
+//   - "large-client-header-buffers"
+//   - "client-header-buffer-size"
+func (settings *controllerSettings) headerBuffers() {
+	const key = "large-client-header-buffers"
+
+	if settings.isSet("client-header-buffer-size") {
+		settings.ctx.ReportIgnored("client-header-buffer-size",
+			"client-header-buffer-size only sizes the initial NGINX buffer, the request header limit comes from "+key)
+	}
+
+	if !settings.isSet(key) {
+		return
+	}
+
+	limit, err := middleware.MaxHeaderBytes(settings.value(key))
+	if err != nil {
+		settings.skip(key, err.Error())
+
+		return
+	}
+
+	for _, name := range httpEntryPoints {
+		settings.entryPointHTTP(name).MaxHeaderBytes = limit
+	}
+
+	settings.ctx.ReportConverted(key)
+}
@@ -0,0 +1,155 @@
+package controller
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/nikhilsbhat/nginx-traefik-converter/pkg/configs"
+	"github.com/traefik/traefik/v3/pkg/config/dynamic"
+	traefik "github.com/traefik/traefik/v3/pkg/provider/kubernetes/crd/traefikio/v1alpha1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+/* ---------------- HSTS ---------------- */
+
+// hsts handles the below keys.
+// Keys:
+//   - "hsts"
+//   - "hsts-max-age"
+//   - "hsts-include-subdomains"
+//   - "hsts-preload"
+//
+// ingress-nginx sends the Strict-Transport-Security header on every TLS response unless hsts is disabled,
+// the header is set by a Headers middleware applied to all the routers of the websecure entry point.
+func (settings *controllerSettings) hsts() {
+	options := settings.presentKeys("hsts-max-age", "hsts-include-subdomains", "hsts-preload")
+
+	if !settings.enabled("hsts") {
+		if settings.isSet("hsts") {
+			settings.ctx.ReportConverted("hsts")
+		}
+
+		for _, key := range options {
+			settings.ctx.ReportIgnored(key, "hsts is disabled")
+		}
+
+		return
+	}
+
+	maxAge, err := strconv.ParseInt(settings.value("hsts-max-age"), 10, 64)
+	if err != nil || maxAge < 0 {
+		settings.skip("hsts-max-age", fmt.Sprintf("invalid hsts-max-age %q, HSTS was not converted", settings.value("hsts-max-age")))
+
+		return
+	}
+
+	name := settings.ctx.IngressName + "-hsts"
+
+	settings.addMiddleware(name, traefik.MiddlewareSpec{
+		Headers: &dynamic.Headers{
+			STSSeconds:           maxAge,
+			STSIncludeSubdomains: settings.enabled("hsts-include-subdomains"),
+			STSPreload:           settings.enabled("hsts-preload"),
+		},
+	})
+
+	websecure := settings.entryPointHTTP(configs.EntryPointWebSecure)
+	websecure.Middlewares = append(websecure.Middlewares, crdReference(settings.ctx.Namespace, name))
+
+	for _, key := range append(settings.presentKeys("hsts"), options...) {
+		settings.ctx.ReportConverted(key)
+	}
+
+	if !settings.isSet("hsts") {
+		settings.ctx.Result.Warnings = append(settings.ctx.Result.Warnings,
+			"HSTS is enabled by default in ingress-nginx, the "+name+" middleware sets it on the websecure entry point",
+		)
+	}
+}
+
+/* ---------------- COMPRESSION ---------------- */
+
+// compression handles the below keys.
+// Keys:
+//   - "use-gzip"
+//   - "enable-brotli"
+//   - "gzip-min-length"
+//   - "gzip-types"
+//   - "gzip-level"
+//   - "brotli-types"
+//   - "brotli-level"
+func (settings *controllerSettings) compression() {
+	options := settings.presentKeys("gzip-min-length", "gzip-types", "gzip-level", "brotli-types", "brotli-level")
+
+	encodings := make([]string, 0)
+
+	if settings.enabled("enable-brotli") {
+		encodings = append(encodings, "br")
+	}
+
+	if settings.enabled("use-gzip") {
+		encodings = append(encodings, "gzip")
+	}
+
+	if len(encodings) == 0 {
+		for _, key := range append(settings.presentKeys("use-gzip", "enable-brotli"), options...) {
+			settings.ctx.ReportIgnored(key, "compression is not enabled")
+		}
+
+		return
+	}
+
+	compress := &traefik.Compress{
+		Encodings: encodings,
+	}
+
+	if minLength, err := strconv.Atoi(settings.value("gzip-min-length")); err == nil {
+		compress.MinResponseBodyBytes = &minLength
+	} else {
+		settings.warn("gzip-min-length", fmt.Sprintf("invalid gzip-min-length %q, Traefik default of 1024 bytes applies",
+			settings.value("gzip-min-length")))
+	}
+
+	if types := strings.Fields(settings.value("gzip-types")); len(types) > 0 && types[0] != "*" {
+		compress.IncludedContentTypes = types
+	}
+
+	name := settings.ctx.IngressName + "-compress"
+
+	settings.addMiddleware(name, traefik.MiddlewareSpec{
+		Compress: compress,
+	})
+
+	for _, entryPoint := range httpEntryPoints {
+		entryPointHTTP := settings.entryPointHTTP(entryPoint)
+		entryPointHTTP.Middlewares = append(entryPointHTTP.Middlewares, crdReference(settings.ctx.Namespace, name))
+	}
+
+	for _, key := range append(settings.presentKeys("use-gzip", "enable-brotli"), options...) {
+		switch key {
+		case "gzip-level", "brotli-level":
+			settings.ctx.ReportIgnored(key, "Traefik does not expose the compression level")
+		case "brotli-types":
+			settings.warn(key, "Traefik compresses the same content types with every encoding, gzip-types applies to brotli as well")
+		default:
+			if !settings.ctx.IsReported(key) {
+				settings.ctx.ReportConverted(key)
+			}
+		}
+	}
+}
+
+func (settings *controllerSettings) addMiddleware(name string, spec traefik.MiddlewareSpec) {
+	settings.ctx.Result.Middlewares = append(settings.ctx.Result.Middlewares, &traefik.Middleware{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: traefik.SchemeGroupVersion.String(),
+			Kind:       "Middleware",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: settings.ctx.Namespace,
+		},
+		Spec: spec,
+	})
+}
@@ -0,0 +1,201 @@
+package controller
+
+import (
+	"fmt"
+	"strings"
+
+	traefik "github.com/traefik/traefik/v3/pkg/provider/kubernetes/crd/traefikio/v1alpha1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// defaultTLSOptionName is the name of the TLSOption Traefik applies to the routers without TLS options.
+const defaultTLSOptionName = "default"
+
+// tlsVersions maps the NGINX protocol names to the Traefik TLS versions, in ascending order.
+var tlsVersions = []struct {
+	protocol string
+	version  string
+}{
+	{protocol: "TLSv1", version: "VersionTLS10"},
+	{protocol: "TLSv1.1", version: "VersionTLS11"},
+	{protocol: "TLSv1.2", version: "VersionTLS12"},
+	{protocol: "TLSv1.3", version: "VersionTLS13"},
+}
+
+// cipherSuites maps the OpenSSL cipher names to the cipher suites supported by Traefik.
+var cipherSuites = map[string]string{
+	"ECDHE-ECDSA-AES128-GCM-SHA256": "TLS_ECDHE_ECDSA_WITH_AES_128_GCM_SHA256",
+	"ECDHE-RSA-AES128-GCM-SHA256":   "TLS_ECDHE_RSA_WITH_AES_128_GCM_SHA256",
+	"ECDHE-ECDSA-AES256-GCM-SHA384": "TLS_ECDHE_ECDSA_WITH_AES_256_GCM_SHA384",
+	"ECDHE-RSA-AES256-GCM-SHA384":   "TLS_ECDHE_RSA_WITH_AES_256_GCM_SHA384",
+	"ECDHE-ECDSA-CHACHA20-POLY1305": "TLS_ECDHE_ECDSA_WITH_CHACHA20_POLY1305_SHA256",
+	"ECDHE-RSA-CHACHA20-POLY1305":   "TLS_ECDHE_RSA_WITH_CHACHA20_POLY1305_SHA256",
+	"ECDHE-ECDSA-AES128-SHA256":     "TLS_ECDHE_ECDSA_WITH_AES_128_CBC_SHA256",
+	"ECDHE-RSA-AES128-SHA256":       "TLS_ECDHE_RSA_WITH_AES_128_CBC_SHA256",
+	"ECDHE-ECDSA-AES128-SHA":        "TLS_ECDHE_ECDSA_WITH_AES_128_CBC_SHA",
+	"ECDHE-RSA-AES128-SHA":          "TLS_ECDHE_RSA_WITH_AES_128_CBC_SHA",
+	"ECDHE-ECDSA-AES256-SHA":        "TLS_ECDHE_ECDSA_WITH_AES_256_CBC_SHA",
+	"ECDHE-RSA-AES256-SHA":          "TLS_ECDHE_RSA_WITH_AES_256_CBC_SHA",
+	"ECDHE-RSA-DES-CBC3-SHA":        "TLS_ECDHE_RSA_WITH_3DES_EDE_CBC_SHA",
+	"AES128-GCM-SHA256":             "TLS_RSA_WITH_AES_128_GCM_SHA256",
+	"AES256-GCM-SHA384":             "TLS_RSA_WITH_AES_256_GCM_SHA384",
+	"AES128-SHA256":                 "TLS_RSA_WITH_AES_128_CBC_SHA256",
+	"AES128-SHA":                    "TLS_RSA_WITH_AES_128_CBC_SHA",
+	"AES256-SHA":                    "TLS_RSA_WITH_AES_256_CBC_SHA",
+	"DES-CBC3-SHA":                  "TLS_RSA_WITH_3DES_EDE_CBC_SHA",
+}
+
+// curves maps the OpenSSL curve names to the curves supported by Traefik.
+var curves = map[string]string{
+	"X25519":     "X25519",
+	"prime256v1": "CurveP256",
+	"secp384r1":  "CurveP384",
+	"secp521r1":  "CurveP521",
+}
+
+/* ---------------- DEFAULT TLS OPTION ---------------- */
+
+// defaultTLSOption handles the below keys.
+// Keys:
+//   - "ssl-protocols"
+//   - "ssl-ciphers"
+//   - "ssl-ecdh-curve"
+//   - "ssl-prefer-server-ciphers"
+//
+// The TLS settings of ingress-nginx apply to every host, they are converted into the default TLSOption.
+func (settings *controllerSettings) defaultTLSOption() {
+	spec := traefik.TLSOptionSpec{}
+
+	spec.MinVersion, spec.MaxVersion = settings.tlsVersionRange()
+
+	spec.CipherSuites = settings.mapNames("ssl-ciphers", ":", cipherSuites,
+		"Traefik does not support these ciphers, they were dropped")
+
+	if curve := settings.value("ssl-ecdh-curve"); curve != "auto" {
+		spec.CurvePreferences = settings.mapNames("ssl-ecdh-curve", ":", curves,
+			"Traefik does not support these curves, they were dropped")
+	} else if settings.isSet("ssl-ecdh-curve") {
+		settings.ctx.ReportConverted("ssl-ecdh-curve")
+	}
+
+	if settings.isSet("ssl-prefer-server-ciphers") {
+		settings.ctx.ReportIgnored("ssl-prefer-server-ciphers", "Traefik prefers the server cipher suites once a TLS version is set")
+	}
+
+	settings.ctx.Result.TLSOptions = append(settings.ctx.Result.TLSOptions, &traefik.TLSOption{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: traefik.SchemeGroupVersion.String(),
+			Kind:       "TLSOption",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      defaultTLSOptionName,
+			Namespace: settings.ctx.Namespace,
+		},
+		Spec: spec,
+	})
+
+	settings.ctx.Result.Warnings = append(settings.ctx.Result.Warnings,
+		"the TLSOption named default applies to every router without TLS options, "+
+			"Traefik rejects it when several namespaces define one; TLS 1.3 cipher suites cannot be configured",
+	)
+}
+
+// tlsVersionRange returns the lowest and highest TLS versions enabled by ssl-protocols, the highest one is
+// only set below TLS 1.3.
+func (settings *controllerSettings) tlsVersionRange() (string, string) {
+	const key = "ssl-protocols"
+
+	enabled := strings.Fields(settings.value(key))
+	minVersion, maxVersion := "", ""
+	unsupported := make([]string, 0)
+
+	for _, protocol := range enabled {
+		supported := false
+
+		for _, tlsVersion := range tlsVersions {
+			if tlsVersion.protocol != protocol {
+				continue
+			}
+
+			if minVersion == "" || versionIndex(tlsVersion.version) < versionIndex(minVersion) {
+				minVersion = tlsVersion.version
+			}
+
+			if versionIndex(tlsVersion.version) > versionIndex(maxVersion) {
+				maxVersion = tlsVersion.version
+			}
+
+			supported = true
+		}
+
+		if !supported {
+			unsupported = append(unsupported, protocol)
+		}
+	}
+
+	if maxVersion == tlsVersions[len(tlsVersions)-1].version {
+		maxVersion = ""
+	}
+
+	switch {
+	case minVersion == "":
+		settings.skip(key, fmt.Sprintf("no TLS version supported by Traefik in %q, Traefik defaults apply", settings.value(key)))
+	case len(unsupported) > 0:
+		settings.warn(key, fmt.Sprintf("Traefik does not support %s, they were dropped", strings.Join(unsupported, ", ")))
+	case settings.isSet(key):
+		settings.ctx.ReportConverted(key)
+	}
+
+	return minVersion, maxVersion
+}
+
+func versionIndex(version string) int {
+	for index, tlsVersion := range tlsVersions {
+		if tlsVersion.version == version {
+			return index
+		}
+	}
+
+	return -1
+}
+
+// mapNames maps the separated OpenSSL names of the key to their Traefik names. Exclusions such as !aNULL
+// are skipped, unknown names are reported when the ConfigMap sets the key.
+func (settings *controllerSettings) mapNames(key, separator string, names map[string]string, msg string) []string {
+	mapped := make([]string, 0)
+	unknown := make([]string, 0)
+
+	for _, name := range strings.Split(settings.value(key), separator) {
+		name = strings.TrimSpace(name)
+		if name == "" || strings.ContainsAny(name[:1], "!-+") {
+			continue
+		}
+
+		if traefikName, ok := names[name]; ok {
+			mapped = append(mapped, traefikName)
+
+			continue
+		}
+
+		unknown = append(unknown, name)
+	}
+
+	if !settings.isSet(key) {
+		return mapped
+	}
+
+	switch {
+	case len(mapped) == 0:
+		settings.skip(key, fmt.Sprintf("%s: %s; Traefik defaults apply", msg, strings.Join(unknown, ", ")))
+	case len(unknown) > 0:
+		settings.warn(key, fmt.Sprintf("%s: %s", msg, strings.Join(unknown, ", ")))
+	default:
+		settings.ctx.ReportConverted(key)
+	}
+
+	if len(mapped) == 0 {
+		return nil
+	}
+
+	return mapped
+}
@@ -0,0 +1,82 @@
+package controller
+
+import (
+	"fmt"
+	"net"
+	"strconv"
+
+	"github.com/nikhilsbhat/nginx-traefik-converter/pkg/configs"
+)
+
+/* ---------------- TRACING ---------------- */
+
+// tracing handles the below keys.
+// Keys:
+//   - "enable-opentelemetry"
+//   - "otlp-collector-host"
+//   - "otlp-collector-port"
+//   - "otel-service-name"
+//   - "otel-sampler-ratio"
+//   - "enable-opentracing"
+func (settings *controllerSettings) tracing() {
+	const key = "enable-opentelemetry"
+
+	options := settings.presentKeys("otlp-collector-host", "otlp-collector-port", "otel-service-name", "otel-sampler-ratio")
+
+	if settings.enabled("enable-opentracing") {
+		settings.skip("enable-opentracing", "Traefik v3 only exports traces with OpenTelemetry, use enable-opentelemetry")
+	} else if settings.isSet("enable-opentracing") {
+		settings.ctx.ReportConverted("enable-opentracing")
+	}
+
+	if !settings.enabled(key) {
+		if settings.isSet(key) {
+			settings.ctx.ReportConverted(key)
+		}
+
+		for _, present := range options {
+			settings.ctx.ReportIgnored(present, "enable-opentelemetry is not enabled")
+		}
+
+		return
+	}
+
+	grpc := &configs.TracingOTLPGRPC{
+		// ingress-nginx exports the traces to the collector without TLS.
+		Insecure: true,
+	}
+
+	if host := settings.value("otlp-collector-host"); host != "" {
+		grpc.Endpoint = net.JoinHostPort(host, settings.value("otlp-collector-port"))
+	} else {
+		settings.ctx.Result.Warnings = append(settings.ctx.Result.Warnings,
+			"otlp-collector-host is not set, Traefik sends the traces to localhost:4317")
+	}
+
+	tracing := &configs.Tracing{
+		ServiceName: settings.value("otel-service-name"),
+		OTLP: &configs.TracingOTLP{
+			GRPC: grpc,
+		},
+	}
+
+	if settings.isSet("otel-sampler-ratio") {
+		ratio, err := strconv.ParseFloat(settings.value("otel-sampler-ratio"), 64)
+		if err != nil || ratio < 0 || ratio > 1 {
+			settings.skip("otel-sampler-ratio",
+				fmt.Sprintf("invalid otel-sampler-ratio %q, Traefik samples every request", settings.value("otel-sampler-ratio")))
+
+			options = settings.presentKeys("otlp-collector-host", "otlp-collector-port", "otel-service-name")
+		} else {
+			tracing.SampleRate = &ratio
+		}
+	}
+
+	settings.ctx.Result.Tracing = tracing
+
+	settings.ctx.ReportConverted(key)
+
+	for _, present := range options {
+		settings.ctx.ReportConverted(present)
+	}
+}
@@ -0,0 +1,163 @@
+package ingressroute
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/nikhilsbhat/nginx-traefik-converter/pkg/configs"
+	"github.com/nikhilsbhat/nginx-traefik-converter/pkg/converters/models"
+	"github.com/traefik/traefik/v3/pkg/config/dynamic"
+)
+
+// defaultSessionCookieName is the cookie name used by ingress-nginx when session-cookie-name is not set,
+// it is kept so that existing sessions survive the migration.
+const defaultSessionCookieName = "INGRESSCOOKIE"
+
+// stickyCookie handles the below annotations.
+// Annotations:
+//   - "nginx.ingress.kubernetes.io/affinity"
+//   - "nginx.ingress.kubernetes.io/affinity-mode"
+//   - "nginx.ingress.kubernetes.io/session-cookie-name"
+//   - "nginx.ingress.kubernetes.io/session-cookie-path"
+//   - "nginx.ingress.kubernetes.io/session-cookie-samesite"
+//   - "nginx.ingress.kubernetes.io/session-cookie-secure"
+//   - "nginx.ingress.kubernetes.io/session-cookie-max-age"
+//   - "nginx.ingress.kubernetes.io/session-cookie-expires"
+//   - "nginx.ingress.kubernetes.io/session-cookie-change-on-failure"
+//
+// It returns the sticky configuration to set on the load balancers of the IngressRoute, nil when affinity is not enabled.
+//
+//nolint:funlen
+func stickyCookie(ctx configs.Context) *dynamic.Sticky {
+	annAffinity := string(models.Affinity)
+
+	affinity, ok := ctx.Annotations[annAffinity]
+	if !ok {
+		return nil
+	}
+
+	if strings.ToLower(strings.TrimSpace(affinity)) != "cookie" {
+		msg := "affinity " + affinity + " is not supported, only cookie based affinity can be converted"
+
+		ctx.Result.Warnings = append(ctx.Result.Warnings, msg)
+		ctx.ReportSkipped(annAffinity, msg)
+
+		return nil
+	}
+
+	cookie := &dynamic.Cookie{
+		Name:     defaultSessionCookieName,
+		HTTPOnly: true,
+	}
+
+	ctx.ReportConverted(annAffinity)
+
+	if mode, ok := ctx.Annotations[string(models.AffinityMode)]; ok {
+		if strings.ToLower(strings.TrimSpace(mode)) == "persistent" {
+			msg := "affinity-mode persistent has no Traefik equivalent; sticky sessions are rebalanced when servers " +
+				"are added or removed, as in the balanced mode"
+
+			ctx.Result.Warnings = append(ctx.Result.Warnings, msg)
+			ctx.ReportWarning(string(models.AffinityMode), msg)
+		} else {
+			ctx.ReportConverted(string(models.AffinityMode))
+		}
+	}
+
+	if name := strings.TrimSpace(ctx.Annotations[string(models.SessionCookieName)]); name != "" {
+		cookie.Name = name
+
+		ctx.ReportConverted(string(models.SessionCookieName))
+	}
+
+	if path := strings.TrimSpace(ctx.Annotations[string(models.SessionCookiePath)]); path != "" {
+		cookie.Path = &path
+
+		ctx.ReportConverted(string(models.SessionCookiePath))
+	}
+
+	if sameSite, ok := ctx.Annotations[string(models.SessionCookieSameSite)]; ok {
+		switch value := strings.ToLower(strings.TrimSpace(sameSite)); value {
+		case "none", "lax", "strict":
+			cookie.SameSite = value
+
+			ctx.ReportConverted(string(models.SessionCookieSameSite))
+		default:
+			msg := "session-cookie-samesite has unknown value " + sameSite + " and was ignored"
+
+			ctx.Result.Warnings = append(ctx.Result.Warnings, msg)
+			ctx.ReportSkipped(string(models.SessionCookieSameSite), msg)
+		}
+	}
+
+	if secure, ok := ctx.Annotations[string(models.SessionCookieSecure)]; ok {
+		cookie.Secure = strings.ToLower(strings.TrimSpace(secure)) == "true"
+
+		ctx.ReportConverted(string(models.SessionCookieSecure))
+	}
+
+	cookie.MaxAge = sessionCookieMaxAge(ctx)
+
+	if _, ok := ctx.Annotations[string(models.SessionCookieChangeOnFailure)]; ok {
+		msg := "session-cookie-change-on-failure has no Traefik equivalent; Traefik picks a new server " +
+			"only when the sticky server is no longer available"
+
+		ctx.Result.Warnings = append(ctx.Result.Warnings, msg)
+		ctx.ReportWarning(string(models.SessionCookieChangeOnFailure), msg)
+	}
+
+	return &dynamic.Sticky{Cookie: cookie}
+}
+
+// sessionCookieMaxAge returns the lifetime of the sticky cookie in seconds. Traefik only sets Max-Age,
+// so session-cookie-expires is used when session-cookie-max-age is not set.
+func sessionCookieMaxAge(ctx configs.Context) int {
+	annMaxAge := string(models.SessionCookieMaxAge)
+	annExpires := string(models.SessionCookieExpires)
+
+	maxAge, hasMaxAge := ctx.Annotations[annMaxAge]
+	expires, hasExpires := ctx.Annotations[annExpires]
+
+	var seconds int
+
+	if hasMaxAge {
+		value, err := strconv.Atoi(strings.TrimSpace(maxAge))
+		if err != nil {
+			msg := "session-cookie-max-age is not a number of seconds and was ignored"
+
+			ctx.Result.Warnings = append(ctx.Result.Warnings, msg)
+			ctx.ReportSkipped(annMaxAge, msg)
+		} else {
+			seconds = value
+
+			ctx.ReportConverted(annMaxAge)
+		}
+	}
+
+	if !hasExpires {
+		return seconds
+	}
+
+	if seconds > 0 {
+		ctx.ReportIgnored(annExpires, "Traefik only sets Max-Age on the sticky cookie, session-cookie-max-age takes precedence")
+
+		return seconds
+	}
+
+	value, err := strconv.Atoi(strings.TrimSpace(expires))
+	if err != nil {
+		msg := "session-cookie-expires is not a number of seconds and was ignored"
+
+		ctx.Result.Warnings = append(ctx.Result.Warnings, msg)
+		ctx.ReportSkipped(annExpires, msg)
+
+		return seconds
+	}
+
+	msg := "session-cookie-expires was converted to the Max-Age of the sticky cookie, Traefik does not set Expires"
+
+	ctx.Result.Warnings = append(ctx.Result.Warnings, msg)
+	ctx.ReportWarning(annExpires, msg)
+
+	return value
+}
@@ -0,0 +1,122 @@
+package ingressroute_test
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/nikhilsbhat/nginx-traefik-converter/pkg/configs"
+)
+
+func TestStickyCookie(t *testing.T) {
+	tests := []struct {
+		name        string
+		annotations map[string]string
+		cookie      string
+		path        string
+		sameSite    string
+		secure      bool
+		maxAge      int
+		statuses    map[string]configs.AnnotationStatus
+	}{
+		{
+			name:        "defaults keep the ingress-nginx cookie name",
+			annotations: map[string]string{"affinity": "cookie"},
+			cookie:      "INGRESSCOOKIE",
+			statuses:    map[string]configs.AnnotationStatus{"affinity": configs.AnnotationConverted},
+		},
+		{
+			name: "cookie attributes",
+			annotations: map[string]string{
+				"affinity":                         "cookie",
+				"affinity-mode":                    "balanced",
+				"session-cookie-name":              "route",
+				"session-cookie-path":              "/app",
+				"session-cookie-samesite":          "Strict",
+				"session-cookie-secure":            "true",
+				"session-cookie-max-age":           "3600",
+				"session-cookie-expires":           "7200",
+				"session-cookie-change-on-failure": "true",
+			},
+			cookie:   "route",
+			path:     "/app",
+			sameSite: "strict",
+			secure:   true,
+			maxAge:   3600,
+			statuses: map[string]configs.AnnotationStatus{
+				"affinity-mode":                    configs.AnnotationConverted,
+				"session-cookie-samesite":          configs.AnnotationConverted,
+				"session-cookie-max-age":           configs.AnnotationConverted,
+				"session-cookie-expires":           configs.AnnotationIgnored,
+				"session-cookie-change-on-failure": configs.AnnotationWarned,
+			},
+		},
+		{
+			name: "expires is used as max-age",
+			annotations: map[string]string{
+				"affinity":               "cookie",
+				"affinity-mode":          "persistent",
+				"session-cookie-expires": "7200",
+			},
+			cookie: "INGRESSCOOKIE",
+			maxAge: 7200,
+			statuses: map[string]configs.AnnotationStatus{
+				"affinity-mode":          configs.AnnotationWarned,
+				"session-cookie-expires": configs.AnnotationWarned,
+			},
+		},
+		{
+			name: "invalid values are skipped",
+			annotations: map[string]string{
+				"affinity":                "cookie",
+				"session-cookie-samesite": "sometimes",
+				"session-cookie-max-age":  "1h",
+			},
+			cookie: "INGRESSCOOKIE",
+			statuses: map[string]configs.AnnotationStatus{
+				"session-cookie-samesite": configs.AnnotationSkipped,
+				"session-cookie-max-age":  configs.AnnotationSkipped,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := newContext(tt.annotations, nil)
+
+			sticky := buildIngressRoute(t, ctx).Spec.Routes[0].Services[0].Sticky
+			if sticky == nil || sticky.Cookie == nil {
+				t.Fatal("no sticky cookie was configured")
+			}
+
+			cookie := sticky.Cookie
+
+			path := ""
+			if cookie.Path != nil {
+				path = *cookie.Path
+			}
+
+			if cookie.Name != tt.cookie || path != tt.path || cookie.SameSite != tt.sameSite ||
+				cookie.Secure != tt.secure || cookie.MaxAge != tt.maxAge || !cookie.HTTPOnly {
+				t.Errorf("cookie = %+v (path %q)", cookie, path)
+			}
+
+			for annotation, status := range tt.statuses {
+				if got := statuses(ctx, annotation); !slices.Equal(got, []configs.AnnotationStatus{status}) {
+					t.Errorf("%s statuses = %v, want [%s]", annotation, got, status)
+				}
+			}
+		})
+	}
+}
+
+func TestStickyCookieUnsupportedAffinity(t *testing.T) {
+	ctx := newContext(map[string]string{"affinity": "ip"}, nil)
+
+	if sticky := buildIngressRoute(t, ctx).Spec.Routes[0].Services[0].Sticky; sticky != nil {
+		t.Errorf("sticky = %+v, want none", sticky)
+	}
+
+	if got := statuses(ctx, "affinity"); !slices.Equal(got, []configs.AnnotationStatus{configs.AnnotationSkipped}) {
+		t.Errorf("affinity statuses = %v, want [skipped]", got)
+	}
+}
@@ -17,6 +17,10 @@ func NeedsIngressRoute(ann map[string]string) bool {
 		return true
 	}
 
+	if hasDefaultBackendRoute(ann) {
+		return true
+	}
+
 	return false
 }
 
@@ -54,13 +58,11 @@ func resolveScheme(annotations map[string]string) (string, error) {
 	}
 }
 
-func entryPointsForScheme(scheme string) []string {
-	switch scheme {
-	case "https":
+// entryPointsForTLS returns the entry points serving the routes, TLS routes are served on websecure.
+func entryPointsForTLS(servesTLS bool) []string {
+	if servesTLS {
 		return []string{"websecure"}
-	case "h2c":
-		return []string{"web"}
-	default:
-		return []string{"web"}
 	}
+
+	return []string{"web"}
 }
@@ -0,0 +1,297 @@
+package ingressroute
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/nikhilsbhat/nginx-traefik-converter/pkg/configs"
+	"github.com/nikhilsbhat/nginx-traefik-converter/pkg/converters/models"
+	"github.com/nikhilsbhat/nginx-traefik-converter/pkg/errors"
+	traefik "github.com/traefik/traefik/v3/pkg/provider/kubernetes/crd/traefikio/v1alpha1"
+	netv1 "k8s.io/api/networking/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+)
+
+const (
+	defaultCanaryWeightTotal = 100
+
+	// ingress-nginx routes to the canary when the header or cookie is set to "always",
+	// and never routes to it when set to "never".
+	canaryAlways = "always"
+	canaryNever  = "never"
+)
+
+var canaryAnnotations = []models.Annotation{
+	models.Canary,
+	models.CanaryWeight,
+	models.CanaryWeightTotal,
+	models.CanaryByHeader,
+	models.CanaryByHeaderValue,
+	models.CanaryByHeaderPattern,
+	models.CanaryByCookie,
+}
+
+// canaryConfig holds the parsed canary annotations of a canary ingress.
+type canaryConfig struct {
+	weight        int
+	weightTotal   int
+	header        string
+	headerValue   string
+	headerPattern string
+	cookie        string
+}
+
+// IsCanary reports whether the annotations mark the ingress as an ingress-nginx canary.
+func IsCanary(annotations map[string]string) bool {
+	return strings.ToLower(annotations[string(models.Canary)]) == "true"
+}
+
+// ReportCanary handles the below annotations of a canary ingress.
+// Annotations:
+//   - "nginx.ingress.kubernetes.io/canary"
+//   - "nginx.ingress.kubernetes.io/canary-weight"
+//   - "nginx.ingress.kubernetes.io/canary-weight-total"
+//   - "nginx.ingress.kubernetes.io/canary-by-header"
+//   - "nginx.ingress.kubernetes.io/canary-by-header-value"
+//   - "nginx.ingress.kubernetes.io/canary-by-header-pattern"
+//   - "nginx.ingress.kubernetes.io/canary-by-cookie"
+//
+// A canary ingress produces no resources of its own, its routes are merged into the ones of its primary.
+func ReportCanary(ctx configs.Context) {
+	if ctx.CanaryOf == nil {
+		msg := "canary ingress has no primary ingress with the same host and path; " +
+			"it was not converted as ingress-nginx would not route to it either"
+
+		ctx.Result.Warnings = append(ctx.Result.Warnings, msg)
+
+		for _, ann := range presentCanaryAnnotations(ctx) {
+			ctx.ReportSkipped(ann, msg)
+		}
+
+		return
+	}
+
+	ctx.ReportRouteMode(configs.RoutedByCanary)
+
+	cfg, err := parseCanary(ctx.Annotations)
+	if err != nil {
+		ctx.Result.Warnings = append(ctx.Result.Warnings, err.Error())
+
+		for _, ann := range presentCanaryAnnotations(ctx) {
+			ctx.ReportSkipped(ann, err.Error())
+		}
+
+		return
+	}
+
+	for _, ann := range presentCanaryAnnotations(ctx) {
+		if ann == string(models.CanaryByHeaderPattern) && cfg.headerValue == "" {
+			if _, err = regexp.Compile(cfg.headerPattern); err != nil {
+				msg := "canary-by-header-pattern is not a valid Go regex for Traefik HeaderRegexp: " + err.Error()
+
+				ctx.Result.Warnings = append(ctx.Result.Warnings, msg)
+				ctx.ReportSkipped(ann, msg)
+
+				continue
+			}
+		}
+
+		ctx.ReportConverted(ann)
+	}
+
+	// ingress-nginx only honours the canary annotations on canary ingresses, the rest come from the primary.
+	for annotation := range ctx.Annotations {
+		if !strings.HasPrefix(annotation, models.NginxAnnotationPrefix) || ctx.IsReported(annotation) {
+			continue
+		}
+
+		ctx.ReportIgnored(annotation,
+			"annotations of canary ingresses are not applied by ingress-nginx, the configuration of "+
+				ctx.CanaryOf.Name+" is used")
+	}
+
+	ctx.Result.Warnings = append(ctx.Result.Warnings,
+		fmt.Sprintf("canary routes were merged into the IngressRoute of the primary ingress %s/%s",
+			ctx.CanaryOf.Namespace, ctx.CanaryOf.Name),
+	)
+}
+
+func presentCanaryAnnotations(ctx configs.Context) []string {
+	present := make([]string, 0, len(canaryAnnotations))
+
+	for _, ann := range canaryAnnotations {
+		if _, ok := ctx.Annotations[string(ann)]; ok {
+			present = append(present, string(ann))
+		}
+	}
+
+	return present
+}
+
+func parseCanary(annotations map[string]string) (*canaryConfig, error) {
+	cfg := &canaryConfig{
+		weightTotal:   defaultCanaryWeightTotal,
+		header:        strings.TrimSpace(annotations[string(models.CanaryByHeader)]),
+		headerValue:   strings.TrimSpace(annotations[string(models.CanaryByHeaderValue)]),
+		headerPattern: strings.TrimSpace(annotations[string(models.CanaryByHeaderPattern)]),
+		cookie:        strings.TrimSpace(annotations[string(models.CanaryByCookie)]),
+	}
+
+	if val, ok := annotations[string(models.CanaryWeight)]; ok {
+		weight, err := strconv.Atoi(strings.TrimSpace(val))
+		if err != nil || weight < 0 {
+			return nil, &errors.ConverterError{Message: fmt.Sprintf("invalid canary-weight %q", val)}
+		}
+
+		cfg.weight = weight
+	}
+
+	if val, ok := annotations[string(models.CanaryWeightTotal)]; ok {
+		total, err := strconv.Atoi(strings.TrimSpace(val))
+		if err != nil || total <= 0 {
+			return nil, &errors.ConverterError{Message: fmt.Sprintf("invalid canary-weight-total %q", val)}
+		}
+
+		cfg.weightTotal = total
+	}
+
+	return cfg, nil
+}
+
+// canaryRoutes returns the routes sending traffic of the base route to the canaries paired with the ingress.
+// Header and cookie based canaries get routes with a priority just above the base route, so that more specific
+// paths of the primary keep precedence. Weight based canaries turn the service of the base route into a
+// weighted TraefikService.
+//
+//nolint:funlen
+func canaryRoutes(ctx configs.Context, host string, path netv1.HTTPIngressPath, base *traefik.Route) []traefik.Route {
+	for _, canary := range ctx.Canaries {
+		backend := canaryBackend(canary, host, path.Path)
+		if backend == nil {
+			continue
+		}
+
+		cfg, err := parseCanary(canary.Annotations)
+		if err != nil {
+			return nil
+		}
+
+		primary := base.Services[0]
+		canarySvc := primary
+		canarySvc.Name = backend.Name
+		canarySvc.Port = intstr.IntOrString{Type: intstr.Int, IntVal: backend.Port.Number}
+
+		priority := len(base.Match) + 1
+		routes := make([]traefik.Route, 0)
+
+		newRoute := func(matcher string, svc traefik.Service) traefik.Route {
+			route := *base
+			route.Match = base.Match + " && " + matcher
+			route.Priority = priority
+			route.Services = []traefik.Service{svc}
+
+			return route
+		}
+
+		// "never" only matters when the remaining traffic is split by weight.
+		pinPrimary := cfg.weight > 0
+
+		switch {
+		case cfg.header != "" && cfg.headerValue != "":
+			routes = append(routes, newRoute(fmt.Sprintf("Header(`%s`, `%s`)", cfg.header, cfg.headerValue), canarySvc))
+		case cfg.header != "" && cfg.headerPattern != "":
+			if _, err = regexp.Compile(cfg.headerPattern); err == nil {
+				routes = append(routes, newRoute(fmt.Sprintf("HeaderRegexp(`%s`, `%s`)", cfg.header, cfg.headerPattern), canarySvc))
+			}
+		case cfg.header != "":
+			routes = append(routes, newRoute(fmt.Sprintf("Header(`%s`, `%s`)", cfg.header, canaryAlways), canarySvc))
+
+			if pinPrimary {
+				routes = append(routes, newRoute(fmt.Sprintf("Header(`%s`, `%s`)", cfg.header, canaryNever), primary))
+			}
+		}
+
+		if cfg.cookie != "" {
+			routes = append(routes, newRoute(cookieMatcher(cfg.cookie, canaryAlways), canarySvc))
+
+			if pinPrimary {
+				routes = append(routes, newRoute(cookieMatcher(cfg.cookie, canaryNever), primary))
+			}
+		}
+
+		if cfg.weight > 0 {
+			base.Services = []traefik.Service{weightedService(ctx, primary, canarySvc, cfg)}
+		}
+
+		return routes
+	}
+
+	return nil
+}
+
+// canaryBackend returns the backend of the canary ingress serving the given host and path.
+func canaryBackend(canary *netv1.Ingress, host, path string) *netv1.IngressServiceBackend {
+	for _, rule := range canary.Spec.Rules {
+		if rule.HTTP == nil || rule.Host != host {
+			continue
+		}
+
+		for _, canaryPath := range rule.HTTP.Paths {
+			if canaryPath.Path == path && canaryPath.Backend.Service != nil {
+				return canaryPath.Backend.Service
+			}
+		}
+	}
+
+	return nil
+}
+
+func cookieMatcher(cookie, value string) string {
+	return fmt.Sprintf("HeaderRegexp(`Cookie`, `(^|;\\s*)%s=%s(;|$)`)", regexp.QuoteMeta(cookie), value)
+}
+
+// weightedService registers a TraefikService splitting the traffic between the primary and the canary service
+// and returns the reference to it.
+func weightedService(ctx configs.Context, primary, canary traefik.Service, cfg *canaryConfig) traefik.Service {
+	canaryWeight := min(cfg.weight, cfg.weightTotal)
+	primaryWeight := cfg.weightTotal - canaryWeight
+
+	name := fmt.Sprintf("%s-canary-%s-%d", ctx.IngressName, canary.Name, canary.Port.IntVal)
+
+	reference := traefik.Service{
+		LoadBalancerSpec: traefik.LoadBalancerSpec{
+			Name: name,
+			Kind: "TraefikService",
+		},
+	}
+
+	for _, svc := range ctx.Result.TraefikServices {
+		if svc.Name == name {
+			return reference
+		}
+	}
+
+	primary.Weight = &primaryWeight
+	canary.Weight = &canaryWeight
+
+	ctx.Result.TraefikServices = append(ctx.Result.TraefikServices, &traefik.TraefikService{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: traefik.SchemeGroupVersion.String(),
+			Kind:       "TraefikService",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: ctx.Namespace,
+		},
+		Spec: traefik.TraefikServiceSpec{
+			Weighted: &traefik.WeightedRoundRobin{
+				Services: []traefik.Service{primary, canary},
+			},
+		},
+	})
+
+	return reference
+}
@@ -0,0 +1,67 @@
+package ingressroute
+
+import (
+	"slices"
+	"strings"
+
+	"github.com/nikhilsbhat/nginx-traefik-converter/pkg/configs"
+	"github.com/nikhilsbhat/nginx-traefik-converter/pkg/converters/middleware"
+	"github.com/nikhilsbhat/nginx-traefik-converter/pkg/converters/models"
+	traefik "github.com/traefik/traefik/v3/pkg/provider/kubernetes/crd/traefikio/v1alpha1"
+)
+
+// defaultBackendPriority keeps the catch-all routes below every route generated from the ingress paths.
+const defaultBackendPriority = 1
+
+// hasDefaultBackendRoute reports whether the default-backend annotation is converted into a catch-all route.
+// Together with custom-http-errors the default backend only serves the error pages.
+func hasDefaultBackendRoute(annotations map[string]string) bool {
+	if _, ok := annotations[string(models.CustomHTTPErrors)]; ok {
+		return false
+	}
+
+	return strings.TrimSpace(annotations[string(models.DefaultBackend)]) != ""
+}
+
+// defaultBackendRoutes adds a lowest priority route per host of the ingress sending
+// the requests matching none of its paths to the default-backend Service.
+func defaultBackendRoutes(ctx configs.Context, groups *[]*routeGroup) {
+	if !hasDefaultBackendRoute(ctx.Annotations) {
+		return
+	}
+
+	service, ok := middleware.DefaultBackendService(ctx)
+	if !ok {
+		return
+	}
+
+	hosts := make([]string, 0, len(ctx.Ingress.Spec.Rules))
+
+	for _, rule := range ctx.Ingress.Spec.Rules {
+		if !slices.Contains(hosts, rule.Host) {
+			hosts = append(hosts, rule.Host)
+		}
+	}
+
+	// An ingress without rules only has spec.defaultBackend, the catch-all route then matches every host.
+	if len(hosts) == 0 {
+		hosts = append(hosts, "")
+	}
+
+	for _, host := range hosts {
+		group := groupFor(groups, ctx.Ingress.Spec.TLS, host)
+		group.routes = append(group.routes, traefik.Route{
+			Kind:        "Rule",
+			Match:       combineMatch(buildHostMatch(host, middleware.WWWCounterpart(ctx, host)), ""),
+			Priority:    defaultBackendPriority,
+			Services:    []traefik.Service{service},
+			Middlewares: middlewareRefs(ctx),
+		})
+	}
+
+	msg := "ingress-nginx only uses default-backend when the Services of the ingress have no ready endpoints; " +
+		"the generated catch-all routes serve every request on the ingress hosts that matches none of its paths"
+
+	ctx.Result.Warnings = append(ctx.Result.Warnings, msg)
+	ctx.ReportWarning(string(models.DefaultBackend), msg)
+}
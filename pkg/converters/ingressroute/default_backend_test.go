@@ -0,0 +1,39 @@
+package ingressroute_test
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/nikhilsbhat/nginx-traefik-converter/pkg/configs"
+)
+
+func TestDefaultBackendRoute(t *testing.T) {
+	ctx := newContext(map[string]string{"default-backend": "fallback"}, nil)
+
+	routes := buildIngressRoute(t, ctx).Spec.Routes
+	if len(routes) != 2 {
+		t.Fatalf("routes = %d, want the path route and the catch-all route", len(routes))
+	}
+
+	catchAll := routes[1]
+
+	if catchAll.Match != "Host(`app.example.com`)" || catchAll.Priority != 1 {
+		t.Errorf("catch-all route = %q with priority %d", catchAll.Match, catchAll.Priority)
+	}
+
+	if catchAll.Services[0].Name != "fallback" || catchAll.Services[0].Port.IntValue() != 80 {
+		t.Errorf("catch-all service = %+v, want fallback:80", catchAll.Services[0])
+	}
+
+	if got := statuses(ctx, "default-backend"); !slices.Equal(got, []configs.AnnotationStatus{configs.AnnotationWarned}) {
+		t.Errorf("default-backend statuses = %v, want [warning]", got)
+	}
+}
+
+func TestDefaultBackendServesErrorPagesOnly(t *testing.T) {
+	ctx := newContext(map[string]string{"default-backend": "fallback", "custom-http-errors": "404"}, nil)
+
+	if routes := buildIngressRoute(t, ctx).Spec.Routes; len(routes) != 1 {
+		t.Errorf("routes = %d, want no catch-all route with custom-http-errors", len(routes))
+	}
+}
@@ -0,0 +1,152 @@
+package ingressroute
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/nikhilsbhat/nginx-traefik-converter/pkg/configs"
+	"github.com/nikhilsbhat/nginx-traefik-converter/pkg/converters/middleware"
+	"github.com/nikhilsbhat/nginx-traefik-converter/pkg/converters/models"
+	"github.com/nikhilsbhat/nginx-traefik-converter/pkg/converters/transport"
+	netv1 "k8s.io/api/networking/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	traefikIngressClass = "traefik"
+
+	ingressClassAnnotation  = "kubernetes.io/ingress.class"
+	lastAppliedAnnotation   = "kubectl.kubernetes.io/last-applied-configuration"
+	routerMiddlewares       = "traefik.ingress.kubernetes.io/router.middlewares"
+	routerTLS               = "traefik.ingress.kubernetes.io/router.tls"
+	routerTLSOptions        = "traefik.ingress.kubernetes.io/router.tls.options"
+	kubernetesCRDProviderID = "@kubernetescrd"
+
+	httpsRedirectWarning = "TLS hosts are served on the websecure entry point only; " +
+		"configure an HTTP to HTTPS redirection on the web entry point as ingress-nginx does for TLS hosts"
+)
+
+// BuildIngress generates a copy of the source Ingress served by Traefik.
+// The copy uses the traefik ingress class, drops the nginx annotations and references
+// the generated middlewares, TLS and entry points through traefik router annotations.
+func BuildIngress(ctx configs.Context) {
+	ing := ctx.Ingress.DeepCopy()
+
+	ingressClass := traefikIngressClass
+
+	ing.TypeMeta = metav1.TypeMeta{
+		APIVersion: netv1.SchemeGroupVersion.String(),
+		Kind:       "Ingress",
+	}
+	ing.ObjectMeta = metav1.ObjectMeta{
+		Name:        ing.Name,
+		Namespace:   ing.Namespace,
+		Labels:      ing.Labels,
+		Annotations: traefikAnnotations(ctx),
+	}
+	ing.Spec.IngressClassName = &ingressClass
+	ing.Spec.Rules = withWWWCounterparts(ctx, ing.Spec.Rules)
+	ing.Status = netv1.IngressStatus{}
+
+	if strings.ToLower(ctx.Annotations[string(models.UseRegex)]) == "true" {
+		msg := "use-regex is not supported by the Traefik Ingress provider, paths are matched as prefixes; " +
+			"regex paths require an IngressRoute with PathRegexp matchers"
+
+		ctx.Result.Warnings = append(ctx.Result.Warnings, msg)
+		ctx.ReportWarning(string(models.UseRegex), msg)
+	}
+
+	if hasDefaultBackendRoute(ctx.Annotations) {
+		msg := "default-backend is converted into a catch-all route, which the Traefik Ingress provider cannot express; " +
+			"use --route-mode ingressroute to convert it"
+
+		ctx.Result.Warnings = append(ctx.Result.Warnings, msg)
+		ctx.ReportSkipped(string(models.DefaultBackend), msg)
+	}
+
+	if len(ing.Spec.TLS) > 0 {
+		ctx.Result.Warnings = append(ctx.Result.Warnings, httpsRedirectWarning)
+	}
+
+	if ref := transport.Reference(ctx); ref != "" {
+		ctx.Result.Warnings = append(ctx.Result.Warnings,
+			"the Traefik Ingress provider reads the ServersTransport from the backend Services; annotate them with "+
+				"traefik.ingress.kubernetes.io/service.serverstransport: "+crdReference(ctx.Namespace, ref),
+		)
+	}
+
+	ctx.Result.Ingresses = append(ctx.Result.Ingresses, ing)
+}
+
+// withWWWCounterparts adds a copy of every rule for the host redirected to it by from-to-www-redirect,
+// so that the redirect middleware gets to see the requests of the counterpart host.
+func withWWWCounterparts(ctx configs.Context, rules []netv1.IngressRule) []netv1.IngressRule {
+	hosts := make(map[string]struct{}, len(rules))
+
+	for _, rule := range rules {
+		hosts[rule.Host] = struct{}{}
+	}
+
+	out := slices.Clone(rules)
+
+	for _, rule := range rules {
+		counterpart := middleware.WWWCounterpart(ctx, rule.Host)
+		if counterpart == "" {
+			continue
+		}
+
+		if _, exists := hosts[counterpart]; exists {
+			continue
+		}
+
+		alias := *rule.DeepCopy()
+		alias.Host = counterpart
+
+		out = append(out, alias)
+	}
+
+	return out
+}
+
+func traefikAnnotations(ctx configs.Context) map[string]string {
+	annotations := make(map[string]string)
+
+	for key, value := range ctx.Annotations {
+		if strings.HasPrefix(key, models.NginxAnnotationPrefix) || key == ingressClassAnnotation || key == lastAppliedAnnotation {
+			continue
+		}
+
+		annotations[key] = value
+	}
+
+	if refs := middlewareRefs(ctx); len(refs) > 0 {
+		names := make([]string, 0, len(refs))
+
+		for _, ref := range refs {
+			names = append(names, crdReference(ctx.Namespace, ref.Name))
+		}
+
+		annotations[routerMiddlewares] = strings.Join(names, ",")
+	}
+
+	if len(ctx.Ingress.Spec.TLS) == 0 {
+		annotations[configs.RouterEntryPointsAnnotation] = configs.EntryPointWeb
+
+		return annotations
+	}
+
+	annotations[configs.RouterEntryPointsAnnotation] = configs.EntryPointWebSecure
+	annotations[routerTLS] = "true"
+
+	if opt, ok := ctx.Result.TLSOptionRefs[ctx.IngressName]; ok {
+		annotations[routerTLSOptions] = crdReference(ctx.Namespace, opt)
+	}
+
+	return annotations
+}
+
+// crdReference returns the reference to a Traefik CRD as expected by the traefik router annotations.
+func crdReference(namespace, name string) string {
+	return fmt.Sprintf("%s-%s%s", namespace, name, kubernetesCRDProviderID)
+}
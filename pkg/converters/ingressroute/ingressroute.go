@@ -3,22 +3,38 @@ package ingressroute
 import (
 	"fmt"
 	"regexp"
+	"slices"
 	"strings"
 
 	"github.com/nikhilsbhat/nginx-traefik-converter/pkg/configs"
+	"github.com/nikhilsbhat/nginx-traefik-converter/pkg/converters/middleware"
 	"github.com/nikhilsbhat/nginx-traefik-converter/pkg/converters/models"
 	"github.com/nikhilsbhat/nginx-traefik-converter/pkg/converters/tls"
+	"github.com/nikhilsbhat/nginx-traefik-converter/pkg/converters/transport"
 	traefik "github.com/traefik/traefik/v3/pkg/provider/kubernetes/crd/traefikio/v1alpha1"
+	"github.com/traefik/traefik/v3/pkg/types"
 	netv1 "k8s.io/api/networking/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/util/intstr"
 )
 
+// routeGroup holds the routes served with the same TLS configuration.
+type routeGroup struct {
+	tls    *netv1.IngressTLS
+	hosts  []string
+	routes []traefik.Route
+}
+
 // BuildIngressRoute handles the below annotations.
 // Annotations:
 //   - "nginx.ingress.kubernetes.io/backend-protocol"
 //   - "nginx.ingress.kubernetes.io/grpc-backend"
 //   - "nginx.ingress.kubernetes.io/use-regex"
+//   - "nginx.ingress.kubernetes.io/default-backend" (without custom-http-errors)
+//   - the session affinity annotations handled by stickyCookie
+//
+// Routes are grouped by the spec.tls entry covering their host, one IngressRoute is generated per group.
+// Routes of hosts covered by spec.tls are served on websecure with the referenced certificate, the others on web.
 func BuildIngressRoute(ctx configs.Context) error {
 	ing := ctx.Ingress
 
@@ -29,8 +45,9 @@ func BuildIngressRoute(ctx configs.Context) error {
 	}
 
 	useRegex := strings.ToLower(ctx.Annotations[string(models.UseRegex)]) == "true"
+	sticky := stickyCookie(ctx)
 
-	routes := make([]traefik.Route, 0)
+	groups := make([]*routeGroup, 0)
 	seen := make(map[string]struct{}) // dedup key set
 
 	for _, rule := range ing.Spec.Rules {
@@ -38,7 +55,8 @@ func BuildIngressRoute(ctx configs.Context) error {
 			continue
 		}
 
-		hostMatch := buildHostMatch(rule.Host)
+		hostMatch := buildHostMatch(rule.Host, middleware.WWWCounterpart(ctx, rule.Host))
+		group := groupFor(&groups, ing.Spec.TLS, rule.Host)
 
 		for _, path := range rule.HTTP.Paths {
 			svc := path.Backend.Service
@@ -85,101 +103,221 @@ func BuildIngressRoute(ctx configs.Context) error {
 								Type:   intstr.Int,
 								IntVal: svc.Port.Number,
 							},
-							Scheme: scheme,
+							Scheme:           scheme,
+							Sticky:           sticky,
+							ServersTransport: transport.Reference(ctx),
 						},
 					},
 				},
 				Middlewares: middlewareRefs(ctx),
 			}
 
-			routes = append(routes, route)
+			group.routes = append(group.routes, canaryRoutes(ctx, rule.Host, path, &route)...)
+			group.routes = append(group.routes, route)
 		}
 	}
 
-	if len(routes) == 0 {
+	defaultBackendRoutes(ctx, &groups)
+
+	groups = nonEmptyGroups(groups)
+	if len(groups) == 0 {
 		return nil
 	}
 
-	ingressRoute := &traefik.IngressRoute{
-		TypeMeta: metav1.TypeMeta{
-			APIVersion: traefik.SchemeGroupVersion.String(),
-			Kind:       "IngressRoute",
-		},
-		ObjectMeta: metav1.ObjectMeta{
-			Name:      ing.Name,
-			Namespace: ing.Namespace,
-		},
-		Spec: traefik.IngressRouteSpec{
-			EntryPoints: entryPointsForScheme(scheme),
-			Routes:      routes,
-		},
+	var servesTLS bool
+
+	for _, group := range groups {
+		ingressRoute := &traefik.IngressRoute{
+			TypeMeta: metav1.TypeMeta{
+				APIVersion: traefik.SchemeGroupVersion.String(),
+				Kind:       "IngressRoute",
+			},
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      groupName(ing.Name, group, len(groups)),
+				Namespace: ing.Namespace,
+			},
+			Spec: traefik.IngressRouteSpec{
+				EntryPoints: entryPointsForTLS(group.tls != nil),
+				Routes:      group.routes,
+				TLS:         buildTLS(group),
+			},
+		}
+
+		if group.tls != nil {
+			servesTLS = true
+
+			tls.ApplyTLSOption(ingressRoute, ctx)
+		}
+
+		ctx.Result.IngressRoutes = append(ctx.Result.IngressRoutes, ingressRoute)
 	}
 
-	// Apply TLS only if scheme requires it (as discussed earlier)
-	tls.ApplyTLSOption(ingressRoute, ctx, scheme)
+	if servesTLS {
+		ctx.Result.Warnings = append(ctx.Result.Warnings, httpsRedirectWarning)
+	}
 
-	ctx.Result.IngressRoutes = append(ctx.Result.IngressRoutes, ingressRoute)
+	if _, ok := ctx.Result.TLSOptionRefs[ctx.IngressName]; ok && !servesTLS {
+		ctx.Result.Warnings = append(ctx.Result.Warnings,
+			"the TLSOption generated for client certificate verification was not applied, the ingress has no spec.tls section",
+		)
+	}
 
 	if useRegex {
 		ctx.ReportConverted(string(models.UseRegex))
 	}
 
-	ctx.ReportConverted(string(models.UseRegex))
-
 	return nil
 }
 
-func middlewareRefs(ctx configs.Context) []traefik.MiddlewareRef {
-	return orderMiddlewares(ctx.Result.Middlewares)
+// groupFor returns the route group of the spec.tls entry covering the host,
+// creating it if it does not exist yet. Hosts not covered by spec.tls share the plain group.
+func groupFor(groups *[]*routeGroup, ingressTLS []netv1.IngressTLS, host string) *routeGroup {
+	entry := tlsForHost(ingressTLS, host)
+
+	for _, group := range *groups {
+		if sameTLS(group.tls, entry) {
+			group.addHost(host)
+
+			return group
+		}
+	}
+
+	group := &routeGroup{tls: entry}
+	group.addHost(host)
+
+	*groups = append(*groups, group)
+
+	return group
 }
 
-//nolint:varnamelen
-func orderMiddlewares(mws []*traefik.Middleware) []traefik.MiddlewareRef {
-	var (
-		conditional *traefik.Middleware
-		cors        *traefik.Middleware
-		rest        []*traefik.Middleware
-	)
+func (group *routeGroup) addHost(host string) {
+	if host == "" || slices.Contains(group.hosts, host) {
+		return
+	}
 
-	for _, mw := range mws {
-		name := mw.GetName()
+	group.hosts = append(group.hosts, host)
+}
 
-		switch {
-		case strings.Contains(name, "conditional-return"):
-			conditional = mw
+func nonEmptyGroups(groups []*routeGroup) []*routeGroup {
+	out := make([]*routeGroup, 0, len(groups))
 
-		case strings.Contains(name, "cors") || strings.Contains(name, "headers"):
-			// your CORS/snippet headers middleware
-			cors = mw
+	for _, group := range groups {
+		if len(group.routes) > 0 {
+			out = append(out, group)
+		}
+	}
 
-		default:
-			rest = append(rest, mw)
+	return out
+}
+
+// tlsForHost returns the spec.tls entry covering the host. An entry without hosts covers every host.
+func tlsForHost(ingressTLS []netv1.IngressTLS, host string) *netv1.IngressTLS {
+	for index := range ingressTLS {
+		entry := &ingressTLS[index]
+
+		if len(entry.Hosts) == 0 {
+			return entry
+		}
+
+		for _, tlsHost := range entry.Hosts {
+			if hostMatches(tlsHost, host) {
+				return entry
+			}
 		}
 	}
 
-	refs := make([]traefik.MiddlewareRef, 0, len(mws))
+	return nil
+}
 
-	if conditional != nil {
-		refs = append(refs, traefik.MiddlewareRef{Name: conditional.GetName()})
+func hostMatches(tlsHost, host string) bool {
+	if strings.EqualFold(tlsHost, host) {
+		return true
 	}
 
-	if cors != nil {
-		refs = append(refs, traefik.MiddlewareRef{Name: cors.GetName()})
+	// Wildcard hosts cover exactly one label, as in "*.example.com".
+	if suffix, ok := strings.CutPrefix(tlsHost, "*."); ok {
+		label, rest, found := strings.Cut(host, ".")
+
+		return found && label != "" && strings.EqualFold(rest, suffix)
+	}
+
+	return false
+}
+
+func sameTLS(left, right *netv1.IngressTLS) bool {
+	if left == nil || right == nil {
+		return left == right
+	}
+
+	return left.SecretName == right.SecretName
+}
+
+// groupName keeps the ingress name when a single IngressRoute is generated,
+// otherwise the routes are named after their certificate secret.
+func groupName(name string, group *routeGroup, groupCount int) string {
+	switch {
+	case groupCount == 1:
+		return name
+	case group.tls == nil:
+		return name + "-http"
+	case group.tls.SecretName == "":
+		return name + "-tls"
+	default:
+		return name + "-" + group.tls.SecretName
+	}
+}
+
+func buildTLS(group *routeGroup) *traefik.TLS {
+	if group.tls == nil {
+		return nil
 	}
 
-	for _, mw := range rest {
-		refs = append(refs, traefik.MiddlewareRef{Name: mw.GetName()})
+	routeTLS := &traefik.TLS{
+		SecretName: group.tls.SecretName,
+	}
+
+	if len(group.hosts) > 0 {
+		routeTLS.Domains = []types.Domain{
+			{
+				Main: group.hosts[0],
+				SANs: group.hosts[1:],
+			},
+		}
+	}
+
+	return routeTLS
+}
+
+// middlewareRefs references the middlewares of the ingress in the order of the result,
+// which the converters sorted by the phase NGINX applies them in.
+func middlewareRefs(ctx configs.Context) []traefik.MiddlewareRef {
+	refs := make([]traefik.MiddlewareRef, 0, len(ctx.Result.Middlewares))
+
+	for _, middleware := range ctx.Result.Middlewares {
+		refs = append(refs, traefik.MiddlewareRef{Name: middleware.GetName()})
 	}
 
 	return refs
 }
 
-func buildHostMatch(host string) string {
-	if host == "" {
-		return ""
+// buildHostMatch matches any of the given hosts, empty hosts are ignored.
+func buildHostMatch(hosts ...string) string {
+	matchers := make([]string, 0, len(hosts))
+
+	for _, host := range hosts {
+		if host != "" {
+			matchers = append(matchers, fmt.Sprintf("Host(`%s`)", host))
+		}
 	}
 
-	return fmt.Sprintf("Host(`%s`)", host)
+	switch len(matchers) {
+	case 0:
+		return ""
+	case 1:
+		return matchers[0]
+	default:
+		return "(" + strings.Join(matchers, " || ") + ")"
+	}
 }
 
 func buildPathMatch(path netv1.HTTPIngressPath, useRegex bool) (string, bool) {
@@ -0,0 +1,139 @@
+package ingressroute
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+
+	"github.com/nikhilsbhat/nginx-traefik-converter/pkg/configs"
+	"github.com/nikhilsbhat/nginx-traefik-converter/pkg/converters/models"
+	traefik "github.com/traefik/traefik/v3/pkg/provider/kubernetes/crd/traefikio/v1alpha1"
+	netv1 "k8s.io/api/networking/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+)
+
+// IsPassthrough reports whether the annotations ask ingress-nginx to pass TLS through to the backend.
+func IsPassthrough(annotations map[string]string) bool {
+	return strings.ToLower(strings.TrimSpace(annotations[string(models.SSLPassthrough)])) == "true"
+}
+
+// BuildPassthrough handles the below annotations.
+// Annotations:
+//   - "nginx.ingress.kubernetes.io/ssl-passthrough"
+//
+// The ingress is converted into an IngressRouteTCP routing on the SNI of the TLS connection, which is
+// passed through to the backend. As in ingress-nginx, a host is served by the backend of its first path.
+// All the other nginx annotations are ignored, as they require terminating TLS.
+func BuildPassthrough(ctx configs.Context) {
+	ann := string(models.SSLPassthrough)
+
+	routes := make([]traefik.RouteTCP, 0)
+	hosts := make([]string, 0)
+
+	for _, rule := range ctx.Ingress.Spec.Rules {
+		if rule.HTTP == nil || len(rule.HTTP.Paths) == 0 {
+			continue
+		}
+
+		if rule.Host == "" {
+			ctx.Result.Warnings = append(ctx.Result.Warnings,
+				"ssl-passthrough routes on the SNI of the connection, the rule without host was not converted",
+			)
+
+			continue
+		}
+
+		if slices.Contains(hosts, rule.Host) {
+			continue
+		}
+
+		backend := rule.HTTP.Paths[0].Backend.Service
+		if backend == nil {
+			continue
+		}
+
+		if len(rule.HTTP.Paths) > 1 {
+			ctx.Result.Warnings = append(ctx.Result.Warnings,
+				fmt.Sprintf("ssl-passthrough cannot route on paths, %s is served by the backend of its first path %s",
+					rule.Host, backend.Name),
+			)
+		}
+
+		hosts = append(hosts, rule.Host)
+		routes = append(routes, traefik.RouteTCP{
+			Match: fmt.Sprintf("HostSNI(`%s`)", rule.Host),
+			Services: []traefik.ServiceTCP{
+				{
+					Name: backend.Name,
+					Port: tcpServicePort(backend.Port),
+				},
+			},
+		})
+	}
+
+	reportPassthroughIgnored(ctx)
+
+	if len(routes) == 0 {
+		msg := "ssl-passthrough is set but the ingress has no rule with a host and a backend; nothing was converted"
+
+		ctx.Result.Warnings = append(ctx.Result.Warnings, msg)
+		ctx.ReportSkipped(ann, msg)
+		ctx.ReportRouteMode(configs.RoutedByNone)
+
+		return
+	}
+
+	ctx.Result.IngressRoutesTCP = append(ctx.Result.IngressRoutesTCP, &traefik.IngressRouteTCP{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: traefik.SchemeGroupVersion.String(),
+			Kind:       "IngressRouteTCP",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      ctx.IngressName,
+			Namespace: ctx.Namespace,
+		},
+		Spec: traefik.IngressRouteTCPSpec{
+			EntryPoints: entryPointsForTLS(true),
+			Routes:      routes,
+			TLS: &traefik.TLSTCP{
+				Passthrough: true,
+			},
+		},
+	})
+
+	if len(ctx.Ingress.Spec.TLS) > 0 {
+		ctx.Result.Warnings = append(ctx.Result.Warnings,
+			"spec.tls is not used with ssl-passthrough, the backend presents its own certificate",
+		)
+	}
+
+	ctx.Result.Warnings = append(ctx.Result.Warnings,
+		"ssl-passthrough hosts are only served on the websecure entry point; plain HTTP requests to them are not routed",
+	)
+
+	ctx.ReportConverted(ann)
+	ctx.ReportRouteMode(configs.RoutedByPassthrough)
+}
+
+// reportPassthroughIgnored reports the nginx annotations that cannot apply to a passed through TLS connection.
+func reportPassthroughIgnored(ctx configs.Context) {
+	ann := string(models.SSLPassthrough)
+
+	for _, annotation := range slices.Sorted(maps.Keys(ctx.Annotations)) {
+		if annotation == ann || !strings.HasPrefix(annotation, models.NginxAnnotationPrefix) {
+			continue
+		}
+
+		ctx.ReportIgnored(annotation, "ssl-passthrough does not terminate TLS, HTTP annotations cannot apply")
+	}
+}
+
+func tcpServicePort(port netv1.ServiceBackendPort) intstr.IntOrString {
+	if port.Name != "" {
+		return intstr.FromString(port.Name)
+	}
+
+	return intstr.FromInt32(port.Number)
+}
@@ -0,0 +1,72 @@
+package ingressroute_test
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/nikhilsbhat/nginx-traefik-converter/pkg/configs"
+	"github.com/nikhilsbhat/nginx-traefik-converter/pkg/converters/ingressroute"
+	netv1 "k8s.io/api/networking/v1"
+)
+
+func TestBuildPassthrough(t *testing.T) {
+	ctx := newContext(map[string]string{"ssl-passthrough": "true", "enable-cors": "true"}, nil)
+
+	rule := &ctx.Ingress.Spec.Rules[0]
+	rule.HTTP.Paths = append(rule.HTTP.Paths, netv1.HTTPIngressPath{
+		Path: "/api",
+		Backend: netv1.IngressBackend{
+			Service: &netv1.IngressServiceBackend{Name: "api", Port: netv1.ServiceBackendPort{Name: "https"}},
+		},
+	})
+	ctx.Ingress.Spec.Rules = append(ctx.Ingress.Spec.Rules, netv1.IngressRule{})
+
+	if !ingressroute.IsPassthrough(ctx.Annotations) {
+		t.Fatal("IsPassthrough() = false, want true")
+	}
+
+	ingressroute.BuildPassthrough(ctx)
+
+	if len(ctx.Result.IngressRoutesTCP) != 1 {
+		t.Fatalf("IngressRoutesTCP = %d, want 1", len(ctx.Result.IngressRoutesTCP))
+	}
+
+	spec := ctx.Result.IngressRoutesTCP[0].Spec
+
+	if !slices.Equal(spec.EntryPoints, []string{"websecure"}) || spec.TLS == nil || !spec.TLS.Passthrough {
+		t.Errorf("spec = entry points %v, tls %+v, want websecure with passthrough", spec.EntryPoints, spec.TLS)
+	}
+
+	// a host is served by the backend of its first path
+	if len(spec.Routes) != 1 || spec.Routes[0].Match != "HostSNI(`app.example.com`)" ||
+		spec.Routes[0].Services[0].Name != "app" || spec.Routes[0].Services[0].Port.IntValue() != 80 {
+		t.Errorf("routes = %+v", spec.Routes)
+	}
+
+	if got := statuses(ctx, "ssl-passthrough"); !slices.Equal(got, []configs.AnnotationStatus{configs.AnnotationConverted}) {
+		t.Errorf("ssl-passthrough statuses = %v, want [converted]", got)
+	}
+
+	if got := statuses(ctx, "enable-cors"); !slices.Equal(got, []configs.AnnotationStatus{configs.AnnotationIgnored}) {
+		t.Errorf("enable-cors statuses = %v, want [ignored]", got)
+	}
+
+	if ctx.Result.IngressReport.RouteMode != configs.RoutedByPassthrough {
+		t.Errorf("route mode = %q, want %q", ctx.Result.IngressReport.RouteMode, configs.RoutedByPassthrough)
+	}
+}
+
+func TestBuildPassthroughWithoutHost(t *testing.T) {
+	ctx := newContext(map[string]string{"ssl-passthrough": "true"}, nil)
+	ctx.Ingress.Spec.Rules[0].Host = ""
+
+	ingressroute.BuildPassthrough(ctx)
+
+	if len(ctx.Result.IngressRoutesTCP) != 0 {
+		t.Errorf("IngressRoutesTCP = %d, want none", len(ctx.Result.IngressRoutesTCP))
+	}
+
+	if got := statuses(ctx, "ssl-passthrough"); !slices.Equal(got, []configs.AnnotationStatus{configs.AnnotationSkipped}) {
+		t.Errorf("ssl-passthrough statuses = %v, want [skipped]", got)
+	}
+}
@@ -1,12 +1,29 @@
 package middleware
 
 import (
+	"fmt"
+	"maps"
+	"net"
+	"net/http"
+	"net/url"
+	"slices"
+	"strconv"
 	"strings"
 
 	"github.com/nikhilsbhat/nginx-traefik-converter/pkg/configs"
 	"github.com/nikhilsbhat/nginx-traefik-converter/pkg/converters/models"
+	"github.com/traefik/traefik/v3/pkg/config/dynamic"
 	traefik "github.com/traefik/traefik/v3/pkg/provider/kubernetes/crd/traefikio/v1alpha1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+)
+
+const (
+	// defaultSigninRedirectParam is the query parameter ingress-nginx adds to auth-signin with the original URL.
+	defaultSigninRedirectParam = "rd"
+
+	// authRequestRedirectHeader is the header ingress-nginx sends to the auth service with auth-request-redirect.
+	authRequestRedirectHeader = "X-Auth-Request-Redirect"
 )
 
 /* ---------------- AUTH URL ---------------- */
@@ -14,15 +31,24 @@ import (
 // HandleAuthURL handles the below annotations.
 // Annotations:
 //   - "nginx.ingress.kubernetes.io/auth-url"
+//   - "nginx.ingress.kubernetes.io/auth-response-headers"
+//   - "nginx.ingress.kubernetes.io/auth-method"
+//   - "nginx.ingress.kubernetes.io/auth-signin"
+//   - "nginx.ingress.kubernetes.io/auth-signin-redirect-param"
+//   - "nginx.ingress.kubernetes.io/auth-request-redirect"
+//   - "nginx.ingress.kubernetes.io/auth-proxy-set-headers"
+//   - "nginx.ingress.kubernetes.io/auth-snippet"
+//   - "nginx.ingress.kubernetes.io/auth-cache-key"
+//   - "nginx.ingress.kubernetes.io/auth-cache-duration"
 func HandleAuthURL(ctx configs.Context) {
 	const ann = string(models.AuthURL)
 
-	val, ok := ctx.Annotations[ann]
+	val, ok := ctx.Annotation(ann)
 	if !ok || strings.TrimSpace(val) == "" {
 		return
 	}
 
-	address := strings.TrimSpace(val)
+	address, variables := expandHostVariables(ctx, strings.TrimSpace(val))
 
 	// Basic sanity check
 	if !strings.HasPrefix(address, "http://") && !strings.HasPrefix(address, "https://") {
@@ -33,6 +59,27 @@ func HandleAuthURL(ctx configs.Context) {
 		return
 	}
 
+	if len(variables) > 0 {
+		msg := "auth-url was not converted, Traefik would call the auth service with the literal NGINX variables: " +
+			describeUnexpanded(variables)
+
+		ctx.Result.Warnings = append(ctx.Result.Warnings, msg)
+		ctx.ReportSkipped(ann, msg)
+
+		return
+	}
+
+	forwardAuth := &traefik.ForwardAuth{
+		Address:             address,
+		TrustForwardHeader:  ctx.Options.TrustForwardedHeaders,
+		AuthResponseHeaders: authResponseHeaders(ctx),
+	}
+
+	authMethod(ctx)
+	authSignin(ctx, address)
+	authRequestRedirect(ctx)
+	authProxySetHeaders(ctx)
+
 	ctx.Result.Middlewares = append(ctx.Result.Middlewares, &traefik.Middleware{
 		TypeMeta: metav1.TypeMeta{
 			APIVersion: traefik.SchemeGroupVersion.String(),
@@ -43,17 +90,413 @@ func HandleAuthURL(ctx configs.Context) {
 			Namespace: ctx.Namespace,
 		},
 		Spec: traefik.MiddlewareSpec{
-			ForwardAuth: &traefik.ForwardAuth{
-				Address:            address,
-				TrustForwardHeader: true,
-			},
+			ForwardAuth: forwardAuth,
 		},
 	})
 
 	// Warn about partial compatibility / related annotations
 	ctx.Result.Warnings = append(ctx.Result.Warnings,
-		"auth-url converted to Traefik ForwardAuth middleware; verify headers and auth behavior",
+		"auth-url converted to Traefik ForwardAuth middleware; the auth service receives X-Forwarded-Method, "+
+			"X-Forwarded-Host and X-Forwarded-Uri instead of the X-Original-URL and X-Original-Method headers sent by ingress-nginx",
+	)
+
+	ctx.ReportConverted(ann)
+
+	for _, ann := range presentAnnotations(ctx, string(models.AuthCacheKey), string(models.AuthCacheDuration)) {
+		ctx.ReportSkipped(ann, "Traefik ForwardAuth does not cache auth responses, every request is sent to the auth service")
+	}
+
+	if _, ok := ctx.Annotation(string(models.AuthSnippet)); ok {
+		msg := "auth-snippet customises the NGINX auth location and cannot be converted; review it manually"
+
+		ctx.Result.Warnings = append(ctx.Result.Warnings, msg)
+		ctx.ReportSkipped(string(models.AuthSnippet), msg)
+	}
+}
+
+// authResponseHeaders returns the headers of the auth response copied to the upstream request.
+func authResponseHeaders(ctx configs.Context) []string {
+	ann := string(models.AuthResponseHeaders)
+
+	val, ok := ctx.Annotation(ann)
+	if !ok {
+		return nil
+	}
+
+	headers := make([]string, 0)
+
+	for _, header := range strings.Split(val, ",") {
+		if header = strings.TrimSpace(header); header != "" {
+			headers = append(headers, http.CanonicalHeaderKey(header))
+		}
+	}
+
+	ctx.ReportConverted(ann)
+
+	return headers
+}
+
+// authMethod checks the method of the auth request, Traefik always sends GET requests to the auth service.
+func authMethod(ctx configs.Context) {
+	ann := string(models.AuthMethod)
+
+	val, ok := ctx.Annotation(ann)
+	if !ok {
+		return
+	}
+
+	if strings.EqualFold(strings.TrimSpace(val), http.MethodGet) {
+		ctx.ReportConverted(ann)
+
+		return
+	}
+
+	msg := fmt.Sprintf("Traefik ForwardAuth sends GET requests to the auth service, auth-method %s cannot be converted; "+
+		"preserveRequestMethod forwards the method of the original request instead", val)
+
+	ctx.Result.Warnings = append(ctx.Result.Warnings, msg)
+	ctx.ReportWarning(ann, msg)
+}
+
+// authSignin sends the unauthenticated requests to the sign-in page through an Errors middleware.
+// The middleware needs a Kubernetes Service, taken from the host of auth-signin or else of auth-url
+// when it is an in-cluster Service address.
+func authSignin(ctx configs.Context, authURL string) {
+	ann := string(models.AuthSignin)
+	annParam := string(models.AuthSigninRedirectParam)
+
+	val, ok := ctx.Annotation(ann)
+	if !ok || strings.TrimSpace(val) == "" {
+		for _, present := range presentAnnotations(ctx, annParam) {
+			ctx.ReportIgnored(present, "auth-signin is not set")
+		}
+
+		return
+	}
+
+	param, _ := ctx.Annotation(annParam)
+	if param = strings.TrimSpace(param); param == "" {
+		param = defaultSigninRedirectParam
+	}
+
+	expanded, variables := expandHostVariables(ctx, strings.TrimSpace(val))
+
+	signin, err := url.Parse(expanded)
+	if err == nil {
+		variables = signinVariables(signin, param)
+	}
+
+	if len(variables) > 0 {
+		msg := "auth-signin was not converted, the sign-in URL would keep the literal NGINX variables: " +
+			describeUnexpanded(variables)
+
+		ctx.Result.Warnings = append(ctx.Result.Warnings, msg)
+		ctx.ReportSkipped(ann, msg)
+
+		for _, present := range presentAnnotations(ctx, annParam) {
+			ctx.ReportSkipped(present, msg)
+		}
+
+		return
+	}
+
+	if err != nil {
+		msg := fmt.Sprintf("auth-signin %q is not a valid URL: %v", val, err)
+
+		ctx.Result.Warnings = append(ctx.Result.Warnings, msg)
+		ctx.ReportSkipped(ann, msg)
+
+		return
+	}
+
+	service, found := clusterService(signin)
+	if !found {
+		if auth, err := url.Parse(authURL); err == nil {
+			service, found = clusterService(auth)
+		}
+	}
+
+	if !found {
+		msg := "auth-signin was not converted, neither auth-signin nor auth-url point to an in-cluster Service " +
+			"(<service>.<namespace>.svc); add an Errors middleware for status 401 serving the sign-in page manually"
+
+		ctx.Result.Warnings = append(ctx.Result.Warnings, msg)
+		ctx.ReportSkipped(ann, msg)
+
+		for _, present := range presentAnnotations(ctx, annParam) {
+			ctx.ReportSkipped(present, msg)
+		}
+
+		return
+	}
+
+	ctx.Result.Middlewares = append(ctx.Result.Middlewares, &traefik.Middleware{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: traefik.SchemeGroupVersion.String(),
+			Kind:       "Middleware",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      mwName(ctx, "auth-signin"),
+			Namespace: ctx.Namespace,
+		},
+		Spec: traefik.MiddlewareSpec{
+			Errors: &traefik.ErrorPage{
+				Status:  []string{strconv.Itoa(http.StatusUnauthorized)},
+				Service: service,
+				Query:   signinQuery(signin, param),
+			},
+		},
+	})
+
+	ctx.Result.Warnings = append(ctx.Result.Warnings,
+		"auth-signin was converted to an Errors middleware serving the sign-in page with the 401 status instead of "+
+			"redirecting to it; for oauth2-proxy use the /oauth2/sign_in path, which renders the page rather than redirecting",
+	)
+
+	if service.Namespace != ctx.Namespace {
+		ctx.Result.Warnings = append(ctx.Result.Warnings,
+			fmt.Sprintf("the sign-in Service %s lives in namespace %s, "+
+				"Traefik only resolves it with allowCrossNamespace enabled on the kubernetesCRD provider",
+				service.Name, service.Namespace),
+		)
+	}
+
+	ctx.ReportConverted(ann)
+
+	for _, present := range presentAnnotations(ctx, annParam) {
+		ctx.ReportConverted(present)
+	}
+}
+
+// signinQuery builds the path and query requested from the sign-in service, the original URL
+// is passed in the redirect parameter.
+func signinQuery(signin *url.URL, param string) string {
+	params := make([]string, 0)
+
+	query := signin.Query()
+
+	for _, key := range slices.Sorted(maps.Keys(query)) {
+		if key == param {
+			continue
+		}
+
+		for _, value := range query[key] {
+			params = append(params, url.QueryEscape(key)+"="+url.QueryEscape(value))
+		}
+	}
+
+	path := signin.Path
+	if path == "" {
+		path = "/"
+	}
+
+	// {url} is replaced by Traefik with the escaped URL of the original request.
+	params = append(params, url.QueryEscape(param)+"={url}")
+
+	return path + "?" + strings.Join(params, "&")
+}
+
+// signinVariables returns the NGINX variables left in the sign-in URL, the redirect parameter
+// is replaced with the URL of the original request.
+func signinVariables(signin *url.URL, param string) []string {
+	parts := []string{signin.Host, signin.Path}
+
+	for key, values := range signin.Query() {
+		if key != param {
+			parts = append(parts, values...)
+		}
+	}
+
+	return variableRe.FindAllString(strings.Join(parts, " "), -1)
+}
+
+// expandHostVariables replaces the host variables of a value with the host of a single host ingress,
+// as done for the headers of the snippets. It returns the NGINX variables left in the value.
+func expandHostVariables(ctx configs.Context, value string) (string, []string) {
+	host := singleHost(ctx)
+	variables := make([]string, 0)
+
+	expanded := variableRe.ReplaceAllStringFunc(value, func(variable string) string {
+		if host != "" && slices.Contains(hostVariables, strings.Trim(variable, "${}")) {
+			return host
+		}
+
+		if !slices.Contains(variables, variable) {
+			variables = append(variables, variable)
+		}
+
+		return variable
+	})
+
+	return expanded, variables
+}
+
+// describeUnexpanded explains why the NGINX variables cannot be expanded by Traefik.
+func describeUnexpanded(variables []string) string {
+	reasons := make([]string, 0, len(variables))
+
+	for _, variable := range variables {
+		variableName := strings.Trim(variable, "${}")
+
+		if slices.Contains(hostVariables, variableName) {
+			reasons = append(reasons, variable+" is only known when the ingress serves a single host")
+
+			continue
+		}
+
+		reasons = append(reasons, variable+" holds "+describeVariable(variableName)+", which Traefik cannot expand")
+	}
+
+	return strings.Join(reasons, "; ")
+}
+
+// clusterService returns the Service addressed by an URL of the form scheme://<service>.<namespace>.svc[.cluster.local][:port].
+func clusterService(target *url.URL) (traefik.Service, bool) {
+	labels := strings.Split(target.Hostname(), ".")
+
+	const serviceLabels = 3
+
+	if len(labels) < serviceLabels || labels[2] != "svc" {
+		return traefik.Service{}, false
+	}
+
+	port := 80
+	if target.Scheme == "https" {
+		port = 443
+	}
+
+	if _, rawPort, err := net.SplitHostPort(target.Host); err == nil {
+		if parsed, err := strconv.Atoi(rawPort); err == nil {
+			port = parsed
+		}
+	}
+
+	return traefik.Service{
+		LoadBalancerSpec: traefik.LoadBalancerSpec{
+			Name:      labels[0],
+			Namespace: labels[1],
+			Port:      intstr.FromInt32(int32(port)), //nolint:gosec
+			Scheme:    target.Scheme,
+		},
+	}, true
+}
+
+// authRequestRedirect sends the X-Auth-Request-Redirect header set by ingress-nginx through a Headers middleware
+// running before ForwardAuth, which forwards it to the auth service.
+func authRequestRedirect(ctx configs.Context) {
+	ann := string(models.AuthRequestRedirect)
+
+	val, ok := ctx.Annotation(ann)
+	if !ok {
+		return
+	}
+
+	if strings.Contains(val, "$") {
+		msg := "auth-request-redirect uses NGINX variables which Traefik cannot expand; " +
+			"the auth service receives X-Forwarded-Uri with the original request path"
+
+		ctx.Result.Warnings = append(ctx.Result.Warnings, msg)
+		ctx.ReportSkipped(ann, msg)
+
+		return
+	}
+
+	ctx.Result.Middlewares = append(ctx.Result.Middlewares,
+		newHeadersMiddleware(ctx, "auth-request-redirect", &dynamic.Headers{
+			CustomRequestHeaders: map[string]string{
+				authRequestRedirectHeader: strings.TrimSpace(val),
+			},
+		}),
+	)
+
+	ctx.Result.Warnings = append(ctx.Result.Warnings,
+		"auth-request-redirect is sent as "+authRequestRedirectHeader+" to the auth service and the upstream",
+	)
+
+	ctx.ReportConverted(ann)
+}
+
+// authProxySetHeaders sends the headers of the ConfigMap referenced by auth-proxy-set-headers through a Headers
+// middleware running before ForwardAuth, which forwards them to the auth service.
+func authProxySetHeaders(ctx configs.Context) {
+	ann := string(models.AuthProxySetHeaders)
+
+	val, ok := ctx.Annotation(ann)
+	if !ok {
+		return
+	}
+
+	namespace, name, found := strings.Cut(strings.TrimSpace(val), "/")
+	if !found {
+		namespace, name = ctx.Namespace, strings.TrimSpace(val)
+	}
+
+	manual := fmt.Sprintf("set its headers as customRequestHeaders of a Headers middleware placed before %s, "+
+		"ForwardAuth forwards them to the auth service", mwName(ctx, "auth-url"))
+
+	if ctx.Options.ConfigMapData == nil {
+		msg := fmt.Sprintf("auth-proxy-set-headers references the ConfigMap %s/%s, which is not available to the converter; %s",
+			namespace, name, manual)
+
+		ctx.Result.Warnings = append(ctx.Result.Warnings, msg)
+		ctx.ReportSkipped(ann, msg)
+
+		return
+	}
+
+	data, err := ctx.Options.ConfigMapData(namespace, name)
+	if err != nil {
+		msg := fmt.Sprintf("the ConfigMap %s/%s of auth-proxy-set-headers could not be read (%v); %s", namespace, name, err, manual)
+
+		ctx.Result.Warnings = append(ctx.Result.Warnings, msg)
+		ctx.ReportSkipped(ann, msg)
+
+		return
+	}
+
+	headers := make(map[string]string, len(data))
+	dropped := make([]string, 0)
+
+	for _, header := range slices.Sorted(maps.Keys(data)) {
+		value, variables := expandHostVariables(ctx, data[header])
+		if len(variables) > 0 {
+			dropped = append(dropped, header+" ("+describeUnexpanded(variables)+")")
+
+			continue
+		}
+
+		headers[http.CanonicalHeaderKey(header)] = value
+	}
+
+	if len(headers) == 0 {
+		msg := fmt.Sprintf("auth-proxy-set-headers was not converted, the ConfigMap %s/%s has no header Traefik can set", namespace, name)
+		if len(dropped) > 0 {
+			msg += ": " + strings.Join(dropped, ", ")
+		}
+
+		ctx.Result.Warnings = append(ctx.Result.Warnings, msg)
+		ctx.ReportSkipped(ann, msg)
+
+		return
+	}
+
+	ctx.Result.Middlewares = append(ctx.Result.Middlewares,
+		newHeadersMiddleware(ctx, "auth-proxy-set-headers", &dynamic.Headers{CustomRequestHeaders: headers}),
+	)
+
+	ctx.Result.Warnings = append(ctx.Result.Warnings,
+		"the headers of auth-proxy-set-headers are sent to the auth service and the upstream",
 	)
 
+	if len(dropped) > 0 {
+		msg := "auth-proxy-set-headers headers left out, their NGINX variables cannot be expanded by Traefik: " +
+			strings.Join(dropped, ", ")
+
+		ctx.Result.Warnings = append(ctx.Result.Warnings, msg)
+		ctx.ReportWarning(ann, msg)
+
+		return
+	}
+
 	ctx.ReportConverted(ann)
 }
@@ -0,0 +1,286 @@
+package middleware_test
+
+import (
+	"errors"
+	"maps"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/nikhilsbhat/nginx-traefik-converter/pkg/configs"
+	"github.com/nikhilsbhat/nginx-traefik-converter/pkg/converters/middleware"
+	netv1 "k8s.io/api/networking/v1"
+)
+
+func TestHandleAuthURL(t *testing.T) {
+	ctx := newContext(map[string]string{
+		"auth-url":              "http://oauth2-proxy.auth.svc.cluster.local:4180/oauth2/auth",
+		"auth-response-headers": "x-auth-request-user, X-Auth-Request-Email",
+		"auth-method":           "GET",
+		"auth-cache-key":        "$cookie_session",
+		"auth-snippet":          "proxy_set_header X-Extra 1;",
+	}, nil)
+
+	middleware.HandleAuthURL(ctx)
+
+	forwardAuth := findMiddleware(t, ctx, "app-auth-url").Spec.ForwardAuth
+	if forwardAuth == nil {
+		t.Fatal("no ForwardAuth middleware was generated")
+	}
+
+	if forwardAuth.Address != "http://oauth2-proxy.auth.svc.cluster.local:4180/oauth2/auth" || forwardAuth.TrustForwardHeader {
+		t.Errorf("forwardAuth = %+v", forwardAuth)
+	}
+
+	if want := []string{"X-Auth-Request-User", "X-Auth-Request-Email"}; !slices.Equal(forwardAuth.AuthResponseHeaders, want) {
+		t.Errorf("authResponseHeaders = %v, want %v", forwardAuth.AuthResponseHeaders, want)
+	}
+
+	want := map[string]configs.AnnotationStatus{
+		"auth-url":              configs.AnnotationConverted,
+		"auth-response-headers": configs.AnnotationConverted,
+		"auth-method":           configs.AnnotationConverted,
+		"auth-cache-key":        configs.AnnotationSkipped,
+		"auth-snippet":          configs.AnnotationSkipped,
+	}
+
+	for annotation, status := range want {
+		if got := statuses(ctx, annotation); !slices.Equal(got, []configs.AnnotationStatus{status}) {
+			t.Errorf("%s statuses = %v, want [%s]", annotation, got, status)
+		}
+	}
+}
+
+func TestHandleAuthURLSignin(t *testing.T) {
+	tests := []struct {
+		name        string
+		annotations map[string]string
+		service     string
+		namespace   string
+		port        int
+		query       string
+	}{
+		{
+			name: "sign-in Service taken from auth-url",
+			annotations: map[string]string{
+				"auth-url":    "http://oauth2-proxy.auth.svc.cluster.local:4180/oauth2/auth",
+				"auth-signin": "https://app.example.com/oauth2/start?rd=$escaped_request_uri",
+			},
+			service:   "oauth2-proxy",
+			namespace: "auth",
+			port:      4180,
+			query:     "/oauth2/start?rd={url}",
+		},
+		{
+			name: "sign-in Service with a custom redirect parameter",
+			annotations: map[string]string{
+				"auth-url":                   "https://auth.example.com/verify",
+				"auth-signin":                "https://login.default.svc/sign_in?client=app&next=$request_uri",
+				"auth-signin-redirect-param": "next",
+			},
+			service:   "login",
+			namespace: "default",
+			port:      443,
+			query:     "/sign_in?client=app&next={url}",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := newContext(tt.annotations, nil)
+
+			middleware.HandleAuthURL(ctx)
+
+			errorPage := findMiddleware(t, ctx, "app-auth-signin").Spec.Errors
+			if errorPage == nil {
+				t.Fatal("no Errors middleware was generated for auth-signin")
+			}
+
+			service := errorPage.Service
+			if service.Name != tt.service || service.Namespace != tt.namespace || service.Port.IntValue() != tt.port {
+				t.Errorf("service = %s/%s:%s, want %s/%s:%d", service.Namespace, service.Name, service.Port.String(),
+					tt.namespace, tt.service, tt.port)
+			}
+
+			if !slices.Equal(errorPage.Status, []string{"401"}) || errorPage.Query != tt.query {
+				t.Errorf("errors = status %v, query %q, want [401], %q", errorPage.Status, errorPage.Query, tt.query)
+			}
+		})
+	}
+}
+
+func TestHandleAuthURLNotConverted(t *testing.T) {
+	ctx := newContext(map[string]string{
+		"auth-url":              "https://auth.example.com/verify",
+		"auth-signin":           "https://login.example.com/start",
+		"auth-method":           "POST",
+		"auth-request-redirect": "$scheme://$host$request_uri",
+	}, nil)
+
+	middleware.HandleAuthURL(ctx)
+
+	want := map[string]configs.AnnotationStatus{
+		"auth-signin":           configs.AnnotationSkipped,
+		"auth-method":           configs.AnnotationWarned,
+		"auth-request-redirect": configs.AnnotationSkipped,
+	}
+
+	for annotation, status := range want {
+		if got := statuses(ctx, annotation); !slices.Equal(got, []configs.AnnotationStatus{status}) {
+			t.Errorf("%s statuses = %v, want [%s]", annotation, got, status)
+		}
+	}
+
+	if len(ctx.Result.Middlewares) != 1 {
+		t.Errorf("only the ForwardAuth middleware should be generated, got %d", len(ctx.Result.Middlewares))
+	}
+
+	relative := newContext(map[string]string{"auth-url": "/verify"}, nil)
+
+	middleware.HandleAuthURL(relative)
+
+	if got := statuses(relative, "auth-url"); !slices.Equal(got, []configs.AnnotationStatus{configs.AnnotationSkipped}) {
+		t.Errorf("auth-url statuses = %v, want [skipped] for a relative URL", got)
+	}
+}
+
+func TestHandleAuthURLVariables(t *testing.T) {
+	annotations := map[string]string{
+		"auth-url":    "https://$host/oauth2/auth",
+		"auth-signin": "https://${host}/oauth2/start?rd=$escaped_request_uri",
+	}
+
+	ctx := newContext(annotations, nil)
+
+	middleware.HandleAuthURL(ctx)
+
+	// the host of a single host ingress is known
+	if address := findMiddleware(t, ctx, "app-auth-url").Spec.ForwardAuth.Address; address != "https://app.example.com/oauth2/auth" {
+		t.Errorf("address = %q, want the host of the ingress", address)
+	}
+
+	multiHost := newContext(annotations, nil)
+	multiHost.Ingress.Spec.Rules = append(multiHost.Ingress.Spec.Rules, netv1.IngressRule{Host: "other.example.com"})
+
+	middleware.HandleAuthURL(multiHost)
+
+	if got := statuses(multiHost, "auth-url"); !slices.Equal(got, []configs.AnnotationStatus{configs.AnnotationSkipped}) {
+		t.Errorf("auth-url statuses = %v, want [skipped]", got)
+	}
+
+	if msg := message(multiHost, "auth-url"); !strings.Contains(msg, "$host is only known when the ingress serves a single host") {
+		t.Errorf("report message %q does not name the variable", msg)
+	}
+
+	if len(multiHost.Result.Middlewares) > 0 {
+		t.Errorf("got %d middlewares, want none", len(multiHost.Result.Middlewares))
+	}
+
+	signin := newContext(map[string]string{
+		"auth-url":    "http://oauth2-proxy.auth.svc:4180/oauth2/auth",
+		"auth-signin": "http://oauth2-proxy.auth.svc:4180/oauth2/start?rd=$escaped_request_uri&tenant=$http_x_tenant",
+	}, nil)
+
+	middleware.HandleAuthURL(signin)
+
+	if got := statuses(signin, "auth-signin"); !slices.Equal(got, []configs.AnnotationStatus{configs.AnnotationSkipped}) {
+		t.Errorf("auth-signin statuses = %v, want [skipped]", got)
+	}
+
+	if msg := message(signin, "auth-signin"); !strings.Contains(msg, "$http_x_tenant holds the x-tenant header of the request") {
+		t.Errorf("report message %q does not name the variable", msg)
+	}
+}
+
+func TestHandleAuthURLProxySetHeaders(t *testing.T) {
+	configMaps := map[string]map[string]string{
+		"auth/auth-headers": {
+			"x-tenant":      "acme",
+			"X-Origin-Host": "$host",
+			"X-Request-Id":  "$req_id",
+		},
+		"default/static-headers": {"X-Tenant": "acme"},
+	}
+
+	tests := []struct {
+		name    string
+		ref     string
+		lookup  bool
+		status  configs.AnnotationStatus
+		headers map[string]string
+		message string
+	}{
+		{
+			name:    "headers with variables",
+			ref:     "auth/auth-headers",
+			lookup:  true,
+			status:  configs.AnnotationWarned,
+			headers: map[string]string{"X-Tenant": "acme", "X-Origin-Host": "app.example.com"},
+			message: "X-Request-Id ($req_id holds a value computed by NGINX",
+		},
+		{
+			name:    "ConfigMap of the ingress namespace",
+			ref:     "static-headers",
+			lookup:  true,
+			status:  configs.AnnotationConverted,
+			headers: map[string]string{"X-Tenant": "acme"},
+		},
+		{
+			name:    "missing ConfigMap",
+			ref:     "auth/missing",
+			lookup:  true,
+			status:  configs.AnnotationSkipped,
+			message: "the ConfigMap auth/missing of auth-proxy-set-headers could not be read",
+		},
+		{
+			name:    "no access to the ConfigMaps",
+			ref:     "auth/auth-headers",
+			status:  configs.AnnotationSkipped,
+			message: "set its headers as customRequestHeaders",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			opts := configs.NewOptions()
+			if tt.lookup {
+				opts.ConfigMapData = func(namespace, name string) (map[string]string, error) {
+					data, ok := configMaps[namespace+"/"+name]
+					if !ok {
+						return nil, errors.New("not found")
+					}
+
+					return data, nil
+				}
+			}
+
+			ctx := newContext(map[string]string{
+				"auth-url":               "https://auth.example.com/verify",
+				"auth-proxy-set-headers": tt.ref,
+			}, opts)
+
+			middleware.HandleAuthURL(ctx)
+
+			if got := statuses(ctx, "auth-proxy-set-headers"); !slices.Equal(got, []configs.AnnotationStatus{tt.status}) {
+				t.Errorf("statuses = %v, want [%s]", got, tt.status)
+			}
+
+			if msg := message(ctx, "auth-proxy-set-headers"); !strings.Contains(msg, tt.message) {
+				t.Errorf("report message %q does not contain %q", msg, tt.message)
+			}
+
+			if tt.headers == nil {
+				if len(ctx.Result.Middlewares) != 1 {
+					t.Errorf("only the ForwardAuth middleware should be generated, got %d", len(ctx.Result.Middlewares))
+				}
+
+				return
+			}
+
+			headers := findMiddleware(t, ctx, "app-auth-proxy-set-headers").Spec.Headers.CustomRequestHeaders
+			if !maps.Equal(headers, tt.headers) {
+				t.Errorf("request headers = %v, want %v", headers, tt.headers)
+			}
+		})
+	}
+}
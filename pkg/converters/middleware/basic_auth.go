@@ -28,7 +28,20 @@ func BasicAuth(ctx configs.Context) {
 		return
 	}
 
-	ctx.Result.Middlewares = append(ctx.Result.Middlewares, &traefik.Middleware{
+	ctx.Result.Middlewares = append(ctx.Result.Middlewares,
+		newBasicAuthMiddleware(ctx, ctx.Annotations[string(models.AuthSecret)], ctx.Annotations[string(models.AuthRealm)]),
+	)
+
+	ctx.ReportConverted(string(models.AuthType))
+
+	ctx.ReportConverted(string(models.AuthSecret))
+
+	ctx.ReportConverted(string(models.AuthRealm))
+}
+
+// newBasicAuthMiddleware returns the BasicAuth middleware of the ingress, checking the users of the given secret.
+func newBasicAuthMiddleware(ctx configs.Context, secret, realm string) *traefik.Middleware {
+	return &traefik.Middleware{
 		TypeMeta: metav1.TypeMeta{
 			APIVersion: traefik.SchemeGroupVersion.String(),
 			Kind:       "Middleware",
@@ -39,13 +52,9 @@ func BasicAuth(ctx configs.Context) {
 		},
 		Spec: traefik.MiddlewareSpec{
 			BasicAuth: &traefik.BasicAuth{
-				Secret: ctx.Annotations[string(models.AuthSecret)],
-				Realm:  ctx.Annotations[string(models.AuthRealm)],
+				Secret: secret,
+				Realm:  realm,
 			},
 		},
-	})
-
-	ctx.ReportConverted(string(models.AuthSecret))
-
-	ctx.ReportConverted(string(models.AuthRealm))
+	}
 }
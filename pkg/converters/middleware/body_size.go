@@ -21,12 +21,12 @@ func BodySize(ctx configs.Context) error {
 
 	ann := string(models.ProxyBodySize)
 
-	val, ok := ctx.Annotations[ann]
+	val, ok := ctx.Annotation(ann)
 	if !ok {
 		return nil
 	}
 
-	intValue, err := parseSizeBytes(val)
+	intValue, err := ParseSizeBytes(val)
 	if err != nil {
 		return &errors.ConverterError{
 			Message: fmt.Sprintf("invalid proxy-body-size %q: %s", val, err.Error()),
@@ -3,13 +3,16 @@ package middleware
 
 import (
 	"encoding/json"
-	"regexp"
+	"fmt"
+	"maps"
+	"slices"
 	"strconv"
 	"strings"
 
 	"github.com/nikhilsbhat/nginx-traefik-converter/pkg/configs"
 	"github.com/nikhilsbhat/nginx-traefik-converter/pkg/converters/models"
 	"github.com/nikhilsbhat/nginx-traefik-converter/pkg/errors"
+	"github.com/nikhilsbhat/nginx-traefik-converter/pkg/nginx"
 	"github.com/traefik/traefik/v3/pkg/config/dynamic"
 	traefik "github.com/traefik/traefik/v3/pkg/provider/kubernetes/crd/traefikio/v1alpha1"
 	apiextv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
@@ -35,6 +38,7 @@ type conditionalReturnConfig struct {
 	Method     string
 	StatusCode int
 	Headers    map[string]any
+	Body       string
 }
 
 var unsupported = map[string]unsupportedDirective{
@@ -71,14 +75,23 @@ func ConfigurationSnippets(ctx configs.Context) error {
 		return nil
 	}
 
-	lines := splitLines(snippet)
-	if len(lines) == 0 {
+	directives, err := nginx.Parse(snippet)
+	if err != nil {
+		msg := "configuration-snippet is not valid NGINX configuration (" + err.Error() + "); it was not converted"
+
+		ctx.Result.Warnings = append(ctx.Result.Warnings, msg)
+		ctx.ReportSkipped(ann, msg)
+
+		return nil
+	}
+
+	if len(directives) == 0 {
 		return nil
 	}
 
 	// 🔒 Conditional CORS handling
-	if isConditionalCORSSnippet(lines) {
-		cfg, err := parseConditionalCORSSnippet(lines)
+	if isConditionalCORSSnippet(directives) {
+		cfg, err := parseConditionalCORSSnippet(directives)
 		if err != nil {
 			ctx.Result.Warnings = append(ctx.Result.Warnings,
 				"failed to parse conditional CORS snippet; skipped",
@@ -89,90 +102,323 @@ func ConfigurationSnippets(ctx configs.Context) error {
 
 		emitCORSMiddleware(ctx, cfg)
 
-		if cr := parseConditionalReturn(lines); cr != nil {
+		if cr := parseConditionalReturn(directives); cr != nil {
 			if err = emitConditionalReturnPlugin(ctx, cr); err != nil {
 				return err
 			}
 		}
 
+		ctx.ReportConverted(ann)
+
 		return nil
 	}
 
-	convertGenericSnippet(ctx, lines)
-
-	ctx.ReportConverted(ann)
-
-	return nil
+	return convertGenericSnippet(ctx, directives)
 }
 
 /* ---------------- Generic snippet handling ---------------- */
 
-func convertGenericSnippet(ctx configs.Context, lines []string) {
+func convertGenericSnippet(ctx configs.Context, directives []*nginx.Directive) error {
 	const (
 		reqHeadersCount  = 4
 		respHeadersCount = 8
 		warningsCount    = 4
 	)
 
+	ann := string(models.ConfigurationSnippet)
+
+	middlewares := len(ctx.Result.Middlewares)
 	reqHeaders := make(map[string]string, reqHeadersCount)
 	respHeaders := make(map[string]string, respHeadersCount)
 	warnings := make([]string, 0, warningsCount)
+	notConverted := make([]string, 0)
+	noops := make([]string, 0)
+	routing := make([]*snippetMiddleware, 0)
+	access := &snippetAccess{}
 
-	for _, raw := range lines {
-		line := strings.TrimSpace(raw)
-		if line == "" {
-			continue
+	var returned *nginx.Directive
+
+	var response *conditionalReturnConfig
+
+	var expires *nginx.Directive
+
+	cacheControl := ""
+
+	// drop records a directive, or the part of it, which is not converted.
+	drop := func(msg string) {
+		warnings = append(warnings, msg)
+		notConverted = append(notConverted, msg)
+	}
+
+	skip := func(directive *nginx.Directive, err error) {
+		drop(directive.Name + " at " + directive.Position() + " was not converted: " + err.Error())
+	}
+
+	// setHeader sets the header with its NGINX variables translated, it is left out when they cannot be
+	// and when Traefik already forwards it with the same value.
+	setHeader := func(headers map[string]string, directive *nginx.Directive, name, value string) {
+		if directive.Name == "proxy_set_header" {
+			if reason, native := nativeRequestHeader(name, value); native {
+				msg := directive.Name + " " + name + " at " + directive.Position() + " has no effect: " + reason
+
+				warnings = append(warnings, msg)
+				noops = append(noops, msg)
+
+				return
+			}
 		}
 
-		lower := strings.ToLower(line)
+		translated, reason, ok := translateHeaderValue(ctx, value)
+		if !ok {
+			drop(directive.Name + " " + name + " at " + directive.Position() + " was left out: " + reason)
 
-		switch directive(lower) {
-		case "add_header", "more_set_headers":
-			if k, v, ok := parseResponseHeader(line); ok {
-				respHeaders[k] = v
-			} else {
+			return
+		}
+
+		headers[name] = translated
+	}
+
+	for _, directive := range directives {
+		switch directive.Name {
+		case "add_header":
+			k, v, ok := parseAddHeader(directive)
+
+			switch {
+			case !ok:
+				drop("failed to parse header directive at " + directive.Position() + ": " + directive.String())
+			case v == "":
+				// An empty value would remove the header in Traefik.
 				warnings = append(warnings,
-					"failed to parse header directive: "+line,
+					"add_header at "+directive.Position()+" has an empty value, which adds nothing in NGINX, and was ignored",
 				)
+			default:
+				setHeader(respHeaders, directive, k, v)
+			}
+
+		case "more_clear_headers", "proxy_hide_header", "more_clear_input_headers":
+			names, filtered := parseClearHeaders(directive)
+
+			if filtered {
+				drop("the status and content type filters of " + directive.Name + " at " + directive.Position() +
+					" are not supported; the headers are cleared from every message")
+			}
+
+			// An empty value removes the header in the Traefik headers middleware.
+			for _, name := range names {
+				if strings.Contains(name, "*") {
+					drop("wildcard header " + name + " of " + directive.Name + " at " + directive.Position() +
+						" is not supported by Traefik and was ignored")
+
+					continue
+				}
+
+				if directive.Name == "more_clear_input_headers" {
+					reqHeaders[name] = ""
+				} else {
+					respHeaders[name] = ""
+				}
+			}
+
+		case "expires":
+			value, err := expiresCacheControl(directive)
+			if err != nil {
+				skip(directive, err)
+
+				continue
+			}
+
+			expires = directive
+			cacheControl = value
+
+		case "more_set_headers":
+			headers, ok := parseMoreSetHeaders(directive)
+			if !ok {
+				drop("failed to parse header directive at " + directive.Position() + ": " + directive.String())
+			}
+
+			for _, k := range slices.Sorted(maps.Keys(headers)) {
+				setHeader(respHeaders, directive, k, headers[k])
 			}
 
 		case "proxy_set_header":
-			key, val := parseProxySetHeader(line)
-			if key != "" {
-				reqHeaders[key] = val
+			key, val, ok := parseProxySetHeader(directive)
+
+			if ok && (strings.EqualFold(key, "Connection") || strings.EqualFold(key, "Upgrade")) {
+				msg := "proxy_set_header " + key + " at " + directive.Position() +
+					" has no effect, Traefik proxies WebSocket and other protocol upgrades natively"
+
+				warnings = append(warnings, msg)
+				noops = append(noops, msg)
+
+				continue
+			}
+
+			if !ok {
+				drop("failed to parse header directive at " + directive.Position() + ": " + directive.String())
+
+				continue
 			}
 
-			if strings.Contains(val, "$") {
+			setHeader(reqHeaders, directive, key, val)
+
+		case "rewrite", "return":
+			// NGINX stops processing the rewrite directives at the first return.
+			if returned != nil {
 				warnings = append(warnings,
-					"proxy_set_header uses NGINX variables which are not evaluated by Traefik",
+					directive.Name+" at "+directive.Position()+" is never reached after the return at "+
+						returned.Position()+" and was ignored",
 				)
+
+				continue
+			}
+
+			if directive.Name == "rewrite" {
+				middleware, warning, err := convertRewrite(directive)
+				if err != nil {
+					skip(directive, err)
+
+					continue
+				}
+
+				routing = append(routing, middleware)
+
+				if warning != "" {
+					warnings = append(warnings, warning)
+				}
+
+				continue
+			}
+
+			returned = directive
+
+			middleware, cfg, warning, err := convertReturn(directive)
+			if err != nil {
+				skip(directive, err)
+
+				continue
 			}
 
+			if middleware != nil {
+				routing = append(routing, middleware)
+			}
+
+			response = cfg
+
+			if warning != "" {
+				warnings = append(warnings, warning)
+			}
+
+		case "allow", "deny", "auth_basic", "auth_basic_user_file", "satisfy":
+			access.add(directive)
+
 		case "gzip", "gzip_comp_level", "gzip_types", "proxy_buffer_size", "proxy_cache":
-			if u, ok := unsupported[directive(lower)]; ok {
-				warnUnsupported(&warnings, u)
+			if u, ok := unsupported[directive.Name]; ok {
+				drop(directive.Name + " at " + directive.Position() + " was not converted: " + unsupportedMessage(u))
 			}
 
 		default:
-			warnings = append(warnings,
-				"unsupported directive in configuration-snippet was ignored: "+line,
-			)
+			drop("unsupported directive in configuration-snippet was ignored at " + directive.Position() + ": " + directive.String())
+		}
+	}
+
+	if rewrites := countKind(routing, snippetRewrite); rewrites > 1 {
+		warnings = append(warnings,
+			"NGINX stops at the first matching rewrite with the last or break flag, "+
+				"Traefik applies every ReplacePathRegex middleware in turn",
+		)
+	}
+
+	// NGINX sends both Cache-Control headers, the one set or cleared explicitly is kept.
+	if cacheControl != "" {
+		if headerKey(respHeaders, cacheControlHeader) != "" {
+			drop("expires at " + expires.Position() + " was ignored, the Cache-Control header of the snippet is used")
+		} else {
+			respHeaders[cacheControlHeader] = cacheControl
 		}
 	}
 
 	ctx.Result.Warnings = append(ctx.Result.Warnings, warnings...)
 
-	if len(reqHeaders) == 0 && len(respHeaders) == 0 {
-		return
+	// The headers middleware comes first so that its response headers apply to redirects and returns.
+	if len(reqHeaders) > 0 || len(respHeaders) > 0 {
+		ctx.Result.Middlewares = append(
+			ctx.Result.Middlewares,
+			newHeadersMiddleware(ctx, "configuration-snippet", &dynamic.Headers{
+				CustomRequestHeaders:  reqHeaders,
+				CustomResponseHeaders: respHeaders,
+			}),
+		)
 	}
 
-	ctx.Result.Middlewares = append(
-		ctx.Result.Middlewares,
-		newHeadersMiddleware(ctx, "configuration-snippet", &dynamic.Headers{
-			CustomRequestHeaders:  reqHeaders,
-			CustomResponseHeaders: respHeaders,
-		}),
-	)
+	emitSnippetMiddlewares(ctx, routing)
+
+	if response != nil {
+		if err := emitConditionalReturnPlugin(ctx, response); err != nil {
+			return err
+		}
+	}
+
+	// NGINX checks access after the rewrite phase, a return answers before it.
+	accessWarnings, accessSkipped := convertSnippetAccess(ctx, access)
+
+	ctx.Result.Warnings = append(ctx.Result.Warnings, accessWarnings...)
+	notConverted = append(notConverted, accessSkipped...)
+
+	// A single report entry lists every directive which was not converted, followed by the ones without effect.
+	generated := len(ctx.Result.Middlewares) > middlewares
+	details := strings.Join(append(notConverted, noops...), "; ")
+
+	switch {
+	case len(notConverted) > 0 && generated:
+		ctx.ReportWarning(ann, details)
+	case len(notConverted) > 0:
+		ctx.ReportSkipped(ann, details)
+	case len(noops) > 0 && !generated:
+		ctx.ReportIgnored(ann, details)
+	default:
+		ctx.ReportConverted(ann)
+	}
+
+	return nil
+}
+
+// emitSnippetMiddlewares adds the rewrite and redirect middlewares in the order of the snippet,
+// numbered when the snippet holds several of a kind.
+func emitSnippetMiddlewares(ctx configs.Context, routing []*snippetMiddleware) {
+	indexes := make(map[string]int)
+
+	for _, middleware := range routing {
+		suffix := "configuration-snippet-" + middleware.kind
+		if countKind(routing, middleware.kind) > 1 {
+			suffix = fmt.Sprintf("%s-%d", suffix, indexes[middleware.kind])
+		}
+
+		indexes[middleware.kind]++
+
+		ctx.Result.Middlewares = append(ctx.Result.Middlewares, &traefik.Middleware{
+			TypeMeta: metav1.TypeMeta{
+				APIVersion: traefik.SchemeGroupVersion.String(),
+				Kind:       "Middleware",
+			},
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      mwName(ctx, suffix),
+				Namespace: ctx.Namespace,
+			},
+			Spec: middleware.spec,
+		})
+	}
+}
+
+func countKind(routing []*snippetMiddleware, kind string) int {
+	count := 0
+
+	for _, middleware := range routing {
+		if middleware.kind == kind {
+			count++
+		}
+	}
+
+	return count
 }
 
 /* ---------------- CORS handling ---------------- */
@@ -181,64 +427,111 @@ func convertGenericSnippet(ctx configs.Context, lines []string) {
 // NGINX `if` directives are never converted,
 // except when they implement pure CORS logic.
 // In that case, Traefik's CORS middleware provides equivalent behavior.
-func isConditionalCORSSnippet(lines []string) bool {
-	var hasOriginIf, hasMethods bool
+func isConditionalCORSSnippet(directives []*nginx.Directive) bool {
+	if originCondition(directives) == nil {
+		return false
+	}
+
+	if nginx.Find(directives, "rewrite", "proxy_pass", "set") != nil ||
+		nginx.FindPrefix(directives, "fastcgi_", "lua_") != nil ||
+		findLuaBlock(directives) != nil {
+		return false
+	}
+
+	return findHeader(directives, "Access-Control-Allow-Methods") != nil
+}
+
+// originCondition returns the if directive matching the Origin header against a regex.
+func originCondition(directives []*nginx.Directive) *nginx.Directive {
+	var found *nginx.Directive
 
-	for _, raw := range lines {
-		line := strings.ToLower(raw)
+	nginx.Walk(directives, func(directive *nginx.Directive, _ []*nginx.Directive) {
+		const conditionArgs = 3
 
-		if strings.Contains(line, "if ($http_origin") {
-			hasOriginIf = true
+		if found != nil || directive.Name != "if" || len(directive.Args) != conditionArgs {
+			return
 		}
 
-		if strings.Contains(line, "access-control-allow-methods") {
-			hasMethods = true
+		if directive.Args[0] == "$http_origin" && (directive.Args[1] == "~*" || directive.Args[1] == "~") {
+			found = directive
 		}
+	})
+
+	return found
+}
+
+// findLuaBlock returns the first *_by_lua* directive of the tree.
+func findLuaBlock(directives []*nginx.Directive) *nginx.Directive {
+	var found *nginx.Directive
 
-		if strings.Contains(line, "rewrite") ||
-			strings.Contains(line, "proxy_pass") ||
-			strings.Contains(line, "fastcgi") ||
-			strings.Contains(line, "lua_") ||
-			strings.Contains(line, "set ") {
-			return false
+	nginx.Walk(directives, func(directive *nginx.Directive, _ []*nginx.Directive) {
+		if found == nil && strings.Contains(directive.Name, "_by_lua") {
+			found = directive
 		}
-	}
+	})
 
-	return hasOriginIf && hasMethods
+	return found
 }
 
-func parseConditionalCORSSnippet(lines []string) (*corsConfig, error) {
+// findHeader returns the last add_header or more_set_headers directive of the tree setting the header,
+// as the last one wins in NGINX, with its value.
+func findHeader(directives []*nginx.Directive, header string) *headerValue {
+	var found *headerValue
+
+	nginx.Walk(directives, func(directive *nginx.Directive, _ []*nginx.Directive) {
+		switch directive.Name {
+		case "add_header":
+			if k, v, ok := parseAddHeader(directive); ok && strings.EqualFold(k, header) {
+				found = &headerValue{name: k, value: v}
+			}
+		case "more_set_headers":
+			headers, _ := parseMoreSetHeaders(directive)
+
+			for k, v := range headers {
+				if strings.EqualFold(k, header) {
+					found = &headerValue{name: k, value: v}
+				}
+			}
+		}
+	})
+
+	return found
+}
+
+type headerValue struct {
+	name  string
+	value string
+}
+
+func parseConditionalCORSSnippet(directives []*nginx.Directive) (*corsConfig, error) {
 	cfg := &corsConfig{}
 
-	origin, ok := extractOriginRegex(lines)
+	origin, ok := extractOriginRegex(directives)
 	if !ok {
 		return nil, &errors.ConverterError{Message: "no origin regex found"}
 	}
 
 	cfg.OriginRegex = origin
 
-	for _, raw := range lines {
-		line := strings.TrimSpace(raw)
-		lower := strings.ToLower(line)
-
-		switch {
-		case strings.Contains(lower, "access-control-allow-headers"):
-			cfg.AllowHeaders = splitCSV(extractQuotedHeaderValue(line))
+	if header := findHeader(directives, "Access-Control-Allow-Headers"); header != nil {
+		cfg.AllowHeaders = splitCSV(header.value)
+	}
 
-		case strings.Contains(lower, "access-control-allow-methods"):
-			cfg.AllowMethods = splitCSV(extractQuotedHeaderValue(line))
+	if header := findHeader(directives, "Access-Control-Allow-Methods"); header != nil {
+		cfg.AllowMethods = splitCSV(header.value)
+	}
 
-		case strings.Contains(lower, "access-control-allow-credentials"):
-			v := strings.ToLower(extractQuotedHeaderValue(line))
-			if v == "true" || v == "false" {
-				b := v == "true"
-				cfg.AllowCreds = &b
-			}
+	if header := findHeader(directives, "Access-Control-Allow-Credentials"); header != nil {
+		v := strings.ToLower(header.value)
+		if v == "true" || v == "false" {
+			b := v == "true"
+			cfg.AllowCreds = &b
+		}
+	}
 
-		case strings.Contains(lower, "access-control-max-age"):
-			if age := extractInt(line); age > 0 {
-				cfg.MaxAge = age
-			}
+	if header := findHeader(directives, "Access-Control-Max-Age"); header != nil {
+		if age, err := strconv.ParseInt(header.value, 10, 64); err == nil && age > 0 {
+			cfg.MaxAge = age
 		}
 	}
 
@@ -279,50 +572,48 @@ func emitCORSMiddleware(ctx configs.Context, cfg *corsConfig) {
 	)
 }
 
-func parseConditionalReturn(lines []string) *conditionalReturnConfig {
-	var (
-		inIf   bool
-		method string
-		status int
-	)
-
-	headers := make(map[string]any)
+func parseConditionalReturn(directives []*nginx.Directive) *conditionalReturnConfig {
+	var found *conditionalReturnConfig
 
-	for _, raw := range lines {
-		line := strings.TrimSpace(raw)
-		lower := strings.ToLower(line)
+	nginx.Walk(directives, func(directive *nginx.Directive, _ []*nginx.Directive) {
+		const conditionArgs = 3
 
 		// Detect: if ($request_method = 'OPTIONS') {
-		if strings.HasPrefix(lower, "if") && strings.Contains(lower, "$request_method") {
-			if strings.Contains(lower, "options") {
-				method = "OPTIONS"
-				inIf = true
-
-				continue
-			}
+		if found != nil || directive.Name != "if" || len(directive.Args) != conditionArgs ||
+			directive.Args[0] != "$request_method" || directive.Args[1] != "=" ||
+			!strings.EqualFold(directive.Args[2], "OPTIONS") {
+			return
 		}
 
-		if inIf {
-			// Detect return 204;
-			if strings.HasPrefix(lower, "return") {
-				fields := strings.Fields(lower)
-				if len(fields) >= 2 {
-					if code, err := strconv.Atoi(strings.TrimSuffix(fields[1], ";")); err == nil {
+		status := 0
+		headers := make(map[string]any)
+
+		for _, nested := range directive.Block {
+			switch nested.Name {
+			case "return":
+				// Detect return 204;
+				if len(nested.Args) > 0 {
+					if code, err := strconv.Atoi(nested.Args[0]); err == nil {
 						status = code
 					}
 				}
 
-				continue
-			}
+			case "add_header":
+				k, v, ok := parseAddHeader(nested)
+				if !ok {
+					continue
+				}
+
+				// Handle $http_origin (cannot be evaluated by Traefik)
+				if strings.Contains(v, "$http_origin") {
+					// Best-effort: let CORS middleware handle dynamic origin
+					v = "*"
+				}
 
-			//nolint:varnamelen
-			// Parse headers properly
-			if k, v, ok := parseAddHeaderNormalized(line); ok {
 				// Special-case list headers
 				switch strings.ToLower(k) {
 				case "access-control-allow-headers", "access-control-allow-methods":
-					list := splitCSV(v)
-					if len(list) > 0 {
+					if list := splitCSV(v); len(list) > 0 {
 						headers[k] = list
 					} else {
 						headers[k] = v
@@ -330,37 +621,41 @@ func parseConditionalReturn(lines []string) *conditionalReturnConfig {
 				default:
 					headers[k] = v
 				}
-
-				continue
 			}
+		}
 
-			// End of block
-			if strings.HasPrefix(line, "}") {
-				inIf = false
+		if status > 0 {
+			found = &conditionalReturnConfig{
+				Method:     "OPTIONS",
+				StatusCode: status,
+				Headers:    headers,
 			}
 		}
+	})
+
+	return found
+}
+
+func emitConditionalReturnPlugin(ctx configs.Context, cfg *conditionalReturnConfig) error {
+	rule := map[string]any{
+		"statusCode": cfg.StatusCode,
 	}
 
-	if method != "" && status > 0 {
-		return &conditionalReturnConfig{
-			Method:     method,
-			StatusCode: status,
-			Headers:    headers,
-		}
+	// Without a method the rule applies to every request.
+	if cfg.Method != "" {
+		rule["method"] = cfg.Method
 	}
 
-	return nil
-}
+	if len(cfg.Headers) > 0 {
+		rule["headers"] = cfg.Headers
+	}
+
+	if cfg.Body != "" {
+		rule["body"] = cfg.Body
+	}
 
-func emitConditionalReturnPlugin(ctx configs.Context, cfg *conditionalReturnConfig) error {
 	pluginCfg := map[string]any{
-		"rules": []map[string]any{
-			{
-				"method":     cfg.Method,
-				"statusCode": cfg.StatusCode,
-				"headers":    cfg.Headers,
-			},
-		},
+		"rules": []map[string]any{rule},
 	}
 
 	raw, err := json.Marshal(pluginCfg)
@@ -379,126 +674,30 @@ func emitConditionalReturnPlugin(ctx configs.Context, cfg *conditionalReturnConf
 		},
 		Spec: traefik.MiddlewareSpec{
 			Plugin: map[string]apiextv1.JSON{
-				"conditionalReturn": {Raw: raw},
+				configs.PluginConditionalReturn: {Raw: raw},
 			},
 		},
 	})
 
-	return nil
-}
-
-func parseAddHeaderNormalized(line string) (string, string, bool) {
-	// Trim trailing ;
-	line = strings.TrimSpace(strings.TrimSuffix(line, ";"))
-
-	lower := strings.ToLower(line)
-	if !strings.HasPrefix(lower, "add_header") && !strings.HasPrefix(lower, "more_set_headers") {
-		return "", "", false
-	}
-
-	// Remove directive name
-	fields := strings.Fields(line)
-	if len(fields) < 3 {
-		return "", "", false
-	}
-
-	rest := strings.TrimSpace(line[len(fields[0]):])
-
-	// Remove "always" if present
-	rest = strings.TrimSpace(strings.TrimSuffix(rest, "always"))
-
-	// Now rest should look like:
-	// "Header-Name" "Header Value"
-	// 'Header-Name' 600
-	// Header-Name value
-
-	var key, val string
-
-	// Try quoted key
-	if strings.HasPrefix(rest, `"`) || strings.HasPrefix(rest, `'`) {
-		quote := rest[0:1]
-
-		end := strings.Index(rest[1:], quote)
-		if end == -1 {
-			return "", "", false
-		}
-
-		end++
-
-		key = rest[1:end]
-		after := strings.TrimSpace(rest[end+1:])
-
-		// Value may be quoted or unquoted
-		if strings.HasPrefix(after, `"`) || strings.HasPrefix(after, `'`) {
-			q := after[0:1]
-
-			e := strings.Index(after[1:], q)
-			if e == -1 {
-				return "", "", false
-			}
-
-			val = after[1 : 1+e]
-		} else {
-			// Unquoted value (e.g. 600)
-			val = strings.TrimSpace(after)
-		}
-	} else {
-		// No quoted key, fallback to fields
-		parts := strings.Fields(rest)
-		if len(parts) < 2 {
-			return "", "", false
-		}
-
-		key = strings.Trim(parts[0], `"'`)
-		val = strings.Trim(strings.Join(parts[1:], " "), `"'`)
-	}
-
-	key = strings.TrimSpace(key)
-	val = strings.TrimSpace(val)
-
-	if key == "" || val == "" {
-		return "", "", false
-	}
-
-	// Handle $http_origin (cannot be evaluated by Traefik)
-	if strings.Contains(val, "$http_origin") {
-		// Best-effort: let CORS middleware handle dynamic origin
-		val = "*"
-	}
+	ctx.Result.Warnings = append(ctx.Result.Warnings,
+		"the "+mwName(ctx, "conditional-return")+" middleware uses the "+configs.PluginConditionalReturn+
+			" plugin, which has no published module; declare the plugin answering the rules with --plugin "+
+			configs.PluginConditionalReturn+"=<module>@<version>, or replace the placeholder module written to "+
+			"experimental.plugins of the static configuration",
+	)
 
-	return key, val, true
+	return nil
 }
 
 /* ---------------- Helpers ---------------- */
 
-func splitLines(s string) []string {
-	out := make([]string, 0)
-
-	for _, l := range strings.Split(s, "\n") {
-		if t := strings.TrimSpace(l); t != "" {
-			out = append(out, t)
-		}
-	}
-
-	return out
-}
-
-func directive(line string) string {
-	fields := strings.Fields(line)
-	if len(fields) == 0 {
-		return ""
-	}
-
-	return fields[0]
-}
-
-func warnUnsupported(warnings *[]string, d unsupportedDirective) {
+func unsupportedMessage(d unsupportedDirective) string {
 	msg := d.Message
 	if d.Enterprise {
 		msg += ". Traefik Enterprise provides an alternative, but it cannot be auto-converted."
 	}
 
-	*warnings = append(*warnings, msg)
+	return msg
 }
 
 func newHeadersMiddleware(
@@ -523,100 +722,72 @@ func newHeadersMiddleware(
 
 /* ---------------- Parsing helpers ---------------- */
 
-func parseProxySetHeader(line string) (string, string) {
-	line = strings.TrimSuffix(line, ";")
-	parts := strings.Fields(line)
-
-	const proxySetHeaderCount = 3
+// parseProxySetHeader returns the header and value of a proxy_set_header directive.
+func parseProxySetHeader(directive *nginx.Directive) (string, string, bool) {
+	const proxySetHeaderArgs = 2
 
-	if len(parts) < proxySetHeaderCount {
-		return "", ""
+	if len(directive.Args) != proxySetHeaderArgs || directive.Args[0] == "" {
+		return "", "", false
 	}
 
-	return strings.Trim(parts[1], `"`), strings.Join(parts[2:], " ")
+	return directive.Args[0], directive.Args[1], true
 }
 
-func parseResponseHeader(line string) (string, string, bool) {
-	line = strings.TrimSuffix(strings.TrimSpace(line), ";")
-
-	if strings.HasPrefix(line, "more_set_headers") {
-		start := strings.Index(line, `"`)
-		end := strings.LastIndex(line, `"`)
-
-		if start == -1 || end <= start {
-			return "", "", false
-		}
-
-		const moreSetHeadersCount = 2
-
-		kv := strings.SplitN(line[start+1:end], ":", moreSetHeadersCount)
-		if len(kv) != moreSetHeadersCount {
-			return "", "", false
-		}
-
-		return strings.TrimSpace(kv[0]), strings.TrimSpace(kv[1]), true
+// parseAddHeader returns the header and value of an add_header directive, add_header <name> <value> [always].
+func parseAddHeader(directive *nginx.Directive) (string, string, bool) {
+	args := directive.Args
+	if len(args) == 3 && args[2] == "always" {
+		args = args[:2]
 	}
 
-	const addHeaderCount = 3
-
-	if strings.HasPrefix(line, "add_header") {
-		fields := strings.Fields(line)
-		if len(fields) < addHeaderCount {
-			return "", "", false
-		}
-
-		return strings.Trim(fields[1], `"`),
-			strings.Trim(strings.Join(fields[2:], " "), `"`),
-			true
+	if len(args) != 2 || strings.TrimSpace(args[0]) == "" {
+		return "", "", false
 	}
 
-	return "", "", false
+	return strings.TrimSpace(args[0]), strings.TrimSpace(args[1]), true
 }
 
-var originIfRe = regexp.MustCompile(
-	`\$http_origin\s+~\*\s+\((.+?)\)\s*\)`,
-)
+// parseMoreSetHeaders returns the headers of a more_set_headers directive, more_set_headers [-s <status>] [-t <type>] "<name>: <value>"...
+// The status and content type filters are not supported and dropped.
+func parseMoreSetHeaders(directive *nginx.Directive) (map[string]string, bool) {
+	headers := make(map[string]string)
+	valid := true
 
-func extractOriginRegex(lines []string) (string, bool) {
-	const originRegexCount = 2
+	for index := 0; index < len(directive.Args); index++ {
+		arg := directive.Args[index]
 
-	for _, l := range lines {
-		if m := originIfRe.FindStringSubmatch(l); len(m) == originRegexCount {
-			return m[1], true
-		}
-	}
+		if arg == "-s" || arg == "-t" {
+			index++
 
-	return "", false
-}
+			continue
+		}
 
-func extractQuotedHeaderValue(line string) string {
-	values := make([]string, 0)
+		name, value, found := strings.Cut(arg, ":")
+		if !found || strings.TrimSpace(name) == "" {
+			valid = false
 
-	for _, quote := range []string{`"`, `'`} {
-		tmp := line
+			continue
+		}
 
-		for {
-			start := strings.Index(tmp, quote)
-			if start == -1 {
-				break
-			}
+		headers[strings.TrimSpace(name)] = strings.TrimSpace(value)
+	}
 
-			end := strings.Index(tmp[start+1:], quote)
-			if end == -1 {
-				break
-			}
+	return headers, valid && len(headers) > 0
+}
 
-			end = start + 1 + end
-			values = append(values, tmp[start+1:end])
-			tmp = tmp[end+1:]
-		}
+// extractOriginRegex returns the regex the Origin header is matched against, without its surrounding parentheses.
+func extractOriginRegex(directives []*nginx.Directive) (string, bool) {
+	condition := originCondition(directives)
+	if condition == nil {
+		return "", false
 	}
 
-	if len(values) == 0 {
-		return ""
+	regex := condition.Args[2]
+	if strings.HasPrefix(regex, "(") && strings.HasSuffix(regex, ")") {
+		regex = regex[1 : len(regex)-1]
 	}
 
-	return values[len(values)-1]
+	return regex, regex != ""
 }
 
 func splitCSV(v string) []string {
@@ -630,18 +801,3 @@ func splitCSV(v string) []string {
 
 	return out
 }
-
-func extractInt(line string) int64 {
-	fields := strings.Fields(line)
-	if len(fields) == 0 {
-		return 0
-	}
-
-	newValue, _ := strconv.ParseInt(
-		strings.TrimSuffix(fields[len(fields)-1], ";"),
-		10,
-		64,
-	)
-
-	return newValue
-}
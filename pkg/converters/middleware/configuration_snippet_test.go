@@ -0,0 +1,164 @@
+package middleware_test
+
+import (
+	"maps"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/nikhilsbhat/nginx-traefik-converter/pkg/configs"
+	"github.com/nikhilsbhat/nginx-traefik-converter/pkg/converters/middleware"
+)
+
+func TestConfigurationSnippetsHeaders(t *testing.T) {
+	ctx := newContext(map[string]string{
+		"configuration-snippet": `
+add_header X-Frame-Options DENY always;
+more_set_headers "X-Served-By: traefik" "X-Env: prod";
+more_clear_headers Server;
+proxy_set_header X-Tenant acme;
+more_clear_input_headers X-Debug;
+`,
+	}, nil)
+
+	if err := middleware.ConfigurationSnippets(ctx); err != nil {
+		t.Fatalf("ConfigurationSnippets() error = %v", err)
+	}
+
+	headers := findMiddleware(t, ctx, "app-configuration-snippet").Spec.Headers
+
+	wantResponse := map[string]string{"X-Frame-Options": "DENY", "X-Served-By": "traefik", "X-Env": "prod", "Server": ""}
+	if !maps.Equal(headers.CustomResponseHeaders, wantResponse) {
+		t.Errorf("response headers = %v, want %v", headers.CustomResponseHeaders, wantResponse)
+	}
+
+	wantRequest := map[string]string{"X-Tenant": "acme", "X-Debug": ""}
+	if !maps.Equal(headers.CustomRequestHeaders, wantRequest) {
+		t.Errorf("request headers = %v, want %v", headers.CustomRequestHeaders, wantRequest)
+	}
+
+	if got := statuses(ctx, "configuration-snippet"); !slices.Equal(got, []configs.AnnotationStatus{configs.AnnotationConverted}) {
+		t.Errorf("statuses = %v, want converted", got)
+	}
+}
+
+func TestConfigurationSnippetsReport(t *testing.T) {
+	tests := []struct {
+		name        string
+		snippet     string
+		status      configs.AnnotationStatus
+		middlewares int
+		messages    []string
+	}{
+		{
+			name:        "directives left out are listed with their position",
+			snippet:     "add_header X-A a;\nproxy_pass http://backend;\nlocation /api {\n  return 204;\n}\n  gzip on;",
+			status:      configs.AnnotationWarned,
+			middlewares: 1,
+			messages: []string{
+				"line 2, column 1: proxy_pass http://backend;",
+				"line 3, column 1: location /api { ... }",
+				"gzip at line 6, column 3 was not converted",
+			},
+		},
+		{
+			name:        "header with a per-request variable",
+			snippet:     "add_header X-A a;\nadd_header X-Uri $request_uri;",
+			status:      configs.AnnotationWarned,
+			middlewares: 1,
+			messages:    []string{"add_header X-Uri at line 2, column 1 was left out"},
+		},
+		{
+			name:        "wildcard and filtered header clearing",
+			snippet:     "more_clear_headers -s 404 'X-Hidden-*' Server;",
+			status:      configs.AnnotationWarned,
+			middlewares: 1,
+			messages: []string{
+				"filters of more_clear_headers at line 1, column 1",
+				"wildcard header X-Hidden-* of more_clear_headers at line 1, column 1",
+			},
+		},
+		{
+			name:        "nothing converted",
+			snippet:     "proxy_pass http://backend;\nif ($host = old.example.com) {\n  add_header X-A a;\n}",
+			status:      configs.AnnotationSkipped,
+			middlewares: 0,
+			messages:    []string{"line 1, column 1: proxy_pass", "line 2, column 1: if $host = old.example.com { ... }"},
+		},
+		{
+			name:        "invalid configuration",
+			snippet:     "add_header X-A \"a;",
+			status:      configs.AnnotationSkipped,
+			middlewares: 0,
+			messages:    []string{"not valid NGINX configuration (line 1, column 16: unterminated \" quoted string)"},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			ctx := newContext(map[string]string{"configuration-snippet": test.snippet}, nil)
+
+			if err := middleware.ConfigurationSnippets(ctx); err != nil {
+				t.Fatalf("ConfigurationSnippets() error = %v", err)
+			}
+
+			// a single entry describes the whole snippet
+			if got := statuses(ctx, "configuration-snippet"); !slices.Equal(got, []configs.AnnotationStatus{test.status}) {
+				t.Errorf("statuses = %v, want [%s]", got, test.status)
+			}
+
+			if len(ctx.Result.Middlewares) != test.middlewares {
+				t.Errorf("got %d middlewares, want %d", len(ctx.Result.Middlewares), test.middlewares)
+			}
+
+			msg := message(ctx, "configuration-snippet")
+			for _, want := range test.messages {
+				if !strings.Contains(msg, want) {
+					t.Errorf("report message %q does not contain %q", msg, want)
+				}
+			}
+		})
+	}
+}
+
+func TestConfigurationSnippetsCORS(t *testing.T) {
+	ctx := newContext(map[string]string{
+		"configuration-snippet": `
+if ($http_origin ~* (^https://.*\.example\.com$)) {
+  add_header Access-Control-Allow-Origin $http_origin;
+  add_header Access-Control-Allow-Methods "GET, POST, OPTIONS";
+  add_header Access-Control-Allow-Headers "Authorization, Content-Type";
+  add_header Access-Control-Allow-Credentials true;
+  add_header Access-Control-Max-Age 600;
+}
+if ($request_method = 'OPTIONS') {
+  return 204;
+}
+`,
+	}, nil)
+
+	if err := middleware.ConfigurationSnippets(ctx); err != nil {
+		t.Fatalf("ConfigurationSnippets() error = %v", err)
+	}
+
+	headers := findMiddleware(t, ctx, "app-cors").Spec.Headers
+
+	if !slices.Equal(headers.AccessControlAllowOriginListRegex, []string{`^https://.*\.example\.com$`}) {
+		t.Errorf("origin regex = %v", headers.AccessControlAllowOriginListRegex)
+	}
+
+	if !slices.Equal(headers.AccessControlAllowMethods, []string{"GET", "POST", "OPTIONS"}) ||
+		!slices.Equal(headers.AccessControlAllowHeaders, []string{"Authorization", "Content-Type"}) ||
+		!headers.AccessControlAllowCredentials || headers.AccessControlMaxAge != 600 {
+		t.Errorf("CORS headers = %+v", headers)
+	}
+
+	plugin := findMiddleware(t, ctx, "app-conditional-return").Spec.Plugin
+	if _, ok := plugin[configs.PluginConditionalReturn]; !ok {
+		t.Errorf("plugin = %v, want the %s plugin", plugin, configs.PluginConditionalReturn)
+	}
+
+	if got := statuses(ctx, "configuration-snippet"); !slices.Equal(got, []configs.AnnotationStatus{configs.AnnotationConverted}) {
+		t.Errorf("statuses = %v, want converted", got)
+	}
+}
@@ -0,0 +1,197 @@
+package middleware
+
+import (
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/nikhilsbhat/nginx-traefik-converter/pkg/configs"
+	"github.com/nikhilsbhat/nginx-traefik-converter/pkg/converters/models"
+	traefik "github.com/traefik/traefik/v3/pkg/provider/kubernetes/crd/traefikio/v1alpha1"
+	netv1 "k8s.io/api/networking/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+)
+
+const (
+	// defaultBackendPort is assumed when the port of the default-backend Service cannot be found in the ingress.
+	defaultBackendPort = 80
+
+	// errorPageQuery keeps the status code in the path requested from the error service,
+	// as ingress-nginx only passes it through the X-Code header.
+	errorPageQuery = "/{status}"
+)
+
+/* ---------------- CUSTOM HTTP ERRORS ---------------- */
+
+// CustomHTTPErrors handles the below annotations.
+// Annotations:
+//   - "nginx.ingress.kubernetes.io/custom-http-errors"
+//   - "nginx.ingress.kubernetes.io/default-backend"
+//
+// The error codes are served by the default-backend Service through an Errors middleware.
+// A default-backend without custom-http-errors is converted into a catch-all route by the route builders.
+func CustomHTTPErrors(ctx configs.Context) {
+	ctx.Log.Debug("running converter CustomHTTPErrors")
+
+	annErrors := string(models.CustomHTTPErrors)
+	annBackend := string(models.DefaultBackend)
+
+	val, ok := ctx.Annotation(annErrors)
+	if !ok {
+		return
+	}
+
+	service, found := DefaultBackendService(ctx)
+	if !found {
+		msg := "custom-http-errors without default-backend uses the default backend of the ingress-nginx controller; " +
+			"add a default-backend annotation pointing to the error pages Service"
+
+		ctx.Result.Warnings = append(ctx.Result.Warnings, msg)
+		ctx.ReportSkipped(annErrors, msg)
+
+		return
+	}
+
+	status, invalid := parseStatusCodes(val)
+	if len(invalid) > 0 || len(status) == 0 {
+		msg := "custom-http-errors contains invalid status codes (" + strings.Join(invalid, ", ") + "); " +
+			"the error pages were not converted"
+
+		ctx.Result.Warnings = append(ctx.Result.Warnings, msg)
+		ctx.ReportSkipped(annErrors, msg)
+		ctx.ReportSkipped(annBackend, msg)
+
+		return
+	}
+
+	ctx.Result.Middlewares = append(ctx.Result.Middlewares, &traefik.Middleware{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: traefik.SchemeGroupVersion.String(),
+			Kind:       "Middleware",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      mwName(ctx, "custom-errors"),
+			Namespace: ctx.Namespace,
+		},
+		Spec: traefik.MiddlewareSpec{
+			Errors: &traefik.ErrorPage{
+				Status:  status,
+				Service: service,
+				Query:   errorPageQuery,
+			},
+		},
+	})
+
+	ctx.Result.Warnings = append(ctx.Result.Warnings,
+		fmt.Sprintf("error pages are requested from %s as %s; Traefik does not send the X-Code, X-Format "+
+			"and X-Original-URI headers ingress-nginx passes to the default backend", service.Name, errorPageQuery),
+	)
+
+	ctx.ReportConverted(annErrors)
+	ctx.ReportConverted(annBackend)
+}
+
+// DefaultBackendService returns the Service referenced by the default-backend annotation.
+// The port is taken from the ingress when it routes to the same Service, otherwise port 80 is assumed.
+func DefaultBackendService(ctx configs.Context) (traefik.Service, bool) {
+	annBackend := string(models.DefaultBackend)
+
+	name := strings.TrimSpace(ctx.Annotations[annBackend])
+	if name == "" {
+		return traefik.Service{}, false
+	}
+
+	port, found := servicePort(ctx.Ingress, name)
+	if !found {
+		ctx.Result.Warnings = append(ctx.Result.Warnings,
+			fmt.Sprintf("the port of the default-backend Service %s is not referenced by the ingress, port %d was assumed",
+				name, defaultBackendPort),
+		)
+	}
+
+	return traefik.Service{
+		LoadBalancerSpec: traefik.LoadBalancerSpec{
+			Name: name,
+			Port: port,
+		},
+	}, true
+}
+
+// servicePort looks up the port of the Service in the default backend and the rules of the ingress.
+func servicePort(ing *netv1.Ingress, name string) (intstr.IntOrString, bool) {
+	backends := make([]*netv1.IngressServiceBackend, 0)
+
+	if ing.Spec.DefaultBackend != nil {
+		backends = append(backends, ing.Spec.DefaultBackend.Service)
+	}
+
+	for _, rule := range ing.Spec.Rules {
+		if rule.HTTP == nil {
+			continue
+		}
+
+		for _, path := range rule.HTTP.Paths {
+			backends = append(backends, path.Backend.Service)
+		}
+	}
+
+	for _, backend := range backends {
+		if backend == nil || backend.Name != name {
+			continue
+		}
+
+		if backend.Port.Name != "" {
+			return intstr.FromString(backend.Port.Name), true
+		}
+
+		return intstr.FromInt32(backend.Port.Number), true
+	}
+
+	return intstr.FromInt32(defaultBackendPort), false
+}
+
+// parseStatusCodes splits the comma separated status codes and merges consecutive codes into ranges.
+func parseStatusCodes(val string) ([]string, []string) {
+	codes := make([]int, 0)
+	invalid := make([]string, 0)
+
+	for _, entry := range strings.Split(val, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+
+		code, err := strconv.Atoi(entry)
+		if err != nil || code < 100 || code > 599 {
+			invalid = append(invalid, entry)
+
+			continue
+		}
+
+		codes = append(codes, code)
+	}
+
+	slices.Sort(codes)
+	codes = slices.Compact(codes)
+
+	status := make([]string, 0, len(codes))
+
+	for start := 0; start < len(codes); {
+		end := start
+		for end+1 < len(codes) && codes[end+1] == codes[end]+1 {
+			end++
+		}
+
+		if end == start {
+			status = append(status, strconv.Itoa(codes[start]))
+		} else {
+			status = append(status, fmt.Sprintf("%d-%d", codes[start], codes[end]))
+		}
+
+		start = end + 1
+	}
+
+	return status, invalid
+}
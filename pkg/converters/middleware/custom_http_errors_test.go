@@ -0,0 +1,97 @@
+package middleware_test
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/nikhilsbhat/nginx-traefik-converter/pkg/configs"
+	"github.com/nikhilsbhat/nginx-traefik-converter/pkg/converters/middleware"
+)
+
+func TestCustomHTTPErrors(t *testing.T) {
+	tests := []struct {
+		name        string
+		annotations map[string]string
+		status      []string
+		service     string
+		port        string
+	}{
+		{
+			name:        "consecutive codes are merged into ranges",
+			annotations: map[string]string{"custom-http-errors": "503, 404,502,500,501,404", "default-backend": "error-pages"},
+			status:      []string{"404", "500-503"},
+			service:     "error-pages",
+			port:        "80",
+		},
+		{
+			name:        "port of a Service routed by the ingress",
+			annotations: map[string]string{"custom-http-errors": "404", "default-backend": "app"},
+			status:      []string{"404"},
+			service:     "app",
+			port:        "80",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := newContext(tt.annotations, nil)
+
+			middleware.CustomHTTPErrors(ctx)
+
+			errorPage := findMiddleware(t, ctx, "app-custom-errors").Spec.Errors
+			if errorPage == nil {
+				t.Fatal("no Errors middleware was generated")
+			}
+
+			if !slices.Equal(errorPage.Status, tt.status) {
+				t.Errorf("status = %v, want %v", errorPage.Status, tt.status)
+			}
+
+			if errorPage.Service.Name != tt.service || errorPage.Service.Port.String() != tt.port {
+				t.Errorf("service = %s:%s, want %s:%s", errorPage.Service.Name, errorPage.Service.Port.String(), tt.service, tt.port)
+			}
+
+			if errorPage.Query != "/{status}" {
+				t.Errorf("query = %q, want /{status}", errorPage.Query)
+			}
+
+			for _, annotation := range []string{"custom-http-errors", "default-backend"} {
+				if got := statuses(ctx, annotation); !slices.Equal(got, []configs.AnnotationStatus{configs.AnnotationConverted}) {
+					t.Errorf("%s statuses = %v, want [converted]", annotation, got)
+				}
+			}
+		})
+	}
+}
+
+func TestCustomHTTPErrorsSkipped(t *testing.T) {
+	tests := []struct {
+		name        string
+		annotations map[string]string
+	}{
+		{
+			name:        "without default-backend",
+			annotations: map[string]string{"custom-http-errors": "404"},
+		},
+		{
+			name:        "invalid status codes",
+			annotations: map[string]string{"custom-http-errors": "404,abc,700", "default-backend": "error-pages"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := newContext(tt.annotations, nil)
+
+			middleware.CustomHTTPErrors(ctx)
+
+			if len(ctx.Result.Middlewares) != 0 {
+				t.Errorf("no middleware should be generated, got %d", len(ctx.Result.Middlewares))
+			}
+
+			if got := statuses(ctx, "custom-http-errors"); !slices.Equal(got, []configs.AnnotationStatus{configs.AnnotationSkipped}) {
+				t.Errorf("custom-http-errors statuses = %v, want [skipped]", got)
+			}
+		})
+	}
+}
@@ -1,6 +1,8 @@
 package middleware
 
 import (
+	"fmt"
+
 	"github.com/nikhilsbhat/nginx-traefik-converter/pkg/configs"
 	"github.com/nikhilsbhat/nginx-traefik-converter/pkg/converters/models"
 )
@@ -15,26 +17,20 @@ import (
 //   - "nginx.ingress.kubernetes.io/enable-opentelemetry"
 //   - "nginx.ingress.kubernetes.io/backend-protocol"
 //   - "nginx.ingress.kubernetes.io/grpc-backend"
+//   - "nginx.ingress.kubernetes.io/client-header-buffer-size"
+//   - "nginx.ingress.kubernetes.io/large-client-header-buffers"
+//   - "nginx.ingress.kubernetes.io/enable-global-auth"
 func ExtraAnnotations(ctx configs.Context) {
 	ctx.Log.Debug("running converter ExtraAnnotations")
 
 	if _, ok := ctx.Annotations[string(models.ClientHeaderBufferSize)]; ok {
-		msg := `'client-header-buffer-size' is not supported per-Ingress in Traefik;
-configure it globally using:
-entryPoints.<name>.http.maxHeaderBytes in Traefik static configuration`
+		msg := "client-header-buffer-size only sizes the initial NGINX buffer, " +
+			"the request header limit comes from large-client-header-buffers"
 
-		ctx.Result.Warnings = append(ctx.Result.Warnings, msg)
 		ctx.ReportIgnored(string(models.ClientHeaderBufferSize), msg)
 	}
 
-	if _, ok := ctx.Annotations[string(models.LargeClientHeaderBuffers)]; ok {
-		msg := `'large-client-header-buffers' is not supported per-Ingress in Traefik;
-Traefik only supports a global limit
-via entryPoints.<name>.http.maxHeaderBytes in static configuration`
-
-		ctx.Result.Warnings = append(ctx.Result.Warnings, msg)
-		ctx.ReportIgnored(string(models.LargeClientHeaderBuffers), msg)
-	}
+	largeClientHeaderBuffers(ctx)
 
 	if ctx.Annotations[string(models.ServiceUpstream)] == "true" {
 		warningMessage := "service-upstream=true is default behavior in Traefik"
@@ -50,19 +46,8 @@ via entryPoints.<name>.http.maxHeaderBytes in static configuration`
 		ctx.ReportWarning(string(models.EnableOpentracing), warningMessage)
 	}
 
-	if ctx.Annotations[string(models.EnableOpentelemetry)] == "true" {
-		warningMessage := "enable-opentelemetry must be configured globally in Traefik static config"
-
-		ctx.Result.Warnings = append(
-			ctx.Result.Warnings,
-			warningMessage+`tracing:
-  otlp:
-    grpc:
-      endpoint: otel-collector:4317`,
-		)
-
-		ctx.ReportWarning(string(models.EnableOpentelemetry), warningMessage)
-	}
+	enableOpentelemetry(ctx)
+	enableGlobalAuth(ctx)
 
 	if v := ctx.Annotations[string(models.BackendProtocol)]; v != "" {
 		warningMessage := "backend-protocol must be applied to IngressRoute service scheme, check for generated ingressroutes.yaml"
@@ -78,3 +63,79 @@ via entryPoints.<name>.http.maxHeaderBytes in static configuration`
 		ctx.ReportWarning(string(models.GrpcBackend), warningMessage)
 	}
 }
+
+// largeClientHeaderBuffers sets the request header limit on the web and websecure entry points,
+// Traefik has no per route limit.
+func largeClientHeaderBuffers(ctx configs.Context) {
+	ann := string(models.LargeClientHeaderBuffers)
+
+	val, ok := ctx.Annotations[ann]
+	if !ok {
+		return
+	}
+
+	limit, err := MaxHeaderBytes(val)
+	if err != nil {
+		ctx.Result.Warnings = append(ctx.Result.Warnings, err.Error())
+		ctx.ReportSkipped(ann, err.Error())
+
+		return
+	}
+
+	if ctx.Result.EntryPoints == nil {
+		ctx.Result.EntryPoints = make(map[string]*configs.EntryPoint)
+	}
+
+	for _, name := range []string{configs.EntryPointWeb, configs.EntryPointWebSecure} {
+		entryPoint := configs.DefaultEntryPoint(name)
+		entryPoint.HTTP = &configs.EntryPointHTTP{MaxHeaderBytes: limit}
+
+		ctx.Result.EntryPoints[name] = entryPoint
+	}
+
+	msg := fmt.Sprintf("large-client-header-buffers is not supported per-Ingress in Traefik; "+
+		"entryPoints.<name>.http.maxHeaderBytes was set to %d in the static configuration, "+
+		"it applies to all the routes of the web and websecure entry points", limit)
+
+	ctx.Result.Warnings = append(ctx.Result.Warnings, msg)
+	ctx.ReportWarning(ann, msg)
+}
+
+// enableOpentelemetry enables the OpenTelemetry tracing in the static configuration,
+// Traefik traces all the routes once tracing is configured.
+func enableOpentelemetry(ctx configs.Context) {
+	ann := string(models.EnableOpentelemetry)
+
+	if ctx.Annotations[ann] != "true" {
+		return
+	}
+
+	ctx.Result.Tracing = &configs.Tracing{
+		OTLP: &configs.TracingOTLP{
+			GRPC: &configs.TracingOTLPGRPC{},
+		},
+	}
+
+	msg := "enable-opentelemetry is global in Traefik; tracing.otlp was added to the static configuration, " +
+		"set tracing.otlp.grpc.endpoint to the collector address (localhost:4317 by default)"
+
+	ctx.Result.Warnings = append(ctx.Result.Warnings, msg)
+	ctx.ReportWarning(ann, msg)
+}
+
+// enableGlobalAuth reports the opt-out of the global auth configured in the controller ConfigMap.
+func enableGlobalAuth(ctx configs.Context) {
+	ann := string(models.EnableGlobalAuth)
+
+	if _, ok := ctx.Annotations[ann]; !ok {
+		return
+	}
+
+	if _, ok := ctx.Options.ControllerDefaults[string(models.AuthURL)]; !ok {
+		ctx.ReportIgnored(ann, "no global-auth-url is set in the controller ConfigMap")
+
+		return
+	}
+
+	ctx.ReportConverted(ann)
+}
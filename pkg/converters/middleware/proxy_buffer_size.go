@@ -16,7 +16,7 @@ import (
 func ProxyBufferSizes(ctx configs.Context) {
 	ctx.Log.Debug("running converter ProxyBufferSize")
 
-	val, ok := ctx.Annotations[string(models.ProxyBufferSize)]
+	val, ok := ctx.Annotation(string(models.ProxyBufferSize))
 	if !ok {
 		return
 	}
@@ -34,7 +34,7 @@ it does not buffer responses the same way and uses Go’s HTTP stack`
 		return
 	}
 
-	size, err := parseSizeBytes(val)
+	size, err := ParseSizeBytes(val)
 	if err != nil {
 		warningMessage := "proxy-buffer-size value could not be parsed and was ignored"
 
@@ -13,7 +13,7 @@ func ProxyBuffering(ctx configs.Context) {
 
 	ann := string(models.ProxyBuffering)
 
-	val, ok := ctx.Annotations[ann]
+	val, ok := ctx.Annotation(ann)
 	if !ok {
 		return
 	}
@@ -169,7 +169,7 @@ func newRewriteResponseHeadersMiddleware(ctx configs.Context, header, regex, rep
 		},
 		Spec: traefik.MiddlewareSpec{
 			Plugin: map[string]apiextv1.JSON{
-				"rewriteResponseHeaders": {Raw: raw},
+				configs.PluginRewriteResponseHeaders: {Raw: raw},
 			},
 		},
 	}
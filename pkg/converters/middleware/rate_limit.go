@@ -1,12 +1,16 @@
 package middleware
 
 import (
+	"fmt"
 	"strconv"
+	"strings"
 
 	"github.com/nikhilsbhat/nginx-traefik-converter/pkg/configs"
 	"github.com/nikhilsbhat/nginx-traefik-converter/pkg/converters/models"
+	"github.com/traefik/traefik/v3/pkg/config/dynamic"
 	traefik "github.com/traefik/traefik/v3/pkg/provider/kubernetes/crd/traefikio/v1alpha1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
 )
 
 /* ---------------- RATE LIMIT ---------------- */
@@ -14,31 +18,90 @@ import (
 // RateLimit handles the below annotations.
 // Annotations:
 //   - "nginx.ingress.kubernetes.io/limit-rps"
+//   - "nginx.ingress.kubernetes.io/limit-rpm"
 //   - "nginx.ingress.kubernetes.io/limit-burst-multiplier"
+//   - "nginx.ingress.kubernetes.io/limit-connections"
+//   - "nginx.ingress.kubernetes.io/limit-whitelist"
+//   - "nginx.ingress.kubernetes.io/limit-rate"
+//   - "nginx.ingress.kubernetes.io/limit-rate-after"
+//
+// As ingress-nginx does, limit-rps and limit-rpm are applied together and every limit is keyed per client IP.
 func RateLimit(ctx configs.Context) error {
 	ctx.Log.Debug("running converter RateLimit")
 
 	annLimitRPS := string(models.LimitRPS)
+	annLimitRPM := string(models.LimitRPM)
 	annLimitBurstMultiplier := string(models.LimitBurstMultiplier)
 
-	rps, ok := ctx.Annotations[annLimitRPS]
-	if !ok {
-		return nil
+	limited := false
+
+	if rps, ok := ctx.Annotations[annLimitRPS]; ok {
+		avg, err := strconv.Atoi(rps)
+		if err != nil {
+			ctx.ReportWarning(annLimitRPS, err.Error())
+			ctx.ReportWarning(annLimitBurstMultiplier, err.Error())
+
+			return err
+		}
+
+		addRateLimit(ctx, "ratelimit", avg, "")
+		ctx.ReportConverted(annLimitRPS)
+
+		limited = true
 	}
 
-	const averageValue = 2
+	if rpm, ok := ctx.Annotations[annLimitRPM]; ok {
+		avg, err := strconv.Atoi(strings.TrimSpace(rpm))
+		if err != nil || avg <= 0 {
+			msg := fmt.Sprintf("invalid limit-rpm %q, the per minute rate limit was not converted", rpm)
+
+			ctx.Result.Warnings = append(ctx.Result.Warnings, msg)
+			ctx.ReportSkipped(annLimitRPM, msg)
+		} else {
+			addRateLimit(ctx, "ratelimit-rpm", avg, "1m")
+			ctx.ReportConverted(annLimitRPM)
 
-	avg, err := strconv.Atoi(rps)
-	if err != nil {
-		ctx.ReportWarning(annLimitRPS, err.Error())
-		ctx.ReportWarning(annLimitBurstMultiplier, err.Error())
+			limited = true
+		}
+	}
+
+	if _, ok := ctx.Annotations[annLimitBurstMultiplier]; ok && limited {
+		ctx.ReportConverted(annLimitBurstMultiplier)
+	}
 
-		return err
+	limited = connectionLimit(ctx) || limited
+
+	if limited {
+		limitWhitelist(ctx)
+
+		if !ctx.Options.TrustForwardedHeaders {
+			ctx.Result.Warnings = append(ctx.Result.Warnings,
+				"rate and connection limits are keyed by the remote address of the connection; behind a load balancer "+
+					"enable PROXY protocol on the entry point or use --trust-forwarded-headers",
+			)
+		}
+	} else if _, ok := ctx.Annotations[string(models.LimitWhitelist)]; ok {
+		ctx.ReportIgnored(string(models.LimitWhitelist), "no rate or connection limit is set")
 	}
 
+	for _, ann := range presentAnnotations(ctx, string(models.LimitRate), string(models.LimitRateAfter)) {
+		msg := "Traefik has no equivalent for throttling the response bandwidth"
+
+		ctx.Result.Warnings = append(ctx.Result.Warnings, ann+": "+msg)
+		ctx.ReportSkipped(ann, msg)
+	}
+
+	return nil
+}
+
+// addRateLimit adds a RateLimit middleware allowing the average amount of requests per period,
+// with a burst of the average times limit-burst-multiplier. An empty period stands for one second.
+func addRateLimit(ctx configs.Context, suffix string, avg int, period string) {
+	const averageValue = 2
+
 	burst := avg * averageValue
 
-	if m := ctx.Annotations[annLimitBurstMultiplier]; m != "" {
+	if m := ctx.Annotations[string(models.LimitBurstMultiplier)]; m != "" {
 		if v, err := strconv.Atoi(m); err == nil {
 			burst = avg * v
 		}
@@ -47,25 +110,101 @@ func RateLimit(ctx configs.Context) error {
 	average := int64(avg)
 	averageBurst := int64(burst)
 
+	rateLimit := &traefik.RateLimit{
+		Average:         &average,
+		Burst:           &averageBurst,
+		SourceCriterion: clientSourceCriterion(ctx),
+	}
+
+	if period != "" {
+		rateLimitPeriod := intstr.FromString(period)
+		rateLimit.Period = &rateLimitPeriod
+	}
+
 	ctx.Result.Middlewares = append(ctx.Result.Middlewares, &traefik.Middleware{
 		TypeMeta: metav1.TypeMeta{
 			APIVersion: traefik.SchemeGroupVersion.String(),
 			Kind:       "Middleware",
 		},
 		ObjectMeta: metav1.ObjectMeta{
-			Name:      mwName(ctx, "ratelimit"),
+			Name:      mwName(ctx, suffix),
 			Namespace: ctx.Namespace,
 		},
 		Spec: traefik.MiddlewareSpec{
-			RateLimit: &traefik.RateLimit{
-				Average: &average,
-				Burst:   &averageBurst,
+			RateLimit: rateLimit,
+		},
+	})
+}
+
+// connectionLimit converts limit-connections into an InFlightReq middleware.
+// It reports whether a middleware was added.
+func connectionLimit(ctx configs.Context) bool {
+	ann := string(models.LimitConnections)
+
+	val, ok := ctx.Annotations[ann]
+	if !ok {
+		return false
+	}
+
+	amount, err := strconv.ParseInt(strings.TrimSpace(val), 10, 64)
+	if err != nil || amount <= 0 {
+		msg := fmt.Sprintf("invalid limit-connections %q, the connection limit was not converted", val)
+
+		ctx.Result.Warnings = append(ctx.Result.Warnings, msg)
+		ctx.ReportSkipped(ann, msg)
+
+		return false
+	}
+
+	ctx.Result.Middlewares = append(ctx.Result.Middlewares, &traefik.Middleware{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: traefik.SchemeGroupVersion.String(),
+			Kind:       "Middleware",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      mwName(ctx, "inflightreq"),
+			Namespace: ctx.Namespace,
+		},
+		Spec: traefik.MiddlewareSpec{
+			InFlightReq: &dynamic.InFlightReq{
+				Amount:          amount,
+				SourceCriterion: clientSourceCriterion(ctx),
 			},
 		},
 	})
 
-	ctx.ReportConverted(annLimitRPS)
-	ctx.ReportConverted(annLimitBurstMultiplier)
+	ctx.Result.Warnings = append(ctx.Result.Warnings,
+		"limit-connections limits concurrent connections in NGINX, Traefik limits the concurrent in-flight requests per client IP",
+	)
 
-	return nil
+	ctx.ReportConverted(ann)
+
+	return true
+}
+
+func limitWhitelist(ctx configs.Context) {
+	ann := string(models.LimitWhitelist)
+
+	if _, ok := ctx.Annotations[ann]; !ok {
+		return
+	}
+
+	msg := "Traefik rate limits have no exclusion list, the client IPs in limit-whitelist are limited as well"
+
+	ctx.Result.Warnings = append(ctx.Result.Warnings, msg)
+	ctx.ReportSkipped(ann, msg)
+}
+
+// clientSourceCriterion groups the requests per client IP, read from X-Forwarded-For when forwarded headers
+// are trusted and from the remote address otherwise. Without an explicit ipStrategy InFlightReq would group
+// them per request host.
+func clientSourceCriterion(ctx configs.Context) *dynamic.SourceCriterion {
+	strategy := clientIPStrategy(ctx)
+	if strategy == nil {
+		strategy = &dynamic.IPStrategy{}
+	}
+
+	return &dynamic.SourceCriterion{
+		IPStrategy: strategy,
+	}
 }
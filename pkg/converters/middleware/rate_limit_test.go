@@ -0,0 +1,148 @@
+package middleware_test
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/nikhilsbhat/nginx-traefik-converter/pkg/configs"
+	"github.com/nikhilsbhat/nginx-traefik-converter/pkg/converters/middleware"
+)
+
+func TestRateLimit(t *testing.T) {
+	tests := []struct {
+		name        string
+		annotations map[string]string
+		trusted     bool
+		average     int64
+		burst       int64
+		period      string
+		statuses    map[string]configs.AnnotationStatus
+	}{
+		{
+			name:        "per second with the default burst",
+			annotations: map[string]string{"limit-rps": "10"},
+			average:     10,
+			burst:       20,
+			statuses:    map[string]configs.AnnotationStatus{"limit-rps": configs.AnnotationConverted},
+		},
+		{
+			name:        "burst multiplier and trusted forwarded headers",
+			annotations: map[string]string{"limit-rps": "10", "limit-burst-multiplier": "3", "limit-whitelist": "10.0.0.0/8"},
+			trusted:     true,
+			average:     10,
+			burst:       30,
+			statuses: map[string]configs.AnnotationStatus{
+				"limit-burst-multiplier": configs.AnnotationConverted,
+				"limit-whitelist":        configs.AnnotationSkipped,
+			},
+		},
+		{
+			name:        "per minute",
+			annotations: map[string]string{"limit-rpm": "60", "limit-rate": "100k"},
+			average:     60,
+			burst:       120,
+			period:      "1m",
+			statuses: map[string]configs.AnnotationStatus{
+				"limit-rpm":  configs.AnnotationConverted,
+				"limit-rate": configs.AnnotationSkipped,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			opts := configs.NewOptions()
+			opts.TrustForwardedHeaders = tt.trusted
+
+			ctx := newContext(tt.annotations, opts)
+
+			if err := middleware.RateLimit(ctx); err != nil {
+				t.Fatalf("RateLimit() error = %v", err)
+			}
+
+			name := "app-ratelimit"
+			if tt.period != "" {
+				name = "app-ratelimit-rpm"
+			}
+
+			rateLimit := findMiddleware(t, ctx, name).Spec.RateLimit
+			if rateLimit == nil {
+				t.Fatal("no RateLimit middleware was generated")
+			}
+
+			if *rateLimit.Average != tt.average || *rateLimit.Burst != tt.burst {
+				t.Errorf("average/burst = %d/%d, want %d/%d", *rateLimit.Average, *rateLimit.Burst, tt.average, tt.burst)
+			}
+
+			period := ""
+			if rateLimit.Period != nil {
+				period = rateLimit.Period.String()
+			}
+
+			if period != tt.period {
+				t.Errorf("period = %q, want %q", period, tt.period)
+			}
+
+			switch {
+			case rateLimit.SourceCriterion == nil || rateLimit.SourceCriterion.IPStrategy == nil:
+				t.Errorf("sourceCriterion = %+v, want an ipStrategy", rateLimit.SourceCriterion)
+			case !tt.trusted && rateLimit.SourceCriterion.IPStrategy.Depth != 0:
+				t.Errorf("sourceCriterion = %+v, want the remote address", rateLimit.SourceCriterion)
+			case tt.trusted && rateLimit.SourceCriterion.IPStrategy.Depth != 1:
+				t.Errorf("sourceCriterion = %+v, want an ipStrategy of depth 1", rateLimit.SourceCriterion)
+			}
+
+			for annotation, status := range tt.statuses {
+				if got := statuses(ctx, annotation); !slices.Equal(got, []configs.AnnotationStatus{status}) {
+					t.Errorf("%s statuses = %v, want [%s]", annotation, got, status)
+				}
+			}
+		})
+	}
+}
+
+func TestRateLimitConnections(t *testing.T) {
+	ctx := newContext(map[string]string{"limit-connections": "5"}, nil)
+
+	if err := middleware.RateLimit(ctx); err != nil {
+		t.Fatalf("RateLimit() error = %v", err)
+	}
+
+	inFlight := findMiddleware(t, ctx, "app-inflightreq").Spec.InFlightReq
+	if inFlight == nil || inFlight.Amount != 5 {
+		t.Fatalf("inFlightReq = %+v, want an amount of 5", inFlight)
+	}
+
+	// without an ipStrategy InFlightReq would group the requests per host
+	if inFlight.SourceCriterion == nil || inFlight.SourceCriterion.IPStrategy == nil {
+		t.Errorf("sourceCriterion = %+v, want an ipStrategy", inFlight.SourceCriterion)
+	}
+}
+
+func TestRateLimitInvalid(t *testing.T) {
+	ctx := newContext(map[string]string{"limit-rpm": "often", "limit-connections": "-1", "limit-whitelist": "10.0.0.0/8"}, nil)
+
+	if err := middleware.RateLimit(ctx); err != nil {
+		t.Fatalf("RateLimit() error = %v", err)
+	}
+
+	if len(ctx.Result.Middlewares) != 0 {
+		t.Errorf("no middleware should be generated, got %d", len(ctx.Result.Middlewares))
+	}
+
+	want := map[string]configs.AnnotationStatus{
+		"limit-rpm":         configs.AnnotationSkipped,
+		"limit-connections": configs.AnnotationSkipped,
+		"limit-whitelist":   configs.AnnotationIgnored,
+	}
+
+	for annotation, status := range want {
+		if got := statuses(ctx, annotation); !slices.Equal(got, []configs.AnnotationStatus{status}) {
+			t.Errorf("%s statuses = %v, want [%s]", annotation, got, status)
+		}
+	}
+
+	if err := middleware.RateLimit(newContext(map[string]string{"limit-rps": "ten"}, nil)); err == nil {
+		t.Error("RateLimit() expected an error for an invalid limit-rps")
+	}
+}
@@ -0,0 +1,275 @@
+package middleware
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/nikhilsbhat/nginx-traefik-converter/pkg/configs"
+	"github.com/nikhilsbhat/nginx-traefik-converter/pkg/converters/models"
+	"github.com/traefik/traefik/v3/pkg/config/dynamic"
+	traefik "github.com/traefik/traefik/v3/pkg/provider/kubernetes/crd/traefikio/v1alpha1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const wwwPrefix = "www."
+
+/* ---------------- URL REDIRECTS ---------------- */
+
+// Redirects handles the below annotations.
+// Annotations:
+//   - "nginx.ingress.kubernetes.io/permanent-redirect"
+//   - "nginx.ingress.kubernetes.io/permanent-redirect-code"
+//   - "nginx.ingress.kubernetes.io/temporal-redirect"
+//   - "nginx.ingress.kubernetes.io/temporal-redirect-code"
+//   - "nginx.ingress.kubernetes.io/app-root"
+//   - "nginx.ingress.kubernetes.io/from-to-www-redirect"
+func Redirects(ctx configs.Context) {
+	ctx.Log.Debug("running converter Redirects")
+
+	// ingress-nginx serves the www counterpart from a dedicated server block, its redirect comes first.
+	fromToWWWRedirect(ctx)
+	urlRedirect(ctx)
+	appRoot(ctx)
+}
+
+// urlRedirect redirects every request of the ingress to a fixed URL.
+// As in ingress-nginx, temporal-redirect takes precedence over permanent-redirect.
+func urlRedirect(ctx configs.Context) {
+	annPermanent := string(models.PermanentRedirect)
+	annPermanentCode := string(models.PermanentRedirectCode)
+	annTemporal := string(models.TemporalRedirect)
+	annTemporalCode := string(models.TemporalRedirectCode)
+
+	target, annURL, annCode, defaultCode := "", "", "", 0
+
+	switch {
+	case strings.TrimSpace(ctx.Annotations[annTemporal]) != "":
+		target, annURL, annCode, defaultCode = ctx.Annotations[annTemporal], annTemporal, annTemporalCode, http.StatusFound
+
+		for _, ann := range presentAnnotations(ctx, annPermanent, annPermanentCode) {
+			ctx.ReportIgnored(ann, "temporal-redirect takes precedence over permanent-redirect")
+		}
+	case strings.TrimSpace(ctx.Annotations[annPermanent]) != "":
+		target, annURL, annCode, defaultCode = ctx.Annotations[annPermanent], annPermanent, annPermanentCode, http.StatusMovedPermanently
+
+		for _, ann := range presentAnnotations(ctx, annTemporalCode) {
+			ctx.ReportIgnored(ann, "temporal-redirect is not set")
+		}
+	default:
+		for _, ann := range presentAnnotations(ctx, annPermanentCode, annTemporalCode) {
+			ctx.ReportIgnored(ann, "no redirect URL is set")
+		}
+
+		return
+	}
+
+	target = strings.TrimSpace(target)
+
+	if parsed, err := url.Parse(target); err != nil || parsed.Scheme == "" || parsed.Host == "" {
+		msg := fmt.Sprintf("redirect URL %q is not an absolute URL; the redirect was not converted", target)
+
+		ctx.Result.Warnings = append(ctx.Result.Warnings, msg)
+
+		for _, ann := range presentAnnotations(ctx, annURL, annCode) {
+			ctx.ReportSkipped(ann, msg)
+		}
+
+		return
+	}
+
+	code := defaultCode
+
+	if val, ok := ctx.Annotations[annCode]; ok {
+		parsed, err := strconv.Atoi(strings.TrimSpace(val))
+		if err != nil || parsed < http.StatusMultipleChoices || parsed > http.StatusPermanentRedirect {
+			msg := fmt.Sprintf("invalid redirect code %q, %d was used", val, defaultCode)
+
+			ctx.Result.Warnings = append(ctx.Result.Warnings, msg)
+			ctx.ReportWarning(annCode, msg)
+		} else {
+			code = parsed
+		}
+	}
+
+	permanent := isPermanentCode(code)
+
+	addRedirectRegex(ctx, "url-redirect", &dynamic.RedirectRegex{
+		Regex:       "^.*$",
+		Replacement: target,
+		Permanent:   permanent,
+	})
+
+	ctx.ReportConverted(annURL)
+
+	if _, ok := ctx.Annotations[annCode]; !ok || ctx.IsReported(annCode) {
+		return
+	}
+
+	if redirectCode(permanent) != code {
+		msg := fmt.Sprintf("Traefik redirects with %d (or %d for non GET requests) instead of %d",
+			redirectCode(permanent), methodPreservingCode(permanent), code)
+
+		ctx.Result.Warnings = append(ctx.Result.Warnings, msg)
+		ctx.ReportWarning(annCode, msg)
+
+		return
+	}
+
+	ctx.ReportConverted(annCode)
+}
+
+// appRoot redirects requests to the root path to the application root, as ingress-nginx does with a 302.
+func appRoot(ctx configs.Context) {
+	ann := string(models.AppRoot)
+
+	val, ok := ctx.Annotations[ann]
+	if !ok {
+		return
+	}
+
+	root := strings.TrimSpace(val)
+	if !strings.HasPrefix(root, "/") || root == "/" {
+		msg := fmt.Sprintf("app-root %q must be a path other than /; it was not converted", val)
+
+		ctx.Result.Warnings = append(ctx.Result.Warnings, msg)
+		ctx.ReportSkipped(ann, msg)
+
+		return
+	}
+
+	// RedirectRegex matches the full request URL, the root path may be followed by a query string.
+	addRedirectRegex(ctx, "app-root", &dynamic.RedirectRegex{
+		Regex:       `^(https?://[^/]+)/(\?.*)?$`,
+		Replacement: "${1}" + root,
+		Permanent:   false,
+	})
+
+	ctx.ReportConverted(ann)
+}
+
+// fromToWWWRedirect redirects the www counterpart of every host of the ingress to the host itself.
+// The counterpart hosts are added to the routes by the route builders through WWWCounterpart.
+func fromToWWWRedirect(ctx configs.Context) {
+	ann := string(models.FromToWWWRedirect)
+
+	if _, ok := ctx.Annotations[ann]; !ok {
+		return
+	}
+
+	if strings.ToLower(strings.TrimSpace(ctx.Annotations[ann])) != "true" {
+		ctx.ReportSkipped(ann, fmt.Sprintf("%s is not set to true", ann))
+
+		return
+	}
+
+	hosts := make([]string, 0)
+
+	for _, rule := range ctx.Ingress.Spec.Rules {
+		if counterpart := WWWCounterpart(ctx, rule.Host); counterpart != "" &&
+			!slices.ContainsFunc(hosts, func(host string) bool { return strings.EqualFold(host, rule.Host) }) {
+			hosts = append(hosts, rule.Host)
+		}
+	}
+
+	if len(hosts) == 0 {
+		msg := "from-to-www-redirect has no effect, the ingress has no rule with a non-wildcard host"
+
+		ctx.ReportSkipped(ann, msg)
+
+		return
+	}
+
+	for index, host := range hosts {
+		suffix := "from-to-www-redirect"
+		if len(hosts) > 1 {
+			suffix = fmt.Sprintf("%s-%d", suffix, index)
+		}
+
+		// ingress-nginx answers with a 308, which Traefik sends for non GET requests of permanent redirects.
+		addRedirectRegex(ctx, suffix, &dynamic.RedirectRegex{
+			Regex:       fmt.Sprintf(`^(https?)://%s(:\d+)?(/.*)?$`, regexp.QuoteMeta(WWWCounterpart(ctx, host))),
+			Replacement: "${1}://" + host + "${2}${3}",
+			Permanent:   true,
+		})
+
+		if len(ctx.Ingress.Spec.TLS) > 0 && !tlsCovers(ctx, WWWCounterpart(ctx, host)) {
+			ctx.Result.Warnings = append(ctx.Result.Warnings,
+				fmt.Sprintf("the certificate of %s does not list %s in spec.tls; "+
+					"HTTPS requests to it will not be served with a matching certificate", host, WWWCounterpart(ctx, host)),
+			)
+		}
+	}
+
+	ctx.ReportConverted(ann)
+}
+
+// WWWCounterpart returns the host redirected to the given host by from-to-www-redirect,
+// empty when the annotation is not enabled or the host has no counterpart.
+func WWWCounterpart(ctx configs.Context, host string) string {
+	if strings.ToLower(strings.TrimSpace(ctx.Annotations[string(models.FromToWWWRedirect)])) != "true" {
+		return ""
+	}
+
+	if host == "" || strings.HasPrefix(host, "*") {
+		return ""
+	}
+
+	if bare, ok := strings.CutPrefix(host, wwwPrefix); ok {
+		return bare
+	}
+
+	return wwwPrefix + host
+}
+
+func addRedirectRegex(ctx configs.Context, suffix string, redirect *dynamic.RedirectRegex) {
+	ctx.Result.Middlewares = append(ctx.Result.Middlewares, &traefik.Middleware{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: traefik.SchemeGroupVersion.String(),
+			Kind:       "Middleware",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      mwName(ctx, suffix),
+			Namespace: ctx.Namespace,
+		},
+		Spec: traefik.MiddlewareSpec{
+			RedirectRegex: redirect,
+		},
+	})
+}
+
+func isPermanentCode(code int) bool {
+	return code == http.StatusMovedPermanently || code == http.StatusPermanentRedirect
+}
+
+// redirectCode returns the status code Traefik uses for GET requests.
+func redirectCode(permanent bool) int {
+	if permanent {
+		return http.StatusMovedPermanently
+	}
+
+	return http.StatusFound
+}
+
+// methodPreservingCode returns the status code Traefik uses for requests other than GET.
+func methodPreservingCode(permanent bool) int {
+	if permanent {
+		return http.StatusPermanentRedirect
+	}
+
+	return http.StatusTemporaryRedirect
+}
+
+func tlsCovers(ctx configs.Context, host string) bool {
+	for _, entry := range ctx.Ingress.Spec.TLS {
+		if slices.ContainsFunc(entry.Hosts, func(tlsHost string) bool { return strings.EqualFold(tlsHost, host) }) {
+			return true
+		}
+	}
+
+	return false
+}
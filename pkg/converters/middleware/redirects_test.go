@@ -0,0 +1,178 @@
+package middleware_test
+
+import (
+	"regexp"
+	"slices"
+	"testing"
+
+	"github.com/nikhilsbhat/nginx-traefik-converter/pkg/configs"
+	"github.com/nikhilsbhat/nginx-traefik-converter/pkg/converters/middleware"
+	"github.com/traefik/traefik/v3/pkg/config/dynamic"
+)
+
+// redirectURL applies the RedirectRegex to the request URL as Traefik does, empty when the request is not redirected.
+func redirectURL(t *testing.T, redirect *dynamic.RedirectRegex, requestURL string) string {
+	t.Helper()
+
+	regex, err := regexp.Compile(redirect.Regex)
+	if err != nil {
+		t.Fatalf("regex %q does not compile: %v", redirect.Regex, err)
+	}
+
+	if !regex.MatchString(requestURL) {
+		return ""
+	}
+
+	return regex.ReplaceAllString(requestURL, redirect.Replacement)
+}
+
+func TestRedirects(t *testing.T) {
+	tests := []struct {
+		name        string
+		annotations map[string]string
+		middleware  string
+		permanent   bool
+		requests    map[string]string
+		statuses    map[string]configs.AnnotationStatus
+	}{
+		{
+			name:        "permanent redirect",
+			annotations: map[string]string{"permanent-redirect": "https://example.com/new"},
+			middleware:  "app-url-redirect",
+			permanent:   true,
+			requests:    map[string]string{"http://app.example.com/any/path?q=1": "https://example.com/new"},
+			statuses:    map[string]configs.AnnotationStatus{"permanent-redirect": configs.AnnotationConverted},
+		},
+		{
+			name: "temporal redirect takes precedence",
+			annotations: map[string]string{
+				"temporal-redirect":       "https://example.com/maintenance",
+				"temporal-redirect-code":  "307",
+				"permanent-redirect":      "https://example.com/new",
+				"permanent-redirect-code": "308",
+			},
+			middleware: "app-url-redirect",
+			requests:   map[string]string{"https://app.example.com/": "https://example.com/maintenance"},
+			statuses: map[string]configs.AnnotationStatus{
+				"temporal-redirect":       configs.AnnotationConverted,
+				"temporal-redirect-code":  configs.AnnotationWarned,
+				"permanent-redirect":      configs.AnnotationIgnored,
+				"permanent-redirect-code": configs.AnnotationIgnored,
+			},
+		},
+		{
+			name:        "app root",
+			annotations: map[string]string{"app-root": "/dashboard"},
+			middleware:  "app-app-root",
+			requests: map[string]string{
+				"https://app.example.com/":          "https://app.example.com/dashboard",
+				"https://app.example.com/?a=b":      "https://app.example.com/dashboard",
+				"https://app.example.com/other":     "",
+				"https://app.example.com/dashboard": "",
+			},
+			statuses: map[string]configs.AnnotationStatus{"app-root": configs.AnnotationConverted},
+		},
+		{
+			name:        "from www redirect",
+			annotations: map[string]string{"from-to-www-redirect": "true"},
+			middleware:  "app-from-to-www-redirect",
+			permanent:   true,
+			requests: map[string]string{
+				"https://www.app.example.com:8443/path?q=1": "https://app.example.com:8443/path?q=1",
+				"http://www.app.example.com":                "http://app.example.com",
+				"https://app.example.com/path":              "",
+			},
+			statuses: map[string]configs.AnnotationStatus{"from-to-www-redirect": configs.AnnotationConverted},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := newContext(tt.annotations, nil)
+
+			middleware.Redirects(ctx)
+
+			redirect := findMiddleware(t, ctx, tt.middleware).Spec.RedirectRegex
+			if redirect == nil {
+				t.Fatal("no RedirectRegex middleware was generated")
+			}
+
+			if redirect.Permanent != tt.permanent {
+				t.Errorf("permanent = %t, want %t", redirect.Permanent, tt.permanent)
+			}
+
+			for request, want := range tt.requests {
+				if got := redirectURL(t, redirect, request); got != want {
+					t.Errorf("%s redirects to %q, want %q", request, got, want)
+				}
+			}
+
+			for annotation, status := range tt.statuses {
+				if got := statuses(ctx, annotation); !slices.Equal(got, []configs.AnnotationStatus{status}) {
+					t.Errorf("%s statuses = %v, want [%s]", annotation, got, status)
+				}
+			}
+		})
+	}
+}
+
+func TestRedirectsSkipped(t *testing.T) {
+	tests := []struct {
+		name        string
+		annotations map[string]string
+		annotation  string
+	}{
+		{
+			name:        "relative redirect URL",
+			annotations: map[string]string{"permanent-redirect": "/new"},
+			annotation:  "permanent-redirect",
+		},
+		{
+			name:        "app root without a path",
+			annotations: map[string]string{"app-root": "/"},
+			annotation:  "app-root",
+		},
+		{
+			name:        "www redirect disabled",
+			annotations: map[string]string{"from-to-www-redirect": "false"},
+			annotation:  "from-to-www-redirect",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := newContext(tt.annotations, nil)
+
+			middleware.Redirects(ctx)
+
+			if len(ctx.Result.Middlewares) != 0 {
+				t.Errorf("no middleware should be generated, got %d", len(ctx.Result.Middlewares))
+			}
+
+			if got := statuses(ctx, tt.annotation); !slices.Equal(got, []configs.AnnotationStatus{configs.AnnotationSkipped}) {
+				t.Errorf("%s statuses = %v, want [skipped]", tt.annotation, got)
+			}
+		})
+	}
+}
+
+func TestWWWCounterpart(t *testing.T) {
+	ctx := newContext(map[string]string{"from-to-www-redirect": "true"}, nil)
+
+	hosts := map[string]string{
+		"app.example.com":     "www.app.example.com",
+		"www.app.example.com": "app.example.com",
+		"*.example.com":       "",
+		"":                    "",
+	}
+
+	for host, want := range hosts {
+		if got := middleware.WWWCounterpart(ctx, host); got != want {
+			t.Errorf("WWWCounterpart(%q) = %q, want %q", host, got, want)
+		}
+	}
+
+	if got := middleware.WWWCounterpart(newContext(nil, nil), "app.example.com"); got != "" {
+		t.Errorf("WWWCounterpart() = %q without from-to-www-redirect, want none", got)
+	}
+}
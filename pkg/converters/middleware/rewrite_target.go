@@ -26,9 +26,10 @@ func RewriteTargets(ctx configs.Context) {
 	}
 
 	if strings.Contains(val, "$") {
-		ctx.Result.Warnings = append(ctx.Result.Warnings,
-			"rewrite-target uses capture groups which cannot be safely converted without path context",
-		)
+		warningMessage := "rewrite-target uses capture groups which cannot be safely converted without path context"
+
+		ctx.Result.Warnings = append(ctx.Result.Warnings, warningMessage)
+		ctx.ReportSkipped(annRewriteTarget, warningMessage)
 
 		return
 	}
@@ -5,9 +5,10 @@ import (
 
 	"github.com/nikhilsbhat/nginx-traefik-converter/pkg/configs"
 	"github.com/nikhilsbhat/nginx-traefik-converter/pkg/converters/models"
+	"github.com/nikhilsbhat/nginx-traefik-converter/pkg/nginx"
 )
 
-/* ---------------- PROXY REDIRECT ---------------- */
+/* ---------------- SERVER SNIPPET ---------------- */
 
 // ServerSnippet handles the below annotations.
 // Annotations:
@@ -18,8 +19,18 @@ func ServerSnippet(ctx configs.Context) {
 		return
 	}
 
-	// 1) Header-only server-snippet (heuristic)
-	if isOnlyAddHeader(snippet) {
+	directives, err := nginx.Parse(snippet)
+	if err != nil {
+		warningMessage := "server-snippet is not valid NGINX configuration (" + err.Error() + "); skipped"
+
+		ctx.Result.Warnings = append(ctx.Result.Warnings, warningMessage)
+		ctx.ReportSkipped(string(models.ServerSnippet), warningMessage)
+
+		return
+	}
+
+	// 1) Header-only server-snippet
+	if isOnlyAddHeader(directives) {
 		warningMessage := "server-snippet contains only add_header directives. " +
 			"These were not auto-converted because server-snippet applies " +
 			"at NGINX server scope. Consider moving them to " +
@@ -28,28 +39,26 @@ func ServerSnippet(ctx configs.Context) {
 
 		ctx.Result.Warnings = append(ctx.Result.Warnings, warningMessage)
 
-		ctx.ReportSkipped(ctx.Annotations[string(models.ServerSnippet)], warningMessage)
+		ctx.ReportSkipped(string(models.ServerSnippet), warningMessage)
 
 		return
 	}
 
 	// 2) Header buffer tuning (static Traefik config)
-	if strings.Contains(snippet, "client_header_buffer_size") ||
-		strings.Contains(snippet, "large_client_header_buffers") {
-		ctx.Result.Warnings = append(ctx.Result.Warnings,
-			"server-snippet configures request header buffer sizes. "+
-				"Traefik does not support per-route header buffer tuning. "+
-				"Equivalent settings must be configured globally on entryPoints "+
-				"(e.g. http.maxHeaderBytes) in Traefik static configuration.",
-		)
+	if nginx.Find(directives, "client_header_buffer_size", "large_client_header_buffers") != nil {
+		warningMessage := "server-snippet configures request header buffer sizes. " +
+			"Traefik does not support per-route header buffer tuning. " +
+			"Equivalent settings must be configured globally on entryPoints " +
+			"(e.g. http.maxHeaderBytes) in Traefik static configuration."
+
+		ctx.Result.Warnings = append(ctx.Result.Warnings, warningMessage)
+		ctx.ReportSkipped(string(models.ServerSnippet), warningMessage)
 
 		return
 	}
 
 	// 3) Timeout tuning (proxy / send timeouts)
-	if strings.Contains(snippet, "proxy_read_timeout") ||
-		strings.Contains(snippet, "proxy_send_timeout") ||
-		strings.Contains(snippet, "send_timeout") {
+	if nginx.Find(directives, "proxy_read_timeout", "proxy_send_timeout", "send_timeout") != nil {
 		warningMessage := "server-snippet configures timeout settings (proxy/send timeouts). " +
 			"These cannot be set per-route in Traefik. Consider using ServersTransport " +
 			"(e.g. forwardingTimeouts) in dynamic configuration or static config instead."
@@ -61,7 +70,7 @@ func ServerSnippet(ctx configs.Context) {
 	}
 
 	// 4) TLS knobs (ssl_* / proxy_ssl_*)
-	if strings.Contains(snippet, "ssl_") || strings.Contains(snippet, "proxy_ssl_") {
+	if nginx.FindPrefix(directives, "ssl_", "proxy_ssl_") != nil {
 		warningMessage := "server-snippet configures TLS-related directives. " +
 			"These cannot be safely auto-converted. In Traefik, use TLSOption " +
 			"and/or ServersTransport for TLS configuration."
@@ -73,7 +82,7 @@ func ServerSnippet(ctx configs.Context) {
 	}
 
 	// 5) Rate limiting (limit_req / limit_conn)
-	if strings.Contains(snippet, "limit_req") || strings.Contains(snippet, "limit_conn") {
+	if nginx.FindPrefix(directives, "limit_req", "limit_conn") != nil {
 		warningMessage := "server-snippet configures NGINX rate limiting (limit_req/limit_conn). " +
 			"Traefik provides a RateLimit middleware, but semantics differ and this cannot be " +
 			"auto-converted safely."
@@ -88,18 +97,17 @@ func ServerSnippet(ctx configs.Context) {
 
 	ctx.Result.Warnings = append(ctx.Result.Warnings, warningMessage)
 
-	ctx.ReportSkipped(ctx.Annotations[string(models.ServerSnippet)], warningMessage)
+	ctx.ReportSkipped(string(models.ServerSnippet), warningMessage)
 }
 
-func isOnlyAddHeader(snippet string) bool {
-	lines := strings.Split(snippet, "\n")
-	for _, l := range lines {
-		line := strings.TrimSpace(l)
-		if line == "" {
-			continue
-		}
+// isOnlyAddHeader reports whether the snippet consists of top-level add_header directives only.
+func isOnlyAddHeader(directives []*nginx.Directive) bool {
+	if len(directives) == 0 {
+		return false
+	}
 
-		if !strings.HasPrefix(line, "add_header") {
+	for _, directive := range directives {
+		if directive.Name != "add_header" {
 			return false
 		}
 	}
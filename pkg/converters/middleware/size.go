@@ -8,7 +8,8 @@ import (
 	"github.com/nikhilsbhat/nginx-traefik-converter/pkg/errors"
 )
 
-func parseSizeBytes(val string) (int64, error) {
+// ParseSizeBytes parses an NGINX size, such as 8k or 1m, into bytes.
+func ParseSizeBytes(val string) (int64, error) {
 	value := strings.TrimSpace(strings.ToLower(val))
 
 	const byteValue = 1024
@@ -34,3 +35,27 @@ func parseSizeBytes(val string) (int64, error) {
 
 	return n * multiplier, nil
 }
+
+// MaxHeaderBytes parses the large-client-header-buffers value, "<number> <size>", into the largest amount of
+// request headers NGINX accepts, which Traefik limits through maxHeaderBytes.
+func MaxHeaderBytes(val string) (int64, error) {
+	fields := strings.Fields(val)
+
+	const bufferFields = 2
+
+	if len(fields) != bufferFields {
+		return 0, &errors.ConverterError{Message: fmt.Sprintf("invalid large-client-header-buffers value: %s", val)}
+	}
+
+	number, err := strconv.ParseInt(fields[0], 10, 64)
+	if err != nil || number <= 0 {
+		return 0, &errors.ConverterError{Message: fmt.Sprintf("invalid large-client-header-buffers value: %s", val)}
+	}
+
+	size, err := ParseSizeBytes(fields[1])
+	if err != nil {
+		return 0, err
+	}
+
+	return number * size, nil
+}
@@ -0,0 +1,191 @@
+package middleware
+
+import (
+	"fmt"
+	"net"
+	"path"
+	"regexp"
+	"strings"
+
+	"github.com/nikhilsbhat/nginx-traefik-converter/pkg/configs"
+	"github.com/nikhilsbhat/nginx-traefik-converter/pkg/converters/models"
+	"github.com/nikhilsbhat/nginx-traefik-converter/pkg/errors"
+	"github.com/nikhilsbhat/nginx-traefik-converter/pkg/nginx"
+	"github.com/traefik/traefik/v3/pkg/config/dynamic"
+	traefik "github.com/traefik/traefik/v3/pkg/provider/kubernetes/crd/traefikio/v1alpha1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+/* ---------------- SNIPPET ACCESS CONTROL ---------------- */
+
+// secretNameRe matches the characters which are not allowed in the name of a Secret.
+var secretNameRe = regexp.MustCompile(`[^a-z0-9.-]+`)
+
+// snippetAccess collects the access control directives of a snippet, which NGINX evaluates together
+// in the access phase: the allow and deny rules in order, basic authentication and how both combine.
+type snippetAccess struct {
+	rules    []*nginx.Directive
+	auth     *nginx.Directive
+	userFile *nginx.Directive
+	satisfy  *nginx.Directive
+}
+
+// add records an access control directive, the last auth_basic, auth_basic_user_file and satisfy win as in NGINX.
+func (access *snippetAccess) add(directive *nginx.Directive) {
+	switch directive.Name {
+	case "allow", "deny":
+		access.rules = append(access.rules, directive)
+	case "auth_basic":
+		access.auth = directive
+	case "auth_basic_user_file":
+		access.userFile = directive
+	case "satisfy":
+		access.satisfy = directive
+	}
+}
+
+// basicAuth reports whether the directives enable basic authentication.
+func (access *snippetAccess) basicAuth() bool {
+	return access.auth != nil && !(len(access.auth.Args) == 1 && access.auth.Args[0] == "off")
+}
+
+// convertSnippetAccess converts the allow and deny rules to an IPAllowList and auth_basic to a BasicAuth middleware.
+// It returns the warnings explaining the conversion and the directives which could not be converted.
+func convertSnippetAccess(ctx configs.Context, access *snippetAccess) ([]string, []string) {
+	warnings := make([]string, 0)
+	skipped := make([]string, 0)
+
+	skip := func(directive *nginx.Directive, msg string) {
+		msg = directive.Name + " at " + directive.Position() + " was not converted: " + msg
+
+		warnings = append(warnings, msg)
+		skipped = append(skipped, msg)
+	}
+
+	if access.satisfy != nil && len(access.satisfy.Args) == 1 && access.satisfy.Args[0] == "any" &&
+		len(access.rules) > 0 && access.basicAuth() {
+		skip(access.satisfy, "satisfy any grants access when either the allow and deny rules or basic authentication pass, "+
+			"while every Traefik middleware of a route must pass; the IP restriction and basic authentication "+
+			"must be migrated manually, for example with a dedicated route for the allowed source ranges")
+
+		return warnings, skipped
+	}
+
+	if len(access.rules) > 0 {
+		ranges, rule, err := allowListRanges(access.rules)
+		if err != nil {
+			skip(rule, err.Error())
+		} else {
+			ctx.Result.Middlewares = append(ctx.Result.Middlewares, &traefik.Middleware{
+				TypeMeta: metav1.TypeMeta{
+					APIVersion: traefik.SchemeGroupVersion.String(),
+					Kind:       "Middleware",
+				},
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      mwName(ctx, "configuration-snippet-ip-allowlist"),
+					Namespace: ctx.Namespace,
+				},
+				Spec: traefik.MiddlewareSpec{
+					IPAllowList: &dynamic.IPAllowList{
+						SourceRange: ranges,
+						IPStrategy:  clientIPStrategy(ctx),
+					},
+				},
+			})
+		}
+	}
+
+	switch {
+	case access.auth == nil:
+		if access.userFile != nil {
+			warnings = append(warnings,
+				"auth_basic_user_file at "+access.userFile.Position()+" has no effect without auth_basic and was ignored",
+			)
+		}
+
+	case !access.basicAuth():
+		warnings = append(warnings,
+			"auth_basic off at "+access.auth.Position()+" only disables basic authentication inherited from the "+
+				"NGINX server, which Traefik does not have; it was ignored",
+		)
+
+	case access.userFile == nil:
+		skip(access.auth, "auth_basic requires auth_basic_user_file")
+
+	case strings.TrimSpace(ctx.Annotations[string(models.AuthType)]) == "basic":
+		warnings = append(warnings,
+			"auth_basic at "+access.auth.Position()+" duplicates auth-type basic; "+
+				"the BasicAuth middleware of the auth-type annotation is used",
+		)
+
+	default:
+		secret := strings.TrimSpace(ctx.Annotations[string(models.AuthSecret)])
+		if secret == "" {
+			secret = userFileSecret(access.userFile)
+
+			warnings = append(warnings, fmt.Sprintf(
+				"auth_basic_user_file %s is a file of the NGINX controller; create the Secret %s/%s "+
+					"holding its htpasswd entries under the users key for the BasicAuth middleware",
+				strings.Join(access.userFile.Args, " "), ctx.Namespace, secret,
+			))
+		}
+
+		ctx.Result.Middlewares = append(ctx.Result.Middlewares,
+			newBasicAuthMiddleware(ctx, secret, strings.Join(access.auth.Args, " ")),
+		)
+	}
+
+	return warnings, skipped
+}
+
+// allowListRanges returns the source ranges of allow rules ending with deny all, the only ordering
+// an IPAllowList can represent since NGINX applies the first matching rule.
+// On error, the rule preventing the conversion is returned.
+func allowListRanges(rules []*nginx.Directive) ([]string, *nginx.Directive, error) {
+	const allowListOnly = "only allow rules followed by a final deny all can be converted to an IPAllowList"
+
+	last := rules[len(rules)-1]
+
+	if last.Name != "deny" || len(last.Args) != 1 || last.Args[0] != "all" {
+		return nil, last, &errors.ConverterError{Message: allowListOnly}
+	}
+
+	if len(rules) == 1 {
+		return nil, last, &errors.ConverterError{Message: "deny all without allow rules rejects every request"}
+	}
+
+	ranges := make([]string, 0, len(rules)-1)
+
+	for _, rule := range rules[:len(rules)-1] {
+		if rule.Name != "allow" {
+			return nil, rule, &errors.ConverterError{Message: "deny rules before the final deny all are not supported; " + allowListOnly}
+		}
+
+		if len(rule.Args) != 1 {
+			return nil, rule, &errors.ConverterError{Message: "allow expects a single address"}
+		}
+
+		if _, _, err := net.ParseCIDR(rule.Args[0]); err != nil && net.ParseIP(rule.Args[0]) == nil {
+			return nil, rule, &errors.ConverterError{Message: fmt.Sprintf("%q is not an IP or CIDR", rule.Args[0])}
+		}
+
+		ranges = append(ranges, rule.Args[0])
+	}
+
+	return ranges, nil, nil
+}
+
+// userFileSecret derives the name of the Secret holding the users from the name of the htpasswd file.
+func userFileSecret(userFile *nginx.Directive) string {
+	name := ""
+	if len(userFile.Args) > 0 {
+		name = path.Base(userFile.Args[0])
+	}
+
+	name = strings.Trim(secretNameRe.ReplaceAllString(strings.ToLower(name), "-"), "-.")
+	if name == "" {
+		return "basic-auth"
+	}
+
+	return name
+}
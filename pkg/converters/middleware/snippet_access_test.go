@@ -0,0 +1,186 @@
+package middleware_test
+
+import (
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/nikhilsbhat/nginx-traefik-converter/pkg/configs"
+	"github.com/nikhilsbhat/nginx-traefik-converter/pkg/converters/middleware"
+)
+
+func TestConfigurationSnippetsAllowList(t *testing.T) {
+	snippet := "allow 10.0.0.0/8;\nallow 192.168.1.10;\nallow 2001:db8::/32;\ndeny all;"
+
+	for _, trustForwarded := range []bool{false, true} {
+		opts := configs.NewOptions()
+		opts.TrustForwardedHeaders = trustForwarded
+
+		ctx := newContext(map[string]string{"configuration-snippet": snippet}, opts)
+
+		if err := middleware.ConfigurationSnippets(ctx); err != nil {
+			t.Fatalf("ConfigurationSnippets() error = %v", err)
+		}
+
+		allowList := findMiddleware(t, ctx, "app-configuration-snippet-ip-allowlist").Spec.IPAllowList
+
+		if want := []string{"10.0.0.0/8", "192.168.1.10", "2001:db8::/32"}; !slices.Equal(allowList.SourceRange, want) {
+			t.Errorf("source range = %v, want %v", allowList.SourceRange, want)
+		}
+
+		// the client IP comes from X-Forwarded-For only when use-forwarded-headers is set
+		if (allowList.IPStrategy != nil) != trustForwarded {
+			t.Errorf("trust forwarded headers %t: ip strategy = %+v", trustForwarded, allowList.IPStrategy)
+		}
+
+		if got := statuses(ctx, "configuration-snippet"); !slices.Equal(got, []configs.AnnotationStatus{configs.AnnotationConverted}) {
+			t.Errorf("statuses = %v, want converted", got)
+		}
+	}
+}
+
+func TestConfigurationSnippetsBasicAuth(t *testing.T) {
+	tests := []struct {
+		name        string
+		annotations map[string]string
+		secret      string
+		realm       string
+		warning     string
+	}{
+		{
+			name: "secret named after the htpasswd file",
+			annotations: map[string]string{
+				"configuration-snippet": "auth_basic \"Restricted Area\";\nauth_basic_user_file /etc/nginx/Admin_Users.htpasswd;",
+			},
+			secret:  "admin-users.htpasswd",
+			realm:   "Restricted Area",
+			warning: "create the Secret default/admin-users.htpasswd",
+		},
+		{
+			name: "auth-secret annotation",
+			annotations: map[string]string{
+				"configuration-snippet": "auth_basic admins;\nauth_basic_user_file /etc/nginx/htpasswd;",
+				"auth-secret":           "admins-htpasswd",
+			},
+			secret: "admins-htpasswd",
+			realm:  "admins",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			ctx := newContext(test.annotations, nil)
+
+			if err := middleware.ConfigurationSnippets(ctx); err != nil {
+				t.Fatalf("ConfigurationSnippets() error = %v", err)
+			}
+
+			auth := findMiddleware(t, ctx, "app-basicauth").Spec.BasicAuth
+
+			if auth.Secret != test.secret || auth.Realm != test.realm {
+				t.Errorf("basic auth = %+v, want secret %s and realm %s", auth, test.secret, test.realm)
+			}
+
+			warned := slices.ContainsFunc(ctx.Result.Warnings, func(warning string) bool {
+				return strings.Contains(warning, "create the Secret")
+			})
+			if warned != (test.warning != "") {
+				t.Errorf("warnings = %v", ctx.Result.Warnings)
+			}
+
+			if got := statuses(ctx, "configuration-snippet"); !slices.Equal(got, []configs.AnnotationStatus{configs.AnnotationConverted}) {
+				t.Errorf("statuses = %v, want converted", got)
+			}
+		})
+	}
+}
+
+func TestConfigurationSnippetsAccessNotConverted(t *testing.T) {
+	tests := []struct {
+		name        string
+		annotations map[string]string
+		status      configs.AnnotationStatus
+		middlewares []string
+		message     string
+	}{
+		{
+			name:        "deny before the final deny all",
+			annotations: map[string]string{"configuration-snippet": "allow 10.0.0.0/8;\ndeny 10.1.0.0/16;\ndeny all;"},
+			status:      configs.AnnotationSkipped,
+			message:     "deny at line 2, column 1 was not converted",
+		},
+		{
+			name:        "allow without a final deny all",
+			annotations: map[string]string{"configuration-snippet": "deny 10.1.0.0/16;\nallow all;"},
+			status:      configs.AnnotationSkipped,
+			message:     "allow at line 2, column 1 was not converted",
+		},
+		{
+			name:        "deny all alone",
+			annotations: map[string]string{"configuration-snippet": "deny all;"},
+			status:      configs.AnnotationSkipped,
+			message:     "rejects every request",
+		},
+		{
+			name:        "invalid address",
+			annotations: map[string]string{"configuration-snippet": "allow 10.0.0.300;\ndeny all;"},
+			status:      configs.AnnotationSkipped,
+			message:     `"10.0.0.300" is not an IP or CIDR`,
+		},
+		{
+			name: "satisfy any",
+			annotations: map[string]string{
+				"configuration-snippet": "satisfy any;\nallow 10.0.0.0/8;\ndeny all;\nauth_basic on;\nauth_basic_user_file /etc/htpasswd;",
+			},
+			status:  configs.AnnotationSkipped,
+			message: "satisfy at line 1, column 1 was not converted",
+		},
+		{
+			name:        "auth_basic without user file",
+			annotations: map[string]string{"configuration-snippet": "add_header X-A a;\nauth_basic on;"},
+			status:      configs.AnnotationWarned,
+			middlewares: []string{"app-configuration-snippet"},
+			message:     "auth_basic at line 2, column 1 was not converted: auth_basic requires auth_basic_user_file",
+		},
+		{
+			name:        "auth_basic off",
+			annotations: map[string]string{"configuration-snippet": "auth_basic off;\nauth_basic_user_file /etc/htpasswd;"},
+			status:      configs.AnnotationConverted,
+		},
+		{
+			name: "duplicate of auth-type basic",
+			annotations: map[string]string{
+				"configuration-snippet": "auth_basic on;\nauth_basic_user_file /etc/htpasswd;",
+				"auth-type":             "basic",
+			},
+			status: configs.AnnotationConverted,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			ctx := newContext(test.annotations, nil)
+
+			if err := middleware.ConfigurationSnippets(ctx); err != nil {
+				t.Fatalf("ConfigurationSnippets() error = %v", err)
+			}
+
+			names := make([]string, 0, len(ctx.Result.Middlewares))
+			for _, generated := range ctx.Result.Middlewares {
+				names = append(names, generated.Name)
+			}
+
+			if !slices.Equal(names, test.middlewares) && (len(names) > 0 || len(test.middlewares) > 0) {
+				t.Errorf("middlewares = %v, want %v", names, test.middlewares)
+			}
+
+			if got := statuses(ctx, "configuration-snippet"); !slices.Equal(got, []configs.AnnotationStatus{test.status}) {
+				t.Errorf("statuses = %v, want [%s]", got, test.status)
+			}
+
+			if msg := message(ctx, "configuration-snippet"); !strings.Contains(msg, test.message) {
+				t.Errorf("report message %q does not contain %q", msg, test.message)
+			}
+		})
+	}
+}
@@ -0,0 +1,134 @@
+package middleware
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/nikhilsbhat/nginx-traefik-converter/pkg/errors"
+	"github.com/nikhilsbhat/nginx-traefik-converter/pkg/nginx"
+)
+
+/* ---------------- SNIPPET HEADERS ---------------- */
+
+const (
+	cacheControlHeader = "Cache-Control"
+
+	// expiresMax is the max-age NGINX sends for expires max, ten years.
+	expiresMax = 315360000
+)
+
+// nginxTimeUnits holds the seconds of the units NGINX accepts in time values measured in seconds.
+var nginxTimeUnits = map[rune]int64{
+	'y': 365 * 24 * 60 * 60,
+	'M': 30 * 24 * 60 * 60,
+	'w': 7 * 24 * 60 * 60,
+	'd': 24 * 60 * 60,
+	'h': 60 * 60,
+	'm': 60,
+	's': 1,
+}
+
+// parseClearHeaders returns the header names of more_clear_headers, more_clear_input_headers and proxy_hide_header.
+// filtered reports whether -s or -t filters were dropped, the headers are then cleared from every message.
+func parseClearHeaders(directive *nginx.Directive) ([]string, bool) {
+	names := make([]string, 0, len(directive.Args))
+	filtered := false
+
+	for index := 0; index < len(directive.Args); index++ {
+		arg := directive.Args[index]
+
+		if arg == "-s" || arg == "-t" {
+			filtered = true
+			index++
+
+			continue
+		}
+
+		names = append(names, strings.Fields(arg)...)
+	}
+
+	return names, filtered
+}
+
+// expiresCacheControl returns the Cache-Control header NGINX sends for expires, empty for expires off.
+// The Expires header is not returned, as it depends on the time of the request.
+func expiresCacheControl(directive *nginx.Directive) (string, error) {
+	if len(directive.Args) != 1 {
+		return "", &errors.ConverterError{Message: "only expires with a single time, epoch, max or off can be converted"}
+	}
+
+	value := directive.Args[0]
+
+	switch {
+	case value == "off":
+		return "", nil
+	case value == "epoch":
+		return "no-cache", nil
+	case value == "max":
+		return "max-age=" + strconv.Itoa(expiresMax), nil
+	case strings.HasPrefix(value, "@"):
+		return "", &errors.ConverterError{Message: "expires at a time of the day depends on the time of the request"}
+	}
+
+	seconds, err := parseNginxSeconds(value)
+	if err != nil {
+		return "", err
+	}
+
+	if seconds < 0 {
+		return "no-cache", nil
+	}
+
+	return "max-age=" + strconv.FormatInt(seconds, 10), nil
+}
+
+// parseNginxSeconds converts an NGINX time value such as 30d or "1h 30m", where a plain number means seconds.
+func parseNginxSeconds(val string) (int64, error) {
+	invalid := &errors.ConverterError{Message: fmt.Sprintf("invalid time value %q", val)}
+
+	value, negative := strings.CutPrefix(strings.ReplaceAll(val, " ", ""), "-")
+	if value == "" {
+		return 0, invalid
+	}
+
+	var total, number int64
+
+	digits := false
+
+	for _, char := range value {
+		if char >= '0' && char <= '9' {
+			number = number*10 + int64(char-'0')
+			digits = true
+
+			continue
+		}
+
+		unit, ok := nginxTimeUnits[char]
+		if !ok || !digits {
+			return 0, invalid
+		}
+
+		total += number * unit
+		number, digits = 0, false
+	}
+
+	total += number
+
+	if negative {
+		return -total, nil
+	}
+
+	return total, nil
+}
+
+// headerKey returns the key of the header in the map, matched case-insensitively, empty when it is not set.
+func headerKey(headers map[string]string, name string) string {
+	for key := range headers {
+		if strings.EqualFold(key, name) {
+			return key
+		}
+	}
+
+	return ""
+}
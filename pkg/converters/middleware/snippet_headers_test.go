@@ -0,0 +1,174 @@
+package middleware_test
+
+import (
+	"maps"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/nikhilsbhat/nginx-traefik-converter/pkg/configs"
+	"github.com/nikhilsbhat/nginx-traefik-converter/pkg/converters/middleware"
+)
+
+func TestConfigurationSnippetsExpires(t *testing.T) {
+	tests := []struct {
+		name         string
+		snippet      string
+		cacheControl string
+		status       configs.AnnotationStatus
+		message      string
+	}{
+		{name: "seconds", snippet: "expires 300;", cacheControl: "max-age=300", status: configs.AnnotationConverted},
+		{name: "units", snippet: "expires 1d;", cacheControl: "max-age=86400", status: configs.AnnotationConverted},
+		{name: "combined units", snippet: `expires "1h 30m";`, cacheControl: "max-age=5400", status: configs.AnnotationConverted},
+		{name: "max", snippet: "expires max;", cacheControl: "max-age=315360000", status: configs.AnnotationConverted},
+		{name: "epoch", snippet: "expires epoch;", cacheControl: "no-cache", status: configs.AnnotationConverted},
+		{name: "negative", snippet: "expires -1;", cacheControl: "no-cache", status: configs.AnnotationConverted},
+		{name: "off", snippet: "add_header X-A a;\nexpires off;", status: configs.AnnotationConverted},
+		{
+			name:         "explicit Cache-Control wins",
+			snippet:      "expires 1h;\nadd_header cache-control private;",
+			cacheControl: "",
+			status:       configs.AnnotationWarned,
+			message:      "expires at line 1, column 1 was ignored",
+		},
+		{
+			name:    "time of the day",
+			snippet: "expires @15h30m;",
+			status:  configs.AnnotationSkipped,
+			message: "expires at line 1, column 1 was not converted: expires at a time of the day",
+		},
+		{
+			name:    "invalid time",
+			snippet: "expires 1x;",
+			status:  configs.AnnotationSkipped,
+			message: `invalid time value "1x"`,
+		},
+		{
+			name:    "modified time",
+			snippet: "expires modified 1h;",
+			status:  configs.AnnotationSkipped,
+			message: "only expires with a single time",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			ctx := newContext(map[string]string{"configuration-snippet": test.snippet}, nil)
+
+			if err := middleware.ConfigurationSnippets(ctx); err != nil {
+				t.Fatalf("ConfigurationSnippets() error = %v", err)
+			}
+
+			if got := statuses(ctx, "configuration-snippet"); !slices.Equal(got, []configs.AnnotationStatus{test.status}) {
+				t.Errorf("statuses = %v, want [%s]", got, test.status)
+			}
+
+			if msg := message(ctx, "configuration-snippet"); !strings.Contains(msg, test.message) {
+				t.Errorf("report message %q does not contain %q", msg, test.message)
+			}
+
+			if test.status == configs.AnnotationSkipped {
+				return
+			}
+
+			headers := findMiddleware(t, ctx, "app-configuration-snippet").Spec.Headers.CustomResponseHeaders
+			if got := headers["Cache-Control"]; got != test.cacheControl {
+				t.Errorf("Cache-Control = %q, want %q", got, test.cacheControl)
+			}
+		})
+	}
+}
+
+func TestConfigurationSnippetsClearHeaders(t *testing.T) {
+	ctx := newContext(map[string]string{
+		"configuration-snippet": "more_clear_headers 'Server X-Powered-By';\nproxy_hide_header X-Runtime;\n" +
+			"more_clear_input_headers -t 'text/html' X-Internal;",
+	}, nil)
+
+	if err := middleware.ConfigurationSnippets(ctx); err != nil {
+		t.Fatalf("ConfigurationSnippets() error = %v", err)
+	}
+
+	headers := findMiddleware(t, ctx, "app-configuration-snippet").Spec.Headers
+
+	if want := map[string]string{"Server": "", "X-Powered-By": "", "X-Runtime": ""}; !maps.Equal(headers.CustomResponseHeaders, want) {
+		t.Errorf("response headers = %v, want %v", headers.CustomResponseHeaders, want)
+	}
+
+	if want := map[string]string{"X-Internal": ""}; !maps.Equal(headers.CustomRequestHeaders, want) {
+		t.Errorf("request headers = %v, want %v", headers.CustomRequestHeaders, want)
+	}
+
+	// the dropped content type filter makes the result differ from NGINX
+	if got := statuses(ctx, "configuration-snippet"); !slices.Equal(got, []configs.AnnotationStatus{configs.AnnotationWarned}) {
+		t.Errorf("statuses = %v, want warning", got)
+	}
+}
+
+func TestConfigurationSnippetsUpgradeHeaders(t *testing.T) {
+	tests := []struct {
+		name       string
+		snippet    string
+		status     configs.AnnotationStatus
+		middleware bool
+		messages   []string
+	}{
+		{
+			name:    "only no-ops",
+			snippet: "proxy_set_header Upgrade $http_upgrade;\nproxy_set_header Connection \"upgrade\";",
+			status:  configs.AnnotationIgnored,
+			messages: []string{
+				"proxy_set_header Upgrade at line 1, column 1 has no effect",
+				"proxy_set_header Connection at line 2, column 1 has no effect",
+			},
+		},
+		{
+			name:       "with converted headers",
+			snippet:    "proxy_set_header Upgrade $http_upgrade;\nproxy_set_header X-Tenant acme;",
+			status:     configs.AnnotationConverted,
+			middleware: true,
+		},
+		{
+			name:       "with dropped directives",
+			snippet:    "proxy_set_header Connection upgrade;\nproxy_set_header X-Tenant acme;\nproxy_read_timeout 3600;",
+			status:     configs.AnnotationWarned,
+			middleware: true,
+			messages: []string{
+				"line 3, column 1: proxy_read_timeout 3600;",
+				"proxy_set_header Connection at line 1, column 1 has no effect",
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			ctx := newContext(map[string]string{"configuration-snippet": test.snippet}, nil)
+
+			if err := middleware.ConfigurationSnippets(ctx); err != nil {
+				t.Fatalf("ConfigurationSnippets() error = %v", err)
+			}
+
+			// one entry, whatever the number of directives
+			if got := statuses(ctx, "configuration-snippet"); !slices.Equal(got, []configs.AnnotationStatus{test.status}) {
+				t.Errorf("statuses = %v, want [%s]", got, test.status)
+			}
+
+			if test.middleware {
+				headers := findMiddleware(t, ctx, "app-configuration-snippet").Spec.Headers.CustomRequestHeaders
+				if want := map[string]string{"X-Tenant": "acme"}; !maps.Equal(headers, want) {
+					t.Errorf("request headers = %v, want %v", headers, want)
+				}
+			} else if len(ctx.Result.Middlewares) > 0 {
+				t.Errorf("got %d middlewares, want none", len(ctx.Result.Middlewares))
+			}
+
+			msg := message(ctx, "configuration-snippet")
+			for _, want := range test.messages {
+				if !strings.Contains(msg, want) {
+					t.Errorf("report message %q does not contain %q", msg, want)
+				}
+			}
+		})
+	}
+}
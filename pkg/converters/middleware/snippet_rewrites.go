@@ -0,0 +1,374 @@
+package middleware
+
+import (
+	"fmt"
+	"net/http"
+	"regexp"
+	"regexp/syntax"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/nikhilsbhat/nginx-traefik-converter/pkg/errors"
+	"github.com/nikhilsbhat/nginx-traefik-converter/pkg/nginx"
+	"github.com/traefik/traefik/v3/pkg/config/dynamic"
+	traefik "github.com/traefik/traefik/v3/pkg/provider/kubernetes/crd/traefikio/v1alpha1"
+)
+
+/* ---------------- SNIPPET REWRITES ---------------- */
+
+const (
+	snippetRewrite  = "rewrite"
+	snippetRedirect = "redirect"
+
+	// originGroup captures the scheme and host of the URL matched by RedirectRegex, ahead of the path.
+	originGroup = `^(https?://[^/]+)`
+
+	// queryGroup captures the query string of the URL matched by RedirectRegex, behind the path.
+	queryGroup = `(\?.*)?$`
+
+	// queryMark separates the path from the query string.
+	queryMark = '?'
+
+	// statusCloseConnection is the non-standard NGINX code closing the connection without a response.
+	statusCloseConnection = 444
+)
+
+// captureRe matches the $1 and ${1} captures NGINX substitutes in rewrite replacements.
+var captureRe = regexp.MustCompile(`\$(\d+|\{\d+\})`)
+
+// flagsRe matches the flags a rewrite regex may start with, as in (?i)^/path.
+var flagsRe = regexp.MustCompile(`^\(\?[imsU]+\)`)
+
+// snippetMiddleware is the middleware a rewrite or return directive of a snippet converts to.
+// kind names the middleware, one of snippetRewrite or snippetRedirect.
+type snippetMiddleware struct {
+	kind string
+	spec traefik.MiddlewareSpec
+}
+
+// convertRewrite converts rewrite <regex> <replacement> [flag].
+// The permanent and redirect flags, as well as absolute replacements, become a RedirectRegex,
+// the last and break flags become a ReplacePathRegex. The warning explains how the result differs from NGINX.
+func convertRewrite(directive *nginx.Directive) (*snippetMiddleware, string, error) {
+	const (
+		rewriteArgs     = 2
+		rewriteFlagArgs = 3
+	)
+
+	if len(directive.Args) != rewriteArgs && len(directive.Args) != rewriteFlagArgs {
+		return nil, "", &errors.ConverterError{Message: "rewrite expects a regex, a replacement and an optional flag"}
+	}
+
+	pattern, replacement, flag := directive.Args[0], directive.Args[1], ""
+	if len(directive.Args) == rewriteFlagArgs {
+		flag = directive.Args[2]
+	}
+
+	switch flag {
+	case "", "last", "break", "redirect", "permanent":
+	default:
+		return nil, "", &errors.ConverterError{Message: fmt.Sprintf("unknown rewrite flag %q", flag)}
+	}
+
+	compiled, err := regexp.Compile(pattern)
+	if err != nil {
+		return nil, "", &errors.ConverterError{
+			Message: fmt.Sprintf("regex %q is not supported by Go regular expressions", pattern),
+		}
+	}
+
+	path, err := pathPattern(pattern)
+	if err != nil {
+		return nil, "", err
+	}
+
+	absolute := strings.HasPrefix(replacement, "http://") || strings.HasPrefix(replacement, "https://")
+
+	if flag == "redirect" || flag == "permanent" || absolute {
+		return rewriteRedirect(path, replacement, compiled.NumSubexp(), absolute, flag == "permanent")
+	}
+
+	target, ok := translateCaptures(replacement, 0)
+	if !ok {
+		return nil, "", variablesError("rewrite replacement", replacement)
+	}
+
+	if strings.Contains(target, "?") {
+		return nil, "", &errors.ConverterError{
+			Message: "rewrites changing the query string cannot be converted, ReplacePathRegex only replaces the path",
+		}
+	}
+
+	return &snippetMiddleware{
+		kind: snippetRewrite,
+		spec: traefik.MiddlewareSpec{
+			ReplacePathRegex: &dynamic.ReplacePathRegex{
+				Regex:       "^" + path + "$",
+				Replacement: target,
+			},
+		},
+	}, "", nil
+}
+
+// rewriteRedirect converts a redirecting rewrite. RedirectRegex matches the full URL, so the path regex
+// is confined to the path, prefixed with the origin and followed by the query string, which NGINX appends
+// to the redirect unless the replacement ends with "?".
+func rewriteRedirect(path, replacement string, groups int, absolute, permanent bool) (*snippetMiddleware, string, error) {
+	path, err := confineToPath(path)
+	if err != nil {
+		return nil, "", err
+	}
+
+	target, ok := translateCaptures(replacement, 1)
+	if !ok {
+		return nil, "", variablesError("rewrite replacement", replacement)
+	}
+
+	if !absolute {
+		if !strings.HasPrefix(target, "/") {
+			return nil, "", &errors.ConverterError{
+				Message: fmt.Sprintf("rewrite replacement %q is neither a path nor an absolute URL", replacement),
+			}
+		}
+
+		target = "${1}" + target
+	}
+
+	warning := ""
+
+	switch {
+	case strings.HasSuffix(target, "?"):
+		target = strings.TrimSuffix(target, "?")
+	case strings.Contains(target, "?"):
+		warning = fmt.Sprintf("rewrite to %q does not append the query string of the request as NGINX does", replacement)
+	default:
+		target += fmt.Sprintf("${%d}", groups+2)
+	}
+
+	return &snippetMiddleware{
+		kind: snippetRedirect,
+		spec: traefik.MiddlewareSpec{
+			RedirectRegex: &dynamic.RedirectRegex{
+				Regex:       originGroup + path + queryGroup,
+				Replacement: target,
+				Permanent:   permanent,
+			},
+		},
+	}, warning, nil
+}
+
+// convertReturn converts return <code> [text|URL] and return <URL>.
+// Redirect codes become a RedirectRegex, the other codes a conditionalReturn rule for every request.
+func convertReturn(directive *nginx.Directive) (*snippetMiddleware, *conditionalReturnConfig, string, error) {
+	const (
+		returnURLArgs  = 1
+		returnCodeArgs = 2
+	)
+
+	args := directive.Args
+
+	if len(args) == returnURLArgs && (strings.HasPrefix(args[0], "http://") || strings.HasPrefix(args[0], "https://")) {
+		args = []string{strconv.Itoa(http.StatusFound), args[0]}
+	}
+
+	if len(args) == 0 || len(args) > returnCodeArgs {
+		return nil, nil, "", &errors.ConverterError{Message: "return expects a code and an optional text or URL"}
+	}
+
+	code, err := strconv.Atoi(args[0])
+	if err != nil || code < http.StatusContinue || code > 599 {
+		return nil, nil, "", &errors.ConverterError{Message: fmt.Sprintf("invalid return code %q", args[0])}
+	}
+
+	text := ""
+	if len(args) == returnCodeArgs {
+		text = args[1]
+	}
+
+	switch code {
+	case statusCloseConnection:
+		return nil, nil, "", &errors.ConverterError{
+			Message: "return 444 closes the connection without a response, which Traefik cannot do",
+		}
+
+	case http.StatusMovedPermanently, http.StatusFound, http.StatusSeeOther,
+		http.StatusTemporaryRedirect, http.StatusPermanentRedirect:
+		redirect, warning, err := returnRedirect(code, text)
+		if err != nil {
+			return nil, nil, "", err
+		}
+
+		return redirect, nil, warning, nil
+	}
+
+	if strings.Contains(text, "$") {
+		return nil, nil, "", variablesError("return text", text)
+	}
+
+	return nil, &conditionalReturnConfig{StatusCode: code, Body: text}, "", nil
+}
+
+// returnRedirect converts return <3xx> <URL>, relative URLs are resolved against the requested origin as NGINX does.
+func returnRedirect(code int, target string) (*snippetMiddleware, string, error) {
+	if target == "" {
+		return nil, "", &errors.ConverterError{Message: fmt.Sprintf("return %d has no redirect URL", code)}
+	}
+
+	if strings.Contains(target, "$") {
+		return nil, "", variablesError("return URL", target)
+	}
+
+	redirect := &dynamic.RedirectRegex{
+		Regex:       "^.*$",
+		Replacement: target,
+		Permanent:   isPermanentCode(code),
+	}
+
+	if strings.HasPrefix(target, "/") {
+		redirect.Regex = originGroup + ".*$"
+		redirect.Replacement = "${1}" + target
+	}
+
+	warning := ""
+	if redirectCode(redirect.Permanent) != code {
+		warning = fmt.Sprintf("Traefik redirects with %d (or %d for non GET requests) instead of %d",
+			redirectCode(redirect.Permanent), methodPreservingCode(redirect.Permanent), code)
+	}
+
+	return &snippetMiddleware{
+		kind: snippetRedirect,
+		spec: traefik.MiddlewareSpec{RedirectRegex: redirect},
+	}, warning, nil
+}
+
+// pathPattern returns the rewrite regex without its anchors, extended to match the whole path
+// as Traefik replaces the matched part only while NGINX replaces the whole URI. Leading flags are kept ahead of it.
+func pathPattern(pattern string) (string, error) {
+	flags := flagsRe.FindString(pattern)
+	pattern = pattern[len(flags):]
+
+	if hasTopLevelAlternation(pattern) {
+		return "", &errors.ConverterError{
+			Message: fmt.Sprintf("rewrite regex %q has top-level alternatives which cannot be anchored to the path", pattern),
+		}
+	}
+
+	body, anchoredStart := strings.CutPrefix(pattern, "^")
+	if !anchoredStart {
+		body = "[^?]*?" + body
+	}
+
+	if strings.HasSuffix(body, "$") && !strings.HasSuffix(body, `\$`) {
+		body = strings.TrimSuffix(body, "$")
+	} else {
+		body += "[^?]*"
+	}
+
+	return flags + body, nil
+}
+
+// confineToPath rewrites the path regex so that it cannot match the query string, which NGINX does not
+// match rewrites against: the dot and the character classes, negated ones included, exclude "?".
+func confineToPath(path string) (string, error) {
+	parsed, err := syntax.Parse(path, syntax.Perl)
+	if err != nil {
+		return "", &errors.ConverterError{
+			Message: fmt.Sprintf("regex %q is not supported by Go regular expressions", path),
+		}
+	}
+
+	excludeQueryMark(parsed)
+
+	return parsed.String(), nil
+}
+
+func excludeQueryMark(parsed *syntax.Regexp) {
+	switch parsed.Op {
+	case syntax.OpAnyChar:
+		parsed.Op = syntax.OpCharClass
+		parsed.Rune = withoutRune([]rune{0, unicode.MaxRune}, queryMark)
+	case syntax.OpAnyCharNotNL:
+		parsed.Op = syntax.OpCharClass
+		parsed.Rune = withoutRune([]rune{0, '\n' - 1, '\n' + 1, unicode.MaxRune}, queryMark)
+	case syntax.OpCharClass:
+		parsed.Rune = withoutRune(parsed.Rune, queryMark)
+	}
+
+	for _, sub := range parsed.Sub {
+		excludeQueryMark(sub)
+	}
+}
+
+// withoutRune removes the rune from the lo-hi pairs of a character class.
+func withoutRune(ranges []rune, excluded rune) []rune {
+	out := make([]rune, 0, len(ranges))
+
+	for index := 0; index+1 < len(ranges); index += 2 {
+		low, high := ranges[index], ranges[index+1]
+
+		if excluded < low || excluded > high {
+			out = append(out, low, high)
+
+			continue
+		}
+
+		if low < excluded {
+			out = append(out, low, excluded-1)
+		}
+
+		if excluded < high {
+			out = append(out, excluded+1, high)
+		}
+	}
+
+	return out
+}
+
+// hasTopLevelAlternation reports whether the regex has a | outside of any group or character class.
+func hasTopLevelAlternation(pattern string) bool {
+	depth, class, escaped := 0, false, false
+
+	for _, char := range pattern {
+		switch {
+		case escaped:
+			escaped = false
+		case char == '\\':
+			escaped = true
+		case class:
+			class = char != ']'
+		case char == '[':
+			class = true
+		case char == '(':
+			depth++
+		case char == ')':
+			depth--
+		case char == '|' && depth == 0:
+			return true
+		}
+	}
+
+	return false
+}
+
+// translateCaptures converts the NGINX $1 captures of the replacement to the ${1} form of Go regexes,
+// shifted by the given number of groups. It reports false when the replacement uses other NGINX variables.
+func translateCaptures(replacement string, shift int) (string, bool) {
+	translated := captureRe.ReplaceAllStringFunc(replacement, func(capture string) string {
+		index, _ := strconv.Atoi(strings.Trim(capture, "${}"))
+
+		return "\x00{" + strconv.Itoa(index+shift) + "}"
+	})
+
+	if strings.Contains(translated, "$") {
+		return "", false
+	}
+
+	return strings.ReplaceAll(translated, "\x00", "$"), true
+}
+
+func variablesError(what, value string) error {
+	return &errors.ConverterError{
+		Message: fmt.Sprintf("%s %q uses NGINX variables which Traefik cannot evaluate", what, value),
+	}
+}
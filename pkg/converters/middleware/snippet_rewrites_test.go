@@ -0,0 +1,250 @@
+package middleware_test
+
+import (
+	"regexp"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/nikhilsbhat/nginx-traefik-converter/pkg/configs"
+	"github.com/nikhilsbhat/nginx-traefik-converter/pkg/converters/middleware"
+)
+
+func TestConfigurationSnippetsRewriteRedirect(t *testing.T) {
+	tests := []struct {
+		name      string
+		snippet   string
+		permanent bool
+		requests  map[string]string
+		warning   string
+	}{
+		{
+			name:      "query string appended",
+			snippet:   "rewrite ^/old/(.*)$ /new/$1 permanent;",
+			permanent: true,
+			requests: map[string]string{
+				"https://app.example.com/old/a/b":         "https://app.example.com/new/a/b",
+				"https://app.example.com/old/a/b?x=1&y=2": "https://app.example.com/new/a/b?x=1&y=2",
+				"https://app.example.com/other/old/a":     "",
+			},
+		},
+		{
+			name:      "query string dropped",
+			snippet:   "rewrite ^/old/(.*)$ /new/$1? permanent;",
+			permanent: true,
+			requests: map[string]string{
+				"https://app.example.com/old/a":        "https://app.example.com/new/a",
+				"https://app.example.com/old/a?x=1":    "https://app.example.com/new/a",
+				"http://app.example.com:8080/old/?x=1": "http://app.example.com:8080/new/",
+			},
+		},
+		{
+			name:    "replacement with its own query string",
+			snippet: "rewrite ^/old/(.*)$ /new?id=$1 redirect;",
+			requests: map[string]string{
+				"https://app.example.com/old/42":        "https://app.example.com/new?id=42",
+				"https://app.example.com/old/42?page=3": "https://app.example.com/new?id=42",
+			},
+			warning: "does not append the query string of the request",
+		},
+		{
+			name:    "negated class and dot stay on the path",
+			snippet: `rewrite ^/docs/([^/]+)/(.+)$ https://docs.example.com/$1/$2 redirect;`,
+			requests: map[string]string{
+				"https://app.example.com/docs/v1/intro?lang=en": "https://docs.example.com/v1/intro?lang=en",
+				"https://app.example.com/docs/v1?lang=en/x":     "",
+			},
+		},
+		{
+			name:    "unanchored regex matches anywhere in the path",
+			snippet: "rewrite /legacy/ /modern/ redirect;",
+			requests: map[string]string{
+				"https://app.example.com/a/legacy/b?q=1": "https://app.example.com/modern/?q=1",
+				"https://app.example.com/a/modern/b?q=1": "",
+			},
+		},
+		{
+			name:    "case-insensitive regex",
+			snippet: "rewrite (?i)^/Old$ /new redirect;",
+			requests: map[string]string{
+				"https://app.example.com/OLD?x=1": "https://app.example.com/new?x=1",
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			ctx := newContext(map[string]string{"configuration-snippet": test.snippet}, nil)
+
+			if err := middleware.ConfigurationSnippets(ctx); err != nil {
+				t.Fatalf("ConfigurationSnippets() error = %v", err)
+			}
+
+			redirect := findMiddleware(t, ctx, "app-configuration-snippet-redirect").Spec.RedirectRegex
+			if redirect == nil {
+				t.Fatal("no RedirectRegex was generated")
+			}
+
+			if redirect.Permanent != test.permanent {
+				t.Errorf("permanent = %t, want %t", redirect.Permanent, test.permanent)
+			}
+
+			for request, want := range test.requests {
+				if got := redirectURL(t, redirect, request); got != want {
+					t.Errorf("%s redirects to %q, want %q (regex %q, replacement %q)",
+						request, got, want, redirect.Regex, redirect.Replacement)
+				}
+			}
+
+			warned := slices.ContainsFunc(ctx.Result.Warnings, func(warning string) bool {
+				return test.warning != "" && strings.Contains(warning, test.warning)
+			})
+			if test.warning != "" && !warned {
+				t.Errorf("warnings %v do not contain %q", ctx.Result.Warnings, test.warning)
+			}
+		})
+	}
+}
+
+func TestConfigurationSnippetsRewritePath(t *testing.T) {
+	tests := []struct {
+		name     string
+		snippet  string
+		requests map[string]string
+	}{
+		{
+			name:    "captures",
+			snippet: "rewrite ^/api/v1/(.*)$ /v1/$1 break;",
+			requests: map[string]string{
+				"/api/v1/users": "/v1/users",
+				"/other":        "/other",
+			},
+		},
+		{
+			name:    "alternation inside a group",
+			snippet: "rewrite ^/(app|web)/(.*)$ /static/${2} last;",
+			requests: map[string]string{
+				"/web/index.html": "/static/index.html",
+				"/api/index.html": "/api/index.html",
+			},
+		},
+		{
+			name:    "prefix without end anchor replaces the whole path",
+			snippet: "rewrite ^/shop /store last;",
+			requests: map[string]string{
+				"/shop/cart": "/store",
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			ctx := newContext(map[string]string{"configuration-snippet": test.snippet}, nil)
+
+			if err := middleware.ConfigurationSnippets(ctx); err != nil {
+				t.Fatalf("ConfigurationSnippets() error = %v", err)
+			}
+
+			replace := findMiddleware(t, ctx, "app-configuration-snippet-rewrite").Spec.ReplacePathRegex
+			if replace == nil {
+				t.Fatal("no ReplacePathRegex was generated")
+			}
+
+			regex := regexp.MustCompile(replace.Regex)
+
+			for path, want := range test.requests {
+				got := path
+				if regex.MatchString(path) {
+					got = regex.ReplaceAllString(path, replace.Replacement)
+				}
+
+				if got != want {
+					t.Errorf("%s is rewritten to %q, want %q", path, got, want)
+				}
+			}
+		})
+	}
+}
+
+func TestConfigurationSnippetsRewriteSkipped(t *testing.T) {
+	tests := []struct {
+		name    string
+		snippet string
+		message string
+	}{
+		{
+			name:    "top-level alternation",
+			snippet: "rewrite ^/a|^/b /c last;",
+			message: "top-level alternatives",
+		},
+		{
+			name:    "NGINX variables",
+			snippet: "rewrite ^/(.*)$ /$host/$1 last;",
+			message: "uses NGINX variables",
+		},
+		{
+			name:    "query string of a path rewrite",
+			snippet: "rewrite ^/search/(.*)$ /find?q=$1 last;",
+			message: "changing the query string",
+		},
+		{
+			name:    "unknown flag",
+			snippet: "rewrite ^/a /b forever;",
+			message: `unknown rewrite flag "forever"`,
+		},
+		{
+			name:    "close connection",
+			snippet: "return 444;",
+			message: "return 444 closes the connection",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			ctx := newContext(map[string]string{"configuration-snippet": test.snippet}, nil)
+
+			if err := middleware.ConfigurationSnippets(ctx); err != nil {
+				t.Fatalf("ConfigurationSnippets() error = %v", err)
+			}
+
+			if got := statuses(ctx, "configuration-snippet"); !slices.Equal(got, []configs.AnnotationStatus{configs.AnnotationSkipped}) {
+				t.Errorf("statuses = %v, want skipped", got)
+			}
+
+			if msg := message(ctx, "configuration-snippet"); !strings.Contains(msg, "at line 1, column 1") ||
+				!strings.Contains(msg, test.message) {
+				t.Errorf("report message %q does not contain the position and %q", msg, test.message)
+			}
+		})
+	}
+}
+
+func TestConfigurationSnippetsReturn(t *testing.T) {
+	ctx := newContext(map[string]string{"configuration-snippet": "return 301 /maintenance;\nrewrite ^ /never last;"}, nil)
+
+	if err := middleware.ConfigurationSnippets(ctx); err != nil {
+		t.Fatalf("ConfigurationSnippets() error = %v", err)
+	}
+
+	redirect := findMiddleware(t, ctx, "app-configuration-snippet-redirect").Spec.RedirectRegex
+
+	if got := redirectURL(t, redirect, "https://app.example.com/any?x=1"); got != "https://app.example.com/maintenance" {
+		t.Errorf("redirects to %q", got)
+	}
+
+	// the rewrite after the return is never reached in NGINX either
+	if len(ctx.Result.Middlewares) != 1 {
+		t.Errorf("got %d middlewares, want the redirect only", len(ctx.Result.Middlewares))
+	}
+
+	ctx = newContext(map[string]string{"configuration-snippet": "return 503 'down for maintenance';"}, nil)
+
+	if err := middleware.ConfigurationSnippets(ctx); err != nil {
+		t.Fatalf("ConfigurationSnippets() error = %v", err)
+	}
+
+	plugin := findMiddleware(t, ctx, "app-conditional-return").Spec.Plugin[configs.PluginConditionalReturn]
+	if !strings.Contains(string(plugin.Raw), `"statusCode":503`) || !strings.Contains(string(plugin.Raw), `"body":"down for maintenance"`) {
+		t.Errorf("plugin configuration = %s", plugin.Raw)
+	}
+}
@@ -0,0 +1,169 @@
+package middleware
+
+import (
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/nikhilsbhat/nginx-traefik-converter/pkg/configs"
+)
+
+/* ---------------- SNIPPET VARIABLES ---------------- */
+
+// variableRe matches the $name and ${name} NGINX variables of a value.
+var variableRe = regexp.MustCompile(`\$(\w+|\{\w+\})`)
+
+// nativeRequestHeaders are set by Traefik on every forwarded request, with the NGINX variables holding the same value.
+var nativeRequestHeaders = map[string][]string{
+	"X-Forwarded-For":    {"$proxy_add_x_forwarded_for", "$remote_addr"},
+	"X-Forwarded-Host":   {"$host", "$http_host"},
+	"X-Forwarded-Port":   {"$server_port"},
+	"X-Forwarded-Proto":  {"$scheme"},
+	"X-Forwarded-Server": {"$hostname"},
+	"X-Real-Ip":          {"$remote_addr"},
+	"Host":               {"$host", "$http_host"},
+}
+
+// hostVariables hold the requested host, which is known when the ingress serves a single host.
+var hostVariables = []string{"host", "http_host", "server_name"}
+
+// requestVariables are evaluated for every request, which the static values of the headers middleware cannot express.
+var requestVariables = map[string]string{
+	"remote_addr":               "the client address",
+	"proxy_add_x_forwarded_for": "the X-Forwarded-For chain of the request",
+	"scheme":                    "the scheme of the request",
+	"request_uri":               "the URI of the request",
+	"uri":                       "the path of the request",
+	"args":                      "the query string of the request",
+	"server_port":               "the port of the request",
+	"request_method":            "the method of the request",
+}
+
+// translateHeaderValue translates the NGINX variables of a header value set by a snippet.
+// When the header cannot be set by the headers middleware, the reason names every variable left untranslated.
+func translateHeaderValue(ctx configs.Context, value string) (string, string, bool) {
+	if !variableRe.MatchString(value) {
+		return value, "", true
+	}
+
+	reasons := make([]string, 0)
+	perRequest := make([]string, 0)
+
+	translated := variableRe.ReplaceAllStringFunc(value, func(variable string) string {
+		variableName := strings.Trim(variable, "${}")
+
+		switch {
+		case slices.Contains(hostVariables, variableName):
+			if host := singleHost(ctx); host != "" {
+				return host
+			}
+
+			reasons = appendUnique(reasons, "$"+variableName+" is only known when the ingress serves a single host")
+		case variableName == "scheme":
+			if scheme := requestScheme(ctx); scheme != "" {
+				return scheme
+			}
+
+			reasons = appendUnique(reasons, "$scheme is only known when either all or none of the hosts of the ingress "+
+				"are covered by spec.tls")
+		default:
+			// Traefik has no variables, only a plugin could set the header per request and none is known for it.
+			perRequest = appendUnique(perRequest, "$"+variableName+" holds "+describeVariable(variableName))
+		}
+
+		return variable
+	})
+
+	if len(perRequest) > 0 {
+		reasons = append(reasons, strings.Join(perRequest, ", ")+", which the Traefik headers middleware cannot evaluate, "+
+			"and no Traefik plugin with a known module sets headers from request values")
+	}
+
+	if len(reasons) > 0 {
+		return "", strings.Join(reasons, "; ") + "; the header is left out rather than sent with the literal variable", false
+	}
+
+	return translated, "", true
+}
+
+// nativeRequestHeader reports whether Traefik already forwards the header with the value of the variable.
+// Other values of these headers are not native, they are translated as any other header.
+func nativeRequestHeader(name, value string) (string, bool) {
+	value = strings.ReplaceAll(strings.ReplaceAll(strings.TrimSpace(value), "${", "$"), "}", "")
+
+	for native, variables := range nativeRequestHeaders {
+		if strings.EqualFold(name, native) && slices.Contains(variables, value) {
+			return "Traefik sets " + native + " to " + value + " on every forwarded request", true
+		}
+	}
+
+	if value == "$http_"+strings.ToLower(strings.ReplaceAll(name, "-", "_")) {
+		return "the request header is forwarded unchanged by Traefik", true
+	}
+
+	return "", false
+}
+
+func describeVariable(name string) string {
+	if description, ok := requestVariables[name]; ok {
+		return description
+	}
+
+	if header, ok := strings.CutPrefix(name, "http_"); ok {
+		return "the " + strings.ReplaceAll(header, "_", "-") + " header of the request"
+	}
+
+	return "a value computed by NGINX"
+}
+
+// singleHost returns the host of the ingress when all its rules share a single, non-wildcard host.
+func singleHost(ctx configs.Context) string {
+	if ctx.Ingress == nil {
+		return ""
+	}
+
+	host := ""
+
+	for _, rule := range ctx.Ingress.Spec.Rules {
+		if rule.Host == "" || strings.HasPrefix(rule.Host, "*") || (host != "" && rule.Host != host) {
+			return ""
+		}
+
+		host = rule.Host
+	}
+
+	return host
+}
+
+// requestScheme returns the scheme of the requests served by the ingress: the hosts covered by spec.tls are served
+// on the websecure entry point only and the others on web, so it is known when either all or none of them are covered.
+func requestScheme(ctx configs.Context) string {
+	if ctx.Ingress == nil {
+		return ""
+	}
+
+	if len(ctx.Ingress.Spec.TLS) == 0 {
+		return "http"
+	}
+
+	tlsHosts := make([]string, 0)
+	for _, tls := range ctx.Ingress.Spec.TLS {
+		tlsHosts = append(tlsHosts, tls.Hosts...)
+	}
+
+	for _, rule := range ctx.Ingress.Spec.Rules {
+		if !slices.Contains(tlsHosts, rule.Host) {
+			return ""
+		}
+	}
+
+	return "https"
+}
+
+func appendUnique(values []string, value string) []string {
+	if slices.Contains(values, value) {
+		return values
+	}
+
+	return append(values, value)
+}
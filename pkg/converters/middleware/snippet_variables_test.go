@@ -0,0 +1,153 @@
+package middleware_test
+
+import (
+	"maps"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/nikhilsbhat/nginx-traefik-converter/pkg/configs"
+	"github.com/nikhilsbhat/nginx-traefik-converter/pkg/converters/middleware"
+	netv1 "k8s.io/api/networking/v1"
+)
+
+func TestConfigurationSnippetsVariables(t *testing.T) {
+	tests := []struct {
+		name     string
+		snippet  string
+		hosts    []string
+		tls      []string
+		status   configs.AnnotationStatus
+		request  map[string]string
+		response map[string]string
+		message  string
+	}{
+		{
+			name: "headers forwarded natively",
+			snippet: "proxy_set_header X-Real-IP $remote_addr;\n" +
+				"proxy_set_header X-Forwarded-For $proxy_add_x_forwarded_for;\n" +
+				"proxy_set_header X-Forwarded-Proto ${scheme};\n" +
+				"proxy_set_header X-Forwarded-Host $http_host;\n" +
+				"proxy_set_header X-Forwarded-Port $server_port;\n" +
+				"proxy_set_header Host $host;\n" +
+				"proxy_set_header X-Client-Id $http_x_client_id;",
+			status:  configs.AnnotationIgnored,
+			message: "proxy_set_header X-Real-IP at line 1, column 1 has no effect: Traefik sets X-Real-Ip to $remote_addr",
+		},
+		{
+			name:    "static value of a forwarded header",
+			snippet: "proxy_set_header X-Forwarded-Proto https;",
+			status:  configs.AnnotationConverted,
+			request: map[string]string{"X-Forwarded-Proto": "https"},
+		},
+		{
+			name:    "forwarded header with another variable",
+			snippet: "proxy_set_header X-Forwarded-Proto $http_x_original_proto;",
+			status:  configs.AnnotationSkipped,
+			message: "proxy_set_header X-Forwarded-Proto at line 1, column 1 was left out: $http_x_original_proto holds " +
+				"the x-original-proto header of the request",
+		},
+		{
+			name:    "forwarded header extending the variable",
+			snippet: "proxy_set_header X-Forwarded-For \"$remote_addr, 10.0.0.1\";\nproxy_set_header X-Tenant acme;",
+			status:  configs.AnnotationWarned,
+			request: map[string]string{"X-Tenant": "acme"},
+			message: "$remote_addr holds the client address, which the Traefik headers middleware cannot evaluate, " +
+				"and no Traefik plugin with a known module sets headers from request values",
+		},
+		{
+			name:     "host of a single host ingress",
+			snippet:  "proxy_set_header X-Original-Host $host;\nadd_header Link \"<https://${host}/>; rel=canonical\";",
+			status:   configs.AnnotationConverted,
+			request:  map[string]string{"X-Original-Host": "app.example.com"},
+			response: map[string]string{"Link": "<https://app.example.com/>; rel=canonical"},
+		},
+		{
+			name:    "host of a multi host ingress",
+			snippet: "add_header X-Host $host;",
+			hosts:   []string{"other.example.com"},
+			status:  configs.AnnotationSkipped,
+			message: "$host is only known when the ingress serves a single host",
+		},
+		{
+			name:     "per-request variables of response headers",
+			snippet:  "add_header X-Static static;\nadd_header X-Uri $request_uri;\nadd_header X-Price 10$;",
+			status:   configs.AnnotationWarned,
+			response: map[string]string{"X-Price": "10$", "X-Static": "static"},
+			message:  "add_header X-Uri at line 2, column 1 was left out: $request_uri holds the URI of the request",
+		},
+		{
+			name:    "every dropped variable is named",
+			snippet: "add_header X-Client \"$remote_addr $http_user_agent $remote_addr\";",
+			status:  configs.AnnotationSkipped,
+			message: "$remote_addr holds the client address, $http_user_agent holds the user-agent header of the request, " +
+				"which the Traefik headers middleware cannot evaluate",
+		},
+		{
+			name:     "scheme of an ingress without TLS",
+			snippet:  "add_header X-Scheme $scheme;",
+			status:   configs.AnnotationConverted,
+			response: map[string]string{"X-Scheme": "http"},
+		},
+		{
+			name:     "scheme of an ingress with all its hosts covered by TLS",
+			snippet:  "add_header X-Scheme $scheme;",
+			tls:      []string{"app.example.com"},
+			status:   configs.AnnotationConverted,
+			response: map[string]string{"X-Scheme": "https"},
+		},
+		{
+			name:    "scheme of an ingress mixing TLS and plain hosts",
+			snippet: "add_header X-Scheme $scheme;",
+			hosts:   []string{"other.example.com"},
+			tls:     []string{"app.example.com"},
+			status:  configs.AnnotationSkipped,
+			message: "$scheme is only known when either all or none of the hosts of the ingress are covered by spec.tls",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			ctx := newContext(map[string]string{"configuration-snippet": test.snippet}, nil)
+
+			for _, host := range test.hosts {
+				ctx.Ingress.Spec.Rules = append(ctx.Ingress.Spec.Rules, netv1.IngressRule{Host: host})
+			}
+
+			if len(test.tls) > 0 {
+				ctx.Ingress.Spec.TLS = []netv1.IngressTLS{{Hosts: test.tls, SecretName: "app-tls"}}
+			}
+
+			if err := middleware.ConfigurationSnippets(ctx); err != nil {
+				t.Fatalf("ConfigurationSnippets() error = %v", err)
+			}
+
+			if got := statuses(ctx, "configuration-snippet"); !slices.Equal(got, []configs.AnnotationStatus{test.status}) {
+				t.Errorf("statuses = %v, want [%s]", got, test.status)
+			}
+
+			if msg := message(ctx, "configuration-snippet"); !strings.Contains(msg, test.message) {
+				t.Errorf("report message %q does not contain %q", msg, test.message)
+			}
+
+			if len(test.request) == 0 && len(test.response) == 0 {
+				if len(ctx.Result.Middlewares) > 0 {
+					t.Errorf("got %d middlewares, want none", len(ctx.Result.Middlewares))
+				}
+
+				return
+			}
+
+			headers := findMiddleware(t, ctx, "app-configuration-snippet").Spec.Headers
+
+			// no header is ever sent with a literal NGINX variable
+			if !maps.Equal(headers.CustomRequestHeaders, test.request) && (len(headers.CustomRequestHeaders) > 0 || len(test.request) > 0) {
+				t.Errorf("request headers = %v, want %v", headers.CustomRequestHeaders, test.request)
+			}
+
+			if !maps.Equal(headers.CustomResponseHeaders, test.response) && (len(headers.CustomResponseHeaders) > 0 || len(test.response) > 0) {
+				t.Errorf("response headers = %v, want %v", headers.CustomResponseHeaders, test.response)
+			}
+		})
+	}
+}
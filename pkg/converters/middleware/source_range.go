@@ -0,0 +1,212 @@
+package middleware
+
+import (
+	"encoding/json"
+	"net"
+	"strings"
+
+	"github.com/nikhilsbhat/nginx-traefik-converter/pkg/configs"
+	"github.com/nikhilsbhat/nginx-traefik-converter/pkg/converters/models"
+	"github.com/traefik/traefik/v3/pkg/config/dynamic"
+	traefik "github.com/traefik/traefik/v3/pkg/provider/kubernetes/crd/traefikio/v1alpha1"
+	apiextv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+/* ---------------- SOURCE RANGE ---------------- */
+
+// SourceRange handles the below annotations.
+// Annotations:
+//   - "nginx.ingress.kubernetes.io/whitelist-source-range"
+//   - "nginx.ingress.kubernetes.io/allowlist-source-range"
+//   - "nginx.ingress.kubernetes.io/denylist-source-range"
+func SourceRange(ctx configs.Context) {
+	ctx.Log.Debug("running converter SourceRange")
+
+	allowList(ctx)
+	denyList(ctx)
+}
+
+func allowList(ctx configs.Context) {
+	annWhitelist := string(models.WhitelistSourceRange)
+	annAllowlist := string(models.AllowlistSourceRange)
+
+	whitelist, hasWhitelist := ctx.Annotation(annWhitelist)
+	allowlist, hasAllowlist := ctx.Annotation(annAllowlist)
+
+	present := presentAnnotations(ctx, annWhitelist, annAllowlist)
+	if len(present) == 0 {
+		return
+	}
+
+	// allowlist-source-range supersedes the deprecated whitelist-source-range.
+	val, name := whitelist, "whitelist-source-range"
+	if hasAllowlist {
+		val, name = allowlist, "allowlist-source-range"
+	}
+
+	ranges, invalid := parseSourceRanges(val)
+	if len(invalid) > 0 || len(ranges) == 0 {
+		msg := sourceRangeError(name, invalid)
+
+		ctx.Result.Warnings = append(ctx.Result.Warnings, msg)
+
+		for _, ann := range present {
+			ctx.ReportSkipped(ann, msg)
+		}
+
+		return
+	}
+
+	ctx.Result.Middlewares = append(ctx.Result.Middlewares, &traefik.Middleware{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: traefik.SchemeGroupVersion.String(),
+			Kind:       "Middleware",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      mwName(ctx, "ip-allowlist"),
+			Namespace: ctx.Namespace,
+		},
+		Spec: traefik.MiddlewareSpec{
+			IPAllowList: &dynamic.IPAllowList{
+				SourceRange: ranges,
+				IPStrategy:  clientIPStrategy(ctx),
+			},
+		},
+	})
+
+	if hasWhitelist && hasAllowlist {
+		msg := "both whitelist-source-range and allowlist-source-range are set; allowlist-source-range was used"
+
+		ctx.Result.Warnings = append(ctx.Result.Warnings, msg)
+		ctx.ReportIgnored(annWhitelist, msg)
+		ctx.ReportConverted(annAllowlist)
+
+		return
+	}
+
+	for _, ann := range present {
+		ctx.ReportConverted(ann)
+	}
+}
+
+func denyList(ctx configs.Context) {
+	ann := string(models.DenylistSourceRange)
+
+	val, ok := ctx.Annotation(ann)
+	if !ok {
+		return
+	}
+
+	ranges, invalid := parseSourceRanges(val)
+	if len(invalid) > 0 || len(ranges) == 0 {
+		msg := sourceRangeError("denylist-source-range", invalid)
+
+		ctx.Result.Warnings = append(ctx.Result.Warnings, msg)
+		ctx.ReportSkipped(ann, msg)
+
+		return
+	}
+
+	if ctx.Options.DisablePlugins {
+		msg := "denylist-source-range has no native Traefik equivalent; enable plugins to use the denyip plugin, " +
+			"or restrict access with an IPAllowList middleware or a NetworkPolicy"
+
+		ctx.Result.Warnings = append(ctx.Result.Warnings, msg)
+		ctx.ReportSkipped(ann, msg)
+
+		return
+	}
+
+	raw, err := json.Marshal(map[string]any{
+		"ipDenyList": ranges,
+	})
+	if err != nil {
+		ctx.ReportSkipped(ann, err.Error())
+
+		return
+	}
+
+	ctx.Result.Middlewares = append(ctx.Result.Middlewares, &traefik.Middleware{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: traefik.SchemeGroupVersion.String(),
+			Kind:       "Middleware",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      mwName(ctx, "ip-denylist"),
+			Namespace: ctx.Namespace,
+		},
+		Spec: traefik.MiddlewareSpec{
+			Plugin: map[string]apiextv1.JSON{
+				configs.PluginDenyIP: {Raw: raw},
+			},
+		},
+	})
+
+	// The plugin only takes the ranges, it has no IP strategy such as the one of the allow list.
+	msg := "denylist-source-range was converted to the denyip plugin, which has no IP strategy; " +
+		"ingress-nginx matched the remote address of the connection. The plugin is declared in traefik-static.yaml"
+
+	if ctx.Options.TrustForwardedHeaders {
+		msg = "denylist-source-range was converted to the denyip plugin, which has no IP strategy; " +
+			"ingress-nginx matched the client IP from X-Forwarded-For as --trust-forwarded-headers is set, " +
+			"verify that clients behind the load balancer are denied. The plugin is declared in traefik-static.yaml"
+	}
+
+	ctx.Result.Warnings = append(ctx.Result.Warnings, msg)
+	ctx.ReportWarning(ann, msg)
+}
+
+// sourceRangeError explains why a source range annotation was not converted.
+func sourceRangeError(name string, invalid []string) string {
+	if len(invalid) == 0 {
+		return name + " is empty; the IP restriction was not converted and must be migrated manually"
+	}
+
+	return name + " contains invalid entries (" + strings.Join(invalid, ", ") + "); " +
+		"the IP restriction was not converted and must be migrated manually"
+}
+
+// parseSourceRanges splits a comma separated list of CIDRs or IPs, returning the valid and invalid entries.
+func parseSourceRanges(val string) ([]string, []string) {
+	ranges := make([]string, 0)
+	invalid := make([]string, 0)
+
+	for _, entry := range splitCSV(val) {
+		if _, _, err := net.ParseCIDR(entry); err == nil || net.ParseIP(entry) != nil {
+			ranges = append(ranges, entry)
+
+			continue
+		}
+
+		invalid = append(invalid, entry)
+	}
+
+	return ranges, invalid
+}
+
+// clientIPStrategy returns the strategy used by Traefik to resolve the client IP.
+// When ingress-nginx trusted X-Forwarded-For, the client IP is the right most entry added by the load balancer,
+// otherwise Traefik falls back to the remote address as ingress-nginx does.
+func clientIPStrategy(ctx configs.Context) *dynamic.IPStrategy {
+	if !ctx.Options.TrustForwardedHeaders {
+		return nil
+	}
+
+	return &dynamic.IPStrategy{
+		Depth: 1,
+	}
+}
+
+// presentAnnotations returns the given annotations that are set on the ingress.
+func presentAnnotations(ctx configs.Context, annotations ...string) []string {
+	present := make([]string, 0, len(annotations))
+
+	for _, ann := range annotations {
+		if _, ok := ctx.Annotation(ann); ok {
+			present = append(present, ann)
+		}
+	}
+
+	return present
+}
@@ -0,0 +1,133 @@
+package middleware_test
+
+import (
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/nikhilsbhat/nginx-traefik-converter/pkg/configs"
+	"github.com/nikhilsbhat/nginx-traefik-converter/pkg/converters/middleware"
+)
+
+func TestSourceRange(t *testing.T) {
+	tests := []struct {
+		name        string
+		annotations map[string]string
+		trust       bool
+		middleware  string
+		ranges      []string
+		depth       int
+		annotation  string
+		status      configs.AnnotationStatus
+		message     string
+	}{
+		{
+			name:        "allowlist",
+			annotations: map[string]string{"allowlist-source-range": "10.0.0.0/8, 192.168.1.1"},
+			middleware:  "app-ip-allowlist",
+			ranges:      []string{"10.0.0.0/8", "192.168.1.1"},
+			annotation:  "allowlist-source-range",
+			status:      configs.AnnotationConverted,
+		},
+		{
+			name:        "allowlist with forwarded headers trusted",
+			annotations: map[string]string{"whitelist-source-range": "10.0.0.0/8"},
+			trust:       true,
+			middleware:  "app-ip-allowlist",
+			ranges:      []string{"10.0.0.0/8"},
+			depth:       1,
+			annotation:  "whitelist-source-range",
+			status:      configs.AnnotationConverted,
+		},
+		{
+			name: "allowlist supersedes whitelist",
+			annotations: map[string]string{
+				"whitelist-source-range": "10.0.0.0/8",
+				"allowlist-source-range": "172.16.0.0/12",
+			},
+			middleware: "app-ip-allowlist",
+			ranges:     []string{"172.16.0.0/12"},
+			annotation: "whitelist-source-range",
+			status:     configs.AnnotationIgnored,
+		},
+		{
+			name:        "empty allowlist",
+			annotations: map[string]string{"allowlist-source-range": " "},
+			annotation:  "allowlist-source-range",
+			status:      configs.AnnotationSkipped,
+			message:     "allowlist-source-range is empty",
+		},
+		{
+			name:        "invalid allowlist",
+			annotations: map[string]string{"allowlist-source-range": "10.0.0.0/8,not-an-ip"},
+			annotation:  "allowlist-source-range",
+			status:      configs.AnnotationSkipped,
+			message:     "invalid entries (not-an-ip)",
+		},
+		{
+			name:        "empty denylist",
+			annotations: map[string]string{"denylist-source-range": ""},
+			annotation:  "denylist-source-range",
+			status:      configs.AnnotationSkipped,
+			message:     "denylist-source-range is empty",
+		},
+		{
+			name:        "denylist",
+			annotations: map[string]string{"denylist-source-range": "10.0.0.1"},
+			middleware:  "app-ip-denylist",
+			annotation:  "denylist-source-range",
+			status:      configs.AnnotationWarned,
+			message:     "matched the remote address",
+		},
+		{
+			name:        "denylist with forwarded headers trusted",
+			annotations: map[string]string{"denylist-source-range": "10.0.0.1"},
+			trust:       true,
+			middleware:  "app-ip-denylist",
+			annotation:  "denylist-source-range",
+			status:      configs.AnnotationWarned,
+			message:     "from X-Forwarded-For",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			opts := configs.NewOptions()
+			opts.TrustForwardedHeaders = tt.trust
+
+			ctx := newContext(tt.annotations, opts)
+
+			middleware.SourceRange(ctx)
+
+			if tt.middleware == "" && len(ctx.Result.Middlewares) > 0 {
+				t.Fatalf("no middleware expected, got %s", ctx.Result.Middlewares[0].Name)
+			}
+
+			if tt.middleware != "" {
+				generated := findMiddleware(t, ctx, tt.middleware)
+
+				if allowList := generated.Spec.IPAllowList; allowList != nil {
+					if !slices.Equal(allowList.SourceRange, tt.ranges) {
+						t.Errorf("sourceRange = %v, want %v", allowList.SourceRange, tt.ranges)
+					}
+
+					if tt.depth == 0 && allowList.IPStrategy != nil {
+						t.Errorf("ipStrategy = %+v, want none", allowList.IPStrategy)
+					}
+
+					if tt.depth > 0 && (allowList.IPStrategy == nil || allowList.IPStrategy.Depth != tt.depth) {
+						t.Errorf("ipStrategy = %+v, want depth %d", allowList.IPStrategy, tt.depth)
+					}
+				}
+			}
+
+			if got := statuses(ctx, tt.annotation); !slices.Contains(got, tt.status) {
+				t.Errorf("statuses of %s = %v, want %s", tt.annotation, got, tt.status)
+			}
+
+			if !strings.Contains(message(ctx, tt.annotation), tt.message) {
+				t.Errorf("message of %s = %q, want it to contain %q", tt.annotation, message(ctx, tt.annotation), tt.message)
+			}
+		})
+	}
+}
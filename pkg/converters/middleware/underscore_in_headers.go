@@ -13,7 +13,7 @@ import (
 func EnableUnderscoresInHeaders(ctx configs.Context) {
 	ann := string(models.UnderscoresInHeaders)
 
-	if _, ok := ctx.Annotations[ann]; !ok {
+	if _, ok := ctx.Annotation(ann); !ok {
 		return
 	}
 
@@ -2,43 +2,106 @@ package models
 
 type Annotation string
 
+// NginxAnnotationPrefix is the prefix shared by all the ingress-nginx annotations.
+const NginxAnnotationPrefix = "nginx.ingress.kubernetes.io/"
+
 const (
-	AuthType                 Annotation = "nginx.ingress.kubernetes.io/auth-type"
-	AuthSecret               Annotation = "nginx.ingress.kubernetes.io/auth-secret" //nolint:gosec
-	AuthRealm                Annotation = "nginx.ingress.kubernetes.io/auth-realm"
-	AuthTLSVerifyClient      Annotation = "nginx.ingress.kubernetes.io/auth-tls-verify-client"
-	AuthTLSSecret            Annotation = "nginx.ingress.kubernetes.io/auth-tls-secret" //nolint:gosec
-	AuthURL                  Annotation = "nginx.ingress.kubernetes.io/auth-url"
-	ProxyBodySize            Annotation = "nginx.ingress.kubernetes.io/proxy-body-size"
-	ConfigurationSnippet     Annotation = "nginx.ingress.kubernetes.io/configuration-snippet"
-	EnableCORS               Annotation = "nginx.ingress.kubernetes.io/enable-cors"
-	CorsAllowOrigin          Annotation = "nginx.ingress.kubernetes.io/cors-allow-origin"
-	CorsAllowMethods         Annotation = "nginx.ingress.kubernetes.io/cors-allow-methods"
-	CorsAllowHeaders         Annotation = "nginx.ingress.kubernetes.io/cors-allow-headers"
-	CorsAllowCredentials     Annotation = "nginx.ingress.kubernetes.io/cors-allow-credentials" //nolint:gosec
-	CorsMaxAge               Annotation = "nginx.ingress.kubernetes.io/cors-max-age"
-	CorsExposeHeaders        Annotation = "nginx.ingress.kubernetes.io/cors-expose-headers"
-	ProxyBuffering           Annotation = "nginx.ingress.kubernetes.io/proxy-buffering"
-	ServiceUpstream          Annotation = "nginx.ingress.kubernetes.io/service-upstream"
-	EnableOpentracing        Annotation = "nginx.ingress.kubernetes.io/enable-opentracing"
-	EnableOpentelemetry      Annotation = "nginx.ingress.kubernetes.io/enable-opentelemetry"
-	BackendProtocol          Annotation = "nginx.ingress.kubernetes.io/backend-protocol"
-	GrpcBackend              Annotation = "nginx.ingress.kubernetes.io/grpc-backend"
-	ProxyBufferSize          Annotation = "nginx.ingress.kubernetes.io/proxy-buffer-size"
-	LimitRPS                 Annotation = "nginx.ingress.kubernetes.io/limit-rps"
-	LimitBurstMultiplier     Annotation = "nginx.ingress.kubernetes.io/limit-burst-multiplier"
-	RewriteTarget            Annotation = "nginx.ingress.kubernetes.io/rewrite-target"
-	SSLRedirect              Annotation = "nginx.ingress.kubernetes.io/ssl-redirect"
-	ForceSSLRedirect         Annotation = "nginx.ingress.kubernetes.io/force-ssl-redirect"
-	UpstreamVhost            Annotation = "nginx.ingress.kubernetes.io/upstream-vhost"
-	ProxyRedirectFrom        Annotation = "nginx.ingress.kubernetes.io/proxy-redirect-from"
-	ProxyRedirectTo          Annotation = "nginx.ingress.kubernetes.io/proxy-redirect-to"
-	ProxyCookiePath          Annotation = "nginx.ingress.kubernetes.io/proxy-cookie-path"
-	ServerSnippet            Annotation = "nginx.ingress.kubernetes.io/server-snippet"
-	UnderscoresInHeaders     Annotation = "nginx.ingress.kubernetes.io/enable-underscores-in-headers"
-	UseRegex                 Annotation = "nginx.ingress.kubernetes.io/use-regex"
-	ClientHeaderBufferSize   Annotation = "nginx.ingress.kubernetes.io/client-header-buffer-size"
-	LargeClientHeaderBuffers Annotation = "nginx.ingress.kubernetes.io/large-client-header-buffers"
+	AuthType                     Annotation = "nginx.ingress.kubernetes.io/auth-type"
+	AuthSecret                   Annotation = "nginx.ingress.kubernetes.io/auth-secret" //nolint:gosec
+	AuthRealm                    Annotation = "nginx.ingress.kubernetes.io/auth-realm"
+	AuthTLSVerifyClient          Annotation = "nginx.ingress.kubernetes.io/auth-tls-verify-client"
+	AuthTLSSecret                Annotation = "nginx.ingress.kubernetes.io/auth-tls-secret" //nolint:gosec
+	AuthTLSVerifyDepth           Annotation = "nginx.ingress.kubernetes.io/auth-tls-verify-depth"
+	AuthTLSErrorPage             Annotation = "nginx.ingress.kubernetes.io/auth-tls-error-page"
+	AuthTLSPassCertToUpstream    Annotation = "nginx.ingress.kubernetes.io/auth-tls-pass-certificate-to-upstream"
+	AuthTLSMatchCN               Annotation = "nginx.ingress.kubernetes.io/auth-tls-match-cn"
+	SSLPassthrough               Annotation = "nginx.ingress.kubernetes.io/ssl-passthrough"
+	AuthURL                      Annotation = "nginx.ingress.kubernetes.io/auth-url"
+	AuthResponseHeaders          Annotation = "nginx.ingress.kubernetes.io/auth-response-headers"
+	AuthSignin                   Annotation = "nginx.ingress.kubernetes.io/auth-signin"
+	AuthSigninRedirectParam      Annotation = "nginx.ingress.kubernetes.io/auth-signin-redirect-param"
+	AuthRequestRedirect          Annotation = "nginx.ingress.kubernetes.io/auth-request-redirect"
+	AuthMethod                   Annotation = "nginx.ingress.kubernetes.io/auth-method"
+	AuthSnippet                  Annotation = "nginx.ingress.kubernetes.io/auth-snippet"
+	AuthCacheKey                 Annotation = "nginx.ingress.kubernetes.io/auth-cache-key"
+	AuthCacheDuration            Annotation = "nginx.ingress.kubernetes.io/auth-cache-duration"
+	AuthProxySetHeaders          Annotation = "nginx.ingress.kubernetes.io/auth-proxy-set-headers"
+	ProxyBodySize                Annotation = "nginx.ingress.kubernetes.io/proxy-body-size"
+	ConfigurationSnippet         Annotation = "nginx.ingress.kubernetes.io/configuration-snippet"
+	EnableCORS                   Annotation = "nginx.ingress.kubernetes.io/enable-cors"
+	CorsAllowOrigin              Annotation = "nginx.ingress.kubernetes.io/cors-allow-origin"
+	CorsAllowMethods             Annotation = "nginx.ingress.kubernetes.io/cors-allow-methods"
+	CorsAllowHeaders             Annotation = "nginx.ingress.kubernetes.io/cors-allow-headers"
+	CorsAllowCredentials         Annotation = "nginx.ingress.kubernetes.io/cors-allow-credentials" //nolint:gosec
+	CorsMaxAge                   Annotation = "nginx.ingress.kubernetes.io/cors-max-age"
+	CorsExposeHeaders            Annotation = "nginx.ingress.kubernetes.io/cors-expose-headers"
+	ProxyBuffering               Annotation = "nginx.ingress.kubernetes.io/proxy-buffering"
+	ServiceUpstream              Annotation = "nginx.ingress.kubernetes.io/service-upstream"
+	EnableOpentracing            Annotation = "nginx.ingress.kubernetes.io/enable-opentracing"
+	EnableOpentelemetry          Annotation = "nginx.ingress.kubernetes.io/enable-opentelemetry"
+	BackendProtocol              Annotation = "nginx.ingress.kubernetes.io/backend-protocol"
+	GrpcBackend                  Annotation = "nginx.ingress.kubernetes.io/grpc-backend"
+	ProxyBufferSize              Annotation = "nginx.ingress.kubernetes.io/proxy-buffer-size"
+	LimitRPS                     Annotation = "nginx.ingress.kubernetes.io/limit-rps"
+	LimitBurstMultiplier         Annotation = "nginx.ingress.kubernetes.io/limit-burst-multiplier"
+	LimitRPM                     Annotation = "nginx.ingress.kubernetes.io/limit-rpm"
+	LimitConnections             Annotation = "nginx.ingress.kubernetes.io/limit-connections"
+	LimitWhitelist               Annotation = "nginx.ingress.kubernetes.io/limit-whitelist"
+	LimitRate                    Annotation = "nginx.ingress.kubernetes.io/limit-rate"
+	LimitRateAfter               Annotation = "nginx.ingress.kubernetes.io/limit-rate-after"
+	RewriteTarget                Annotation = "nginx.ingress.kubernetes.io/rewrite-target"
+	SSLRedirect                  Annotation = "nginx.ingress.kubernetes.io/ssl-redirect"
+	ForceSSLRedirect             Annotation = "nginx.ingress.kubernetes.io/force-ssl-redirect"
+	UpstreamVhost                Annotation = "nginx.ingress.kubernetes.io/upstream-vhost"
+	ProxyRedirectFrom            Annotation = "nginx.ingress.kubernetes.io/proxy-redirect-from"
+	ProxyRedirectTo              Annotation = "nginx.ingress.kubernetes.io/proxy-redirect-to"
+	ProxyCookiePath              Annotation = "nginx.ingress.kubernetes.io/proxy-cookie-path"
+	ServerSnippet                Annotation = "nginx.ingress.kubernetes.io/server-snippet"
+	UnderscoresInHeaders         Annotation = "nginx.ingress.kubernetes.io/enable-underscores-in-headers"
+	UseRegex                     Annotation = "nginx.ingress.kubernetes.io/use-regex"
+	ClientHeaderBufferSize       Annotation = "nginx.ingress.kubernetes.io/client-header-buffer-size"
+	LargeClientHeaderBuffers     Annotation = "nginx.ingress.kubernetes.io/large-client-header-buffers"
+	WhitelistSourceRange         Annotation = "nginx.ingress.kubernetes.io/whitelist-source-range"
+	AllowlistSourceRange         Annotation = "nginx.ingress.kubernetes.io/allowlist-source-range"
+	DenylistSourceRange          Annotation = "nginx.ingress.kubernetes.io/denylist-source-range"
+	Canary                       Annotation = "nginx.ingress.kubernetes.io/canary"
+	CanaryWeight                 Annotation = "nginx.ingress.kubernetes.io/canary-weight"
+	CanaryWeightTotal            Annotation = "nginx.ingress.kubernetes.io/canary-weight-total"
+	CanaryByHeader               Annotation = "nginx.ingress.kubernetes.io/canary-by-header"
+	CanaryByHeaderValue          Annotation = "nginx.ingress.kubernetes.io/canary-by-header-value"
+	CanaryByHeaderPattern        Annotation = "nginx.ingress.kubernetes.io/canary-by-header-pattern"
+	CanaryByCookie               Annotation = "nginx.ingress.kubernetes.io/canary-by-cookie"
+	Affinity                     Annotation = "nginx.ingress.kubernetes.io/affinity"
+	AffinityMode                 Annotation = "nginx.ingress.kubernetes.io/affinity-mode"
+	SessionCookieName            Annotation = "nginx.ingress.kubernetes.io/session-cookie-name"
+	SessionCookiePath            Annotation = "nginx.ingress.kubernetes.io/session-cookie-path"
+	SessionCookieSameSite        Annotation = "nginx.ingress.kubernetes.io/session-cookie-samesite"
+	SessionCookieSecure          Annotation = "nginx.ingress.kubernetes.io/session-cookie-secure"
+	SessionCookieMaxAge          Annotation = "nginx.ingress.kubernetes.io/session-cookie-max-age"
+	SessionCookieExpires         Annotation = "nginx.ingress.kubernetes.io/session-cookie-expires"
+	SessionCookieChangeOnFailure Annotation = "nginx.ingress.kubernetes.io/session-cookie-change-on-failure"
+	ProxyConnectTimeout          Annotation = "nginx.ingress.kubernetes.io/proxy-connect-timeout"
+	ProxyReadTimeout             Annotation = "nginx.ingress.kubernetes.io/proxy-read-timeout"
+	ProxySendTimeout             Annotation = "nginx.ingress.kubernetes.io/proxy-send-timeout"
+	ProxyHTTPVersion             Annotation = "nginx.ingress.kubernetes.io/proxy-http-version"
+	UpstreamKeepaliveConnections Annotation = "nginx.ingress.kubernetes.io/upstream-keepalive-connections"
+	UpstreamKeepaliveTimeout     Annotation = "nginx.ingress.kubernetes.io/upstream-keepalive-timeout"
+	UpstreamKeepaliveRequests    Annotation = "nginx.ingress.kubernetes.io/upstream-keepalive-requests"
+	UpstreamKeepaliveTime        Annotation = "nginx.ingress.kubernetes.io/upstream-keepalive-time"
+	ProxySSLVerify               Annotation = "nginx.ingress.kubernetes.io/proxy-ssl-verify"
+	ProxySSLSecret               Annotation = "nginx.ingress.kubernetes.io/proxy-ssl-secret" //nolint:gosec
+	ProxySSLName                 Annotation = "nginx.ingress.kubernetes.io/proxy-ssl-name"
+	ProxySSLServerName           Annotation = "nginx.ingress.kubernetes.io/proxy-ssl-server-name"
+	ProxySSLVerifyDepth          Annotation = "nginx.ingress.kubernetes.io/proxy-ssl-verify-depth"
+	CustomHTTPErrors             Annotation = "nginx.ingress.kubernetes.io/custom-http-errors"
+	DefaultBackend               Annotation = "nginx.ingress.kubernetes.io/default-backend"
+	PermanentRedirect            Annotation = "nginx.ingress.kubernetes.io/permanent-redirect"
+	PermanentRedirectCode        Annotation = "nginx.ingress.kubernetes.io/permanent-redirect-code"
+	TemporalRedirect             Annotation = "nginx.ingress.kubernetes.io/temporal-redirect"
+	TemporalRedirectCode         Annotation = "nginx.ingress.kubernetes.io/temporal-redirect-code"
+	AppRoot                      Annotation = "nginx.ingress.kubernetes.io/app-root"
+	FromToWWWRedirect            Annotation = "nginx.ingress.kubernetes.io/from-to-www-redirect"
+	EnableGlobalAuth             Annotation = "nginx.ingress.kubernetes.io/enable-global-auth"
 )
 
 var AllAnnotations = []Annotation{
@@ -47,7 +110,21 @@ var AllAnnotations = []Annotation{
 	AuthRealm,
 	AuthTLSVerifyClient,
 	AuthTLSSecret,
+	AuthTLSVerifyDepth,
+	AuthTLSErrorPage,
+	AuthTLSPassCertToUpstream,
+	AuthTLSMatchCN,
+	SSLPassthrough,
 	AuthURL,
+	AuthResponseHeaders,
+	AuthSignin,
+	AuthSigninRedirectParam,
+	AuthRequestRedirect,
+	AuthMethod,
+	AuthSnippet,
+	AuthCacheKey,
+	AuthCacheDuration,
+	AuthProxySetHeaders,
 	ProxyBodySize,
 	ConfigurationSnippet,
 	EnableCORS,
@@ -66,6 +143,11 @@ var AllAnnotations = []Annotation{
 	ProxyBufferSize,
 	LimitRPS,
 	LimitBurstMultiplier,
+	LimitRPM,
+	LimitConnections,
+	LimitWhitelist,
+	LimitRate,
+	LimitRateAfter,
 	RewriteTarget,
 	SSLRedirect,
 	ForceSSLRedirect,
@@ -78,6 +160,47 @@ var AllAnnotations = []Annotation{
 	UseRegex,
 	ClientHeaderBufferSize,
 	LargeClientHeaderBuffers,
+	WhitelistSourceRange,
+	AllowlistSourceRange,
+	DenylistSourceRange,
+	Canary,
+	CanaryWeight,
+	CanaryWeightTotal,
+	CanaryByHeader,
+	CanaryByHeaderValue,
+	CanaryByHeaderPattern,
+	CanaryByCookie,
+	Affinity,
+	AffinityMode,
+	SessionCookieName,
+	SessionCookiePath,
+	SessionCookieSameSite,
+	SessionCookieSecure,
+	SessionCookieMaxAge,
+	SessionCookieExpires,
+	SessionCookieChangeOnFailure,
+	ProxyConnectTimeout,
+	ProxyReadTimeout,
+	ProxySendTimeout,
+	ProxyHTTPVersion,
+	UpstreamKeepaliveConnections,
+	UpstreamKeepaliveTimeout,
+	UpstreamKeepaliveRequests,
+	UpstreamKeepaliveTime,
+	ProxySSLVerify,
+	ProxySSLSecret,
+	ProxySSLName,
+	ProxySSLServerName,
+	ProxySSLVerifyDepth,
+	CustomHTTPErrors,
+	DefaultBackend,
+	PermanentRedirect,
+	PermanentRedirectCode,
+	TemporalRedirect,
+	TemporalRedirectCode,
+	AppRoot,
+	FromToWWWRedirect,
+	EnableGlobalAuth,
 }
 
 func (a Annotation) String() string {
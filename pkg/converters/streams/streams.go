@@ -0,0 +1,252 @@
+// Package streams converts the tcp-services and udp-services ConfigMaps of the ingress-nginx controller,
+// which expose raw TCP and UDP ports, into Traefik IngressRouteTCP and IngressRouteUDP resources.
+package streams
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/nikhilsbhat/nginx-traefik-converter/pkg/configs"
+	"github.com/nikhilsbhat/nginx-traefik-converter/pkg/errors"
+	"github.com/traefik/traefik/v3/pkg/config/dynamic"
+	traefik "github.com/traefik/traefik/v3/pkg/provider/kubernetes/crd/traefikio/v1alpha1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+)
+
+const (
+	// ProtocolTCP selects the tcp-services ConfigMap format.
+	ProtocolTCP = "tcp"
+	// ProtocolUDP selects the udp-services ConfigMap format.
+	ProtocolUDP = "udp"
+
+	proxyProtocolFlag = "PROXY"
+	// nginx sends version 1 of the PROXY protocol to the upstreams.
+	proxyProtocolVersion = 1
+	maxPort              = 65535
+)
+
+// stream is a single entry of a tcp-services or udp-services ConfigMap.
+type stream struct {
+	port        int
+	namespace   string
+	service     string
+	servicePort intstr.IntOrString
+	// decodeProxy accepts the PROXY protocol from the clients.
+	decodeProxy bool
+	// encodeProxy sends the PROXY protocol to the upstream.
+	encodeProxy bool
+}
+
+// Convert converts the entries of the tcp-services or udp-services ConfigMap, each in the form
+// "<port>": "<namespace>/<service>:<service port>[:PROXY][:PROXY]", into routes on dedicated entry points.
+// The report of the ConfigMap lists one entry per port.
+func Convert(ctx configs.Context, configMap *corev1.ConfigMap, protocol string) {
+	ctx.Log.Debug("running converter Streams")
+
+	if ctx.Result.EntryPoints == nil {
+		ctx.Result.EntryPoints = make(map[string]*configs.EntryPoint)
+	}
+
+	if protocol == ProtocolUDP {
+		ctx.ReportRouteMode(configs.RouteModeIngressRouteUDP)
+	} else {
+		ctx.ReportRouteMode(configs.RouteModeIngressRouteTCP)
+	}
+
+	for _, key := range slices.Sorted(maps.Keys(configMap.Data)) {
+		name := key + "/" + protocol
+
+		entry, err := parseStream(key, configMap.Data[key])
+		if err != nil {
+			ctx.Result.Warnings = append(ctx.Result.Warnings, err.Error())
+			ctx.ReportSkipped(name, err.Error())
+
+			continue
+		}
+
+		entryPoint := fmt.Sprintf("%s-%d", protocol, entry.port)
+
+		ctx.Result.EntryPoints[entryPoint] = &configs.EntryPoint{
+			Address: fmt.Sprintf(":%d/%s", entry.port, protocol),
+		}
+
+		if protocol == ProtocolUDP {
+			convertUDP(ctx, entry, entryPoint, name)
+
+			continue
+		}
+
+		convertTCP(ctx, entry, entryPoint, name)
+	}
+
+	if len(ctx.Result.EntryPoints) > 0 {
+		ctx.Result.Warnings = append(ctx.Result.Warnings,
+			fmt.Sprintf("declare the %s entry points in the Traefik static configuration and expose their ports "+
+				"on the Traefik Service, see traefik-static.yaml", strings.ToUpper(protocol)),
+		)
+	}
+}
+
+func convertTCP(ctx configs.Context, entry *stream, entryPoint, name string) {
+	service := traefik.ServiceTCP{
+		Name: entry.service,
+		Port: entry.servicePort,
+	}
+
+	if entry.decodeProxy {
+		// ingress-nginx accepts the PROXY protocol from any client on these ports.
+		ctx.Result.EntryPoints[entryPoint].ProxyProtocol = &configs.EntryPointProxyProtocol{
+			Insecure: true,
+		}
+
+		ctx.Result.Warnings = append(ctx.Result.Warnings,
+			fmt.Sprintf("entry point %s accepts the PROXY protocol from any client as ingress-nginx does; "+
+				"restrict it with proxyProtocol.trustedIPs to the addresses of the load balancer", entryPoint),
+		)
+	}
+
+	if entry.encodeProxy {
+		transport := emitProxyProtocolTransport(ctx, entry, entryPoint)
+		service.ServersTransport = transport
+	}
+
+	ctx.Result.IngressRoutesTCP = append(ctx.Result.IngressRoutesTCP, &traefik.IngressRouteTCP{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: traefik.SchemeGroupVersion.String(),
+			Kind:       "IngressRouteTCP",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      entryPoint,
+			Namespace: entry.namespace,
+		},
+		Spec: traefik.IngressRouteTCPSpec{
+			EntryPoints: []string{entryPoint},
+			Routes: []traefik.RouteTCP{
+				{
+					// Without TLS the SNI is unknown, the route takes all the connections of the entry point.
+					Match:    "HostSNI(`*`)",
+					Services: []traefik.ServiceTCP{service},
+				},
+			},
+		},
+	})
+
+	ctx.ReportConverted(name)
+}
+
+func convertUDP(ctx configs.Context, entry *stream, entryPoint, name string) {
+	ctx.Result.IngressRoutesUDP = append(ctx.Result.IngressRoutesUDP, &traefik.IngressRouteUDP{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: traefik.SchemeGroupVersion.String(),
+			Kind:       "IngressRouteUDP",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      entryPoint,
+			Namespace: entry.namespace,
+		},
+		Spec: traefik.IngressRouteUDPSpec{
+			EntryPoints: []string{entryPoint},
+			Routes: []traefik.RouteUDP{
+				{
+					Services: []traefik.ServiceUDP{
+						{
+							Name: entry.service,
+							Port: entry.servicePort,
+						},
+					},
+				},
+			},
+		},
+	})
+
+	if entry.decodeProxy || entry.encodeProxy {
+		msg := "the PROXY protocol is not supported for UDP, the flags were dropped"
+
+		ctx.Result.Warnings = append(ctx.Result.Warnings, name+": "+msg)
+		ctx.ReportWarning(name, msg)
+
+		return
+	}
+
+	ctx.ReportConverted(name)
+}
+
+// emitProxyProtocolTransport generates the ServersTransportTCP sending the PROXY protocol to the upstream
+// and returns its name.
+func emitProxyProtocolTransport(ctx configs.Context, entry *stream, entryPoint string) string {
+	name := entryPoint + "-proxy-protocol"
+
+	ctx.Result.ServersTransportsTCP = append(ctx.Result.ServersTransportsTCP, &traefik.ServersTransportTCP{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: traefik.SchemeGroupVersion.String(),
+			Kind:       "ServersTransportTCP",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: entry.namespace,
+		},
+		Spec: traefik.ServersTransportTCPSpec{
+			ProxyProtocol: &dynamic.ProxyProtocol{
+				Version: proxyProtocolVersion,
+			},
+		},
+	})
+
+	return name
+}
+
+// parseStream parses an entry of the ConfigMap, "<namespace>/<service>:<service port>[:PROXY][:PROXY]".
+// The first PROXY flag decodes the PROXY protocol from the clients, the second one sends it to the upstream.
+func parseStream(key, value string) (*stream, error) {
+	port, err := strconv.Atoi(strings.TrimSpace(key))
+	if err != nil || port <= 0 || port > maxPort {
+		return nil, &errors.ConverterError{Message: fmt.Sprintf("invalid port %q", key)}
+	}
+
+	parts := strings.Split(strings.TrimSpace(value), ":")
+
+	const minParts, maxParts = 2, 4
+
+	if len(parts) < minParts || len(parts) > maxParts {
+		return nil, &errors.ConverterError{
+			Message: fmt.Sprintf("invalid entry %q for port %d, expected <namespace>/<service>:<port>[:PROXY][:PROXY]", value, port),
+		}
+	}
+
+	namespace, service, found := strings.Cut(parts[0], "/")
+	if !found || namespace == "" || service == "" {
+		return nil, &errors.ConverterError{
+			Message: fmt.Sprintf("invalid service %q for port %d, expected <namespace>/<service>", parts[0], port),
+		}
+	}
+
+	entry := &stream{
+		port:        port,
+		namespace:   namespace,
+		service:     service,
+		servicePort: intstr.Parse(parts[1]),
+	}
+
+	for index, flag := range parts[minParts:] {
+		if flag != proxyProtocolFlag {
+			if flag == "" {
+				continue
+			}
+
+			return nil, &errors.ConverterError{Message: fmt.Sprintf("invalid flag %q for port %d", flag, port)}
+		}
+
+		if index == 0 {
+			entry.decodeProxy = true
+		} else {
+			entry.encodeProxy = true
+		}
+	}
+
+	return entry, nil
+}
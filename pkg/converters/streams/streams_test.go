@@ -0,0 +1,141 @@
+package streams_test
+
+import (
+	"io"
+	"log/slog"
+	"slices"
+	"testing"
+
+	"github.com/nikhilsbhat/nginx-traefik-converter/pkg/configs"
+	"github.com/nikhilsbhat/nginx-traefik-converter/pkg/converters/streams"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// convert converts the ConfigMap data as the convert command does with --tcp-services-configmap and --udp-services-configmap.
+func convert(data map[string]string, protocol string) *configs.Context {
+	configMap := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Name: protocol + "-services", Namespace: "ingress-nginx"},
+		Data:       data,
+	}
+
+	ctx := &configs.Context{
+		IngressName: configMap.Name,
+		Namespace:   configMap.Namespace,
+		Result:      configs.NewResult(),
+		Options:     configs.NewOptions(),
+		Log:         slog.New(slog.NewTextHandler(io.Discard, nil)),
+	}
+	ctx.StartIngressReport(configMap.Namespace, configMap.Name)
+
+	streams.Convert(*ctx, configMap, protocol)
+
+	return ctx
+}
+
+func status(ctx *configs.Context, name string) configs.AnnotationStatus {
+	for _, entry := range ctx.Result.IngressReport.Entries {
+		if entry.Name == name {
+			return entry.Status
+		}
+	}
+
+	return ""
+}
+
+func TestConvertTCP(t *testing.T) {
+	ctx := convert(map[string]string{
+		"5432": "databases/postgres:5432",
+		"6379": "cache/redis:redis:PROXY:PROXY",
+		"abc":  "default/app:80",
+		"9000": "default-app:80",
+	}, streams.ProtocolTCP)
+
+	res := ctx.Result
+
+	if len(res.IngressRoutesTCP) != 2 {
+		t.Fatalf("IngressRoutesTCP = %d, want 2", len(res.IngressRoutesTCP))
+	}
+
+	postgres := res.IngressRoutesTCP[0]
+	if postgres.Name != "tcp-5432" || postgres.Namespace != "databases" ||
+		!slices.Equal(postgres.Spec.EntryPoints, []string{"tcp-5432"}) {
+		t.Errorf("IngressRouteTCP = %s/%s on %v", postgres.Namespace, postgres.Name, postgres.Spec.EntryPoints)
+	}
+
+	route := postgres.Spec.Routes[0]
+	if route.Match != "HostSNI(`*`)" || route.Services[0].Name != "postgres" || route.Services[0].Port.IntValue() != 5432 {
+		t.Errorf("route = %+v", route)
+	}
+
+	redis := res.IngressRoutesTCP[1].Spec.Routes[0].Services[0]
+	if redis.Port.String() != "redis" || redis.ServersTransport != "tcp-6379-proxy-protocol" {
+		t.Errorf("redis service = %+v", redis)
+	}
+
+	if len(res.ServersTransportsTCP) != 1 || res.ServersTransportsTCP[0].Spec.ProxyProtocol.Version != 1 {
+		t.Errorf("ServersTransportsTCP = %+v, want one sending the PROXY protocol v1", res.ServersTransportsTCP)
+	}
+
+	if entryPoint := res.EntryPoints["tcp-6379"]; entryPoint == nil || entryPoint.Address != ":6379/tcp" ||
+		entryPoint.ProxyProtocol == nil || !entryPoint.ProxyProtocol.Insecure {
+		t.Errorf("entry point tcp-6379 = %+v, want :6379/tcp accepting the PROXY protocol", entryPoint)
+	}
+
+	if entryPoint := res.EntryPoints["tcp-5432"]; entryPoint == nil || entryPoint.ProxyProtocol != nil {
+		t.Errorf("entry point tcp-5432 = %+v, want no PROXY protocol", entryPoint)
+	}
+
+	want := map[string]configs.AnnotationStatus{
+		"5432/tcp": configs.AnnotationConverted,
+		"6379/tcp": configs.AnnotationConverted,
+		"abc/tcp":  configs.AnnotationSkipped,
+		"9000/tcp": configs.AnnotationSkipped,
+	}
+
+	for name, wantStatus := range want {
+		if got := status(ctx, name); got != wantStatus {
+			t.Errorf("%s status = %q, want %q", name, got, wantStatus)
+		}
+	}
+
+	if res.IngressReport.RouteMode != configs.RouteModeIngressRouteTCP {
+		t.Errorf("route mode = %q, want ingressroutetcp", res.IngressReport.RouteMode)
+	}
+}
+
+func TestConvertUDP(t *testing.T) {
+	ctx := convert(map[string]string{
+		"53":  "kube-system/dns:53",
+		"514": "logging/syslog:514:PROXY",
+	}, streams.ProtocolUDP)
+
+	res := ctx.Result
+
+	if len(res.IngressRoutesUDP) != 2 {
+		t.Fatalf("IngressRoutesUDP = %d, want 2", len(res.IngressRoutesUDP))
+	}
+
+	// the ports are converted in the order of the ConfigMap keys
+	dns := res.IngressRoutesUDP[1]
+	if dns.Name != "udp-53" || dns.Namespace != "kube-system" || dns.Spec.Routes[0].Services[0].Name != "dns" {
+		t.Errorf("IngressRouteUDP = %+v", dns)
+	}
+
+	if entryPoint := res.EntryPoints["udp-53"]; entryPoint == nil || entryPoint.Address != ":53/udp" {
+		t.Errorf("entry point udp-53 = %+v, want :53/udp", entryPoint)
+	}
+
+	if got := status(ctx, "53/udp"); got != configs.AnnotationConverted {
+		t.Errorf("53/udp status = %q, want converted", got)
+	}
+
+	// the PROXY protocol is not supported for UDP
+	if got := status(ctx, "514/udp"); got != configs.AnnotationWarned {
+		t.Errorf("514/udp status = %q, want warning", got)
+	}
+
+	if ctx.Result.IngressReport.RouteMode != configs.RouteModeIngressRouteUDP {
+		t.Errorf("route mode = %q, want %s", ctx.Result.IngressReport.RouteMode, configs.RouteModeIngressRouteUDP)
+	}
+}
@@ -9,9 +9,19 @@ import (
 // Annotations:
 //   - "nginx.ingress.kubernetes.io/auth-tls-verify-client"
 //   - "nginx.ingress.kubernetes.io/auth-tls-secret"
+//   - "nginx.ingress.kubernetes.io/auth-tls-verify-depth"
+//   - "nginx.ingress.kubernetes.io/auth-tls-error-page"
+//   - "nginx.ingress.kubernetes.io/auth-tls-pass-certificate-to-upstream"
+//   - "nginx.ingress.kubernetes.io/auth-tls-match-cn"
 func HandleAuthTLSVerifyClient(ctx configs.Context) {
 	verify := ctx.Annotations[string(models.AuthTLSVerifyClient)]
 	if verify == "" || verify == "off" || verify == "false" {
+		for _, ann := range clientCertAnnotations {
+			if _, ok := ctx.Annotations[string(ann)]; ok {
+				ctx.ReportIgnored(string(ann), "client certificates are not requested, auth-tls-verify-client is not enabled")
+			}
+		}
+
 		return
 	}
 
@@ -48,4 +58,10 @@ func HandleAuthTLSVerifyClient(ctx configs.Context) {
 	}
 
 	emitTLSOption(ctx, secret, clientAuthType)
+
+	ctx.ReportConverted(string(models.AuthTLSVerifyClient))
+	ctx.ReportConverted(string(models.AuthTLSSecret))
+
+	passCertificateToUpstream(ctx)
+	reportClientCertChecks(ctx)
 }
@@ -0,0 +1,176 @@
+package tls_test
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/nikhilsbhat/nginx-traefik-converter/pkg/configs"
+	"github.com/nikhilsbhat/nginx-traefik-converter/pkg/converters/tls"
+	traefik "github.com/traefik/traefik/v3/pkg/provider/kubernetes/crd/traefikio/v1alpha1"
+)
+
+func TestHandleAuthTLSVerifyClient(t *testing.T) {
+	tests := []struct {
+		name           string
+		annotations    map[string]string
+		tlsOption      string
+		clientAuthType string
+		secretNames    []string
+	}{
+		{
+			name:           "required client certificates",
+			annotations:    map[string]string{"auth-tls-verify-client": "on", "auth-tls-secret": "default/client-ca"},
+			tlsOption:      "mtls-default-client-ca",
+			clientAuthType: "RequireAndVerifyClientCert",
+			secretNames:    []string{"client-ca"},
+		},
+		{
+			name:           "optional client certificates with a secret of the ingress namespace",
+			annotations:    map[string]string{"auth-tls-verify-client": "optional", "auth-tls-secret": "client-ca"},
+			tlsOption:      "mtls-default-client-ca-optional",
+			clientAuthType: "VerifyClientCertIfGiven",
+			secretNames:    []string{"client-ca"},
+		},
+		{
+			name:           "CA secret of another namespace",
+			annotations:    map[string]string{"auth-tls-verify-client": "on", "auth-tls-secret": "security/client-ca"},
+			tlsOption:      "mtls-security-client-ca",
+			clientAuthType: "RequireAndVerifyClientCert",
+			secretNames:    []string{"client-ca"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := newContext(tt.annotations, nil)
+
+			tls.HandleAuthTLSVerifyClient(ctx)
+
+			if len(ctx.Result.TLSOptions) != 1 {
+				t.Fatalf("TLSOptions = %d, want 1", len(ctx.Result.TLSOptions))
+			}
+
+			option := ctx.Result.TLSOptions[0]
+
+			if option.Name != tt.tlsOption || option.Namespace != "default" {
+				t.Errorf("TLSOption = %s/%s, want default/%s", option.Namespace, option.Name, tt.tlsOption)
+			}
+
+			if option.Spec.ClientAuth.ClientAuthType != tt.clientAuthType || !slices.Equal(option.Spec.ClientAuth.SecretNames, tt.secretNames) {
+				t.Errorf("clientAuth = %+v", option.Spec.ClientAuth)
+			}
+
+			if ref := ctx.Result.TLSOptionRefs["app"]; ref != tt.tlsOption {
+				t.Errorf("TLSOption reference = %q, want %q", ref, tt.tlsOption)
+			}
+
+			for _, annotation := range []string{"auth-tls-verify-client", "auth-tls-secret"} {
+				if got := statuses(ctx, annotation); !slices.Equal(got, []configs.AnnotationStatus{configs.AnnotationConverted}) {
+					t.Errorf("%s statuses = %v, want [converted]", annotation, got)
+				}
+			}
+		})
+	}
+}
+
+func TestHandleAuthTLSVerifyClientChecks(t *testing.T) {
+	ctx := newContext(map[string]string{
+		"auth-tls-verify-client":                "on",
+		"auth-tls-secret":                       "default/client-ca",
+		"auth-tls-pass-certificate-to-upstream": "true",
+		"auth-tls-verify-depth":                 "2",
+		"auth-tls-error-page":                   "https://example.com/error",
+		"auth-tls-match-cn":                     "CN=client",
+	}, nil)
+
+	tls.HandleAuthTLSVerifyClient(ctx)
+
+	if len(ctx.Result.Middlewares) != 2 || ctx.Result.Middlewares[0].Name != "app-pass-client-cert" {
+		t.Fatalf("middlewares = %v, want app-pass-client-cert and app-auth-tls-match-cn", ctx.Result.Middlewares)
+	}
+
+	// auth-tls-match-cn fails closed
+	if allowList := ctx.Result.Middlewares[1].Spec.IPAllowList; allowList == nil ||
+		!slices.Equal(allowList.SourceRange, []string{"0.0.0.0/32"}) {
+		t.Errorf("ipAllowList = %+v, want no client admitted", allowList)
+	}
+
+	if passCert := ctx.Result.Middlewares[0].Spec.PassTLSClientCert; passCert == nil || !passCert.PEM {
+		t.Errorf("passTLSClientCert = %+v, want the PEM certificate passed", passCert)
+	}
+
+	want := map[string]configs.AnnotationStatus{
+		"auth-tls-pass-certificate-to-upstream": configs.AnnotationConverted,
+		"auth-tls-verify-depth":                 configs.AnnotationWarned,
+		"auth-tls-error-page":                   configs.AnnotationSkipped,
+		"auth-tls-match-cn":                     configs.AnnotationWarned,
+	}
+
+	for annotation, status := range want {
+		if got := statuses(ctx, annotation); !slices.Equal(got, []configs.AnnotationStatus{status}) {
+			t.Errorf("%s statuses = %v, want [%s]", annotation, got, status)
+		}
+	}
+}
+
+func TestHandleAuthTLSVerifyClientNotConverted(t *testing.T) {
+	tests := []struct {
+		name        string
+		annotations map[string]string
+		statuses    map[string]configs.AnnotationStatus
+	}{
+		{
+			name:        "verification disabled",
+			annotations: map[string]string{"auth-tls-verify-client": "off", "auth-tls-secret": "default/client-ca"},
+			statuses:    map[string]configs.AnnotationStatus{"auth-tls-secret": configs.AnnotationIgnored},
+		},
+		{
+			name:        "missing CA secret",
+			annotations: map[string]string{"auth-tls-verify-client": "on"},
+			statuses:    map[string]configs.AnnotationStatus{"auth-tls-verify-client": configs.AnnotationSkipped},
+		},
+		{
+			name:        "optional without CA",
+			annotations: map[string]string{"auth-tls-verify-client": "optional_no_ca", "auth-tls-secret": "default/client-ca"},
+			statuses:    map[string]configs.AnnotationStatus{"auth-tls-verify-client": configs.AnnotationSkipped},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := newContext(tt.annotations, nil)
+
+			tls.HandleAuthTLSVerifyClient(ctx)
+
+			if len(ctx.Result.TLSOptions) != 0 {
+				t.Errorf("no TLSOption should be generated, got %d", len(ctx.Result.TLSOptions))
+			}
+
+			for annotation, status := range tt.statuses {
+				if got := statuses(ctx, annotation); !slices.Equal(got, []configs.AnnotationStatus{status}) {
+					t.Errorf("%s statuses = %v, want [%s]", annotation, got, status)
+				}
+			}
+		})
+	}
+}
+
+func TestApplyTLSOption(t *testing.T) {
+	ctx := newContext(map[string]string{"auth-tls-verify-client": "on", "auth-tls-secret": "default/client-ca"}, nil)
+
+	tls.HandleAuthTLSVerifyClient(ctx)
+
+	withTLS := &traefik.IngressRoute{Spec: traefik.IngressRouteSpec{TLS: &traefik.TLS{SecretName: "app-tls"}}}
+	withoutTLS := &traefik.IngressRoute{}
+
+	tls.ApplyTLSOption(withTLS, ctx)
+	tls.ApplyTLSOption(withoutTLS, ctx)
+
+	if withTLS.Spec.TLS.Options == nil || withTLS.Spec.TLS.Options.Name != "mtls-default-client-ca" {
+		t.Errorf("tls options = %+v, want mtls-default-client-ca", withTLS.Spec.TLS.Options)
+	}
+
+	if withoutTLS.Spec.TLS != nil {
+		t.Error("routes without TLS must be left untouched")
+	}
+}
@@ -0,0 +1,133 @@
+package tls
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/nikhilsbhat/nginx-traefik-converter/pkg/configs"
+	"github.com/nikhilsbhat/nginx-traefik-converter/pkg/converters/models"
+	"github.com/traefik/traefik/v3/pkg/config/dynamic"
+	traefik "github.com/traefik/traefik/v3/pkg/provider/kubernetes/crd/traefikio/v1alpha1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// clientCertAnnotations are the annotations only meaningful when client certificates are verified.
+var clientCertAnnotations = []models.Annotation{
+	models.AuthTLSSecret,
+	models.AuthTLSVerifyDepth,
+	models.AuthTLSErrorPage,
+	models.AuthTLSPassCertToUpstream,
+	models.AuthTLSMatchCN,
+}
+
+// passCertificateToUpstream forwards the verified client certificate to the upstream through a PassTLSClientCert middleware.
+func passCertificateToUpstream(ctx configs.Context) {
+	ann := string(models.AuthTLSPassCertToUpstream)
+
+	val, ok := ctx.Annotations[ann]
+	if !ok {
+		return
+	}
+
+	if strings.ToLower(strings.TrimSpace(val)) != "true" {
+		ctx.ReportSkipped(ann, ann+" is not set to true")
+
+		return
+	}
+
+	ctx.Result.Middlewares = append(ctx.Result.Middlewares, &traefik.Middleware{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: traefik.SchemeGroupVersion.String(),
+			Kind:       "Middleware",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      ctx.IngressName + "-pass-client-cert",
+			Namespace: ctx.Namespace,
+		},
+		Spec: traefik.MiddlewareSpec{
+			PassTLSClientCert: &dynamic.PassTLSClientCert{
+				PEM: true,
+				// The subject and issuer replace the ssl-client-subject-dn and ssl-client-issuer-dn headers of ingress-nginx.
+				Info: &dynamic.TLSClientCertificateInfo{
+					Subject: &dynamic.TLSClientCertificateSubjectDNInfo{
+						CommonName:   true,
+						Organization: true,
+						Country:      true,
+					},
+					Issuer: &dynamic.TLSClientCertificateIssuerDNInfo{
+						CommonName:   true,
+						Organization: true,
+						Country:      true,
+					},
+				},
+			},
+		},
+	})
+
+	ctx.Result.Warnings = append(ctx.Result.Warnings,
+		"the client certificate is passed upstream in X-Forwarded-Tls-Client-Cert (base64 DER, URL escaped) and "+
+			"X-Forwarded-Tls-Client-Cert-Info instead of the ssl-client-cert header (URL escaped PEM) of ingress-nginx; "+
+			"update the upstream to read the new headers",
+	)
+
+	ctx.ReportConverted(ann)
+}
+
+// reportClientCertChecks reports the client certificate checks Traefik cannot perform.
+func reportClientCertChecks(ctx configs.Context) {
+	if _, ok := ctx.Annotations[string(models.AuthTLSVerifyDepth)]; ok {
+		msg := "auth-tls-verify-depth has no Traefik equivalent, the whole client certificate chain is verified"
+
+		ctx.Result.Warnings = append(ctx.Result.Warnings, msg)
+		ctx.ReportWarning(string(models.AuthTLSVerifyDepth), msg)
+	}
+
+	if _, ok := ctx.Annotations[string(models.AuthTLSErrorPage)]; ok {
+		msg := "auth-tls-error-page cannot be converted, Traefik rejects invalid client certificates during the TLS handshake " +
+			"before any HTTP response can be sent"
+
+		ctx.Result.Warnings = append(ctx.Result.Warnings, msg)
+		ctx.ReportSkipped(string(models.AuthTLSErrorPage), msg)
+	}
+
+	rejectUnmatchedCN(ctx)
+}
+
+// rejectUnmatchedCN fails closed on auth-tls-match-cn: Traefik can neither route nor filter on the subject of the
+// client certificate, so an IPAllowList admitting no client answers every request with 403, as ingress-nginx does
+// for certificates with another CN, rather than accepting every certificate signed by the CA.
+func rejectUnmatchedCN(ctx configs.Context) {
+	ann := string(models.AuthTLSMatchCN)
+
+	val, ok := ctx.Annotations[ann]
+	if !ok {
+		return
+	}
+
+	name := ctx.IngressName + "-auth-tls-match-cn"
+
+	ctx.Result.Middlewares = append(ctx.Result.Middlewares, &traefik.Middleware{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: traefik.SchemeGroupVersion.String(),
+			Kind:       "Middleware",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: ctx.Namespace,
+		},
+		Spec: traefik.MiddlewareSpec{
+			IPAllowList: &dynamic.IPAllowList{
+				// No client connects from the unspecified address.
+				SourceRange: []string{"0.0.0.0/32"},
+			},
+		},
+	})
+
+	msg := fmt.Sprintf("auth-tls-match-cn %q cannot be enforced, Traefik routes and middlewares cannot match on the client "+
+		"certificate subject; the %s middleware rejects every request with 403 instead of accepting every certificate "+
+		"signed by the CA, remove it once the CN is checked otherwise (a CA dedicated to the allowed clients in "+
+		"auth-tls-secret, or X-Forwarded-Tls-Client-Cert-Info checked upstream)", val, name)
+
+	ctx.Result.Warnings = append(ctx.Result.Warnings, msg)
+	ctx.ReportWarning(ann, msg)
+}
@@ -0,0 +1,108 @@
+package tls_test
+
+import (
+	"io"
+	"log/slog"
+	"strings"
+	"testing"
+
+	"github.com/nikhilsbhat/nginx-traefik-converter/pkg/configs"
+	traefik "github.com/traefik/traefik/v3/pkg/provider/kubernetes/crd/traefikio/v1alpha1"
+	netv1 "k8s.io/api/networking/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const annotationPrefix = "nginx.ingress.kubernetes.io/"
+
+// newContext returns the context of an ingress named app routing app.example.com to the app Service,
+// with the given nginx annotations whose keys are given without the nginx.ingress.kubernetes.io/ prefix.
+func newContext(annotations map[string]string, opts *configs.Options) configs.Context {
+	if opts == nil {
+		opts = configs.NewOptions()
+	}
+
+	prefixed := make(map[string]string, len(annotations))
+	for key, value := range annotations {
+		prefixed[annotationPrefix+key] = value
+	}
+
+	pathType := netv1.PathTypePrefix
+
+	ing := &netv1.Ingress{
+		ObjectMeta: metav1.ObjectMeta{Name: "app", Namespace: "default", Annotations: prefixed},
+		Spec: netv1.IngressSpec{
+			Rules: []netv1.IngressRule{
+				{
+					Host: "app.example.com",
+					IngressRuleValue: netv1.IngressRuleValue{
+						HTTP: &netv1.HTTPIngressRuleValue{
+							Paths: []netv1.HTTPIngressPath{
+								{
+									Path:     "/",
+									PathType: &pathType,
+									Backend: netv1.IngressBackend{
+										Service: &netv1.IngressServiceBackend{
+											Name: "app",
+											Port: netv1.ServiceBackendPort{Number: 80},
+										},
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+
+	ctx := configs.New(ing, configs.NewResult(), opts, slog.New(slog.NewTextHandler(io.Discard, nil)))
+	ctx.StartIngressReport(ing.Namespace, ing.Name)
+
+	return *ctx
+}
+
+// findMiddleware returns the generated middleware with the given name, failing the test when it is missing.
+func findMiddleware(t *testing.T, ctx configs.Context, name string) *traefik.Middleware {
+	t.Helper()
+
+	for _, middleware := range ctx.Result.Middlewares {
+		if middleware.Name == name {
+			return middleware
+		}
+	}
+
+	names := make([]string, 0, len(ctx.Result.Middlewares))
+	for _, middleware := range ctx.Result.Middlewares {
+		names = append(names, middleware.Name)
+	}
+
+	t.Fatalf("middleware %s was not generated, got %v", name, names)
+
+	return nil
+}
+
+// statuses returns the report statuses of the annotation, given without the nginx prefix.
+func statuses(ctx configs.Context, annotation string) []configs.AnnotationStatus {
+	found := make([]configs.AnnotationStatus, 0)
+
+	for _, entry := range ctx.Result.IngressReport.Entries {
+		if entry.Name == annotationPrefix+annotation {
+			found = append(found, entry.Status)
+		}
+	}
+
+	return found
+}
+
+// message returns the report message of the annotation, given without the nginx prefix.
+func message(ctx configs.Context, annotation string) string {
+	messages := make([]string, 0)
+
+	for _, entry := range ctx.Result.IngressReport.Entries {
+		if entry.Name == annotationPrefix+annotation && entry.Message != "" {
+			messages = append(messages, entry.Message)
+		}
+	}
+
+	return strings.Join(messages, "\n")
+}
@@ -1,17 +1,31 @@
 package tls
 
 import (
+	"fmt"
+	"strings"
+
 	"github.com/nikhilsbhat/nginx-traefik-converter/pkg/configs"
 	traefik "github.com/traefik/traefik/v3/pkg/provider/kubernetes/crd/traefikio/v1alpha1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
-func emitTLSOption(ctx configs.Context, secretName, clientAuthType string) {
+// emitTLSOption generates the TLSOption verifying client certificates against the CA secret.
+// The option is named after the namespace and name of the CA secret, so that all ingresses of a namespace
+// trusting the same CA share one TLSOption, as Traefik applies a single TLSOption per host.
+func emitTLSOption(ctx configs.Context, secret, clientAuthType string) {
 	if ctx.Result.TLSOptionRefs == nil {
 		ctx.Result.TLSOptionRefs = make(map[string]string)
 	}
 
-	name := ctx.IngressName + "-mtls"
+	namespace, secretName, found := strings.Cut(secret, "/")
+	if !found {
+		namespace, secretName = ctx.Namespace, secret
+	}
+
+	name := fmt.Sprintf("mtls-%s-%s", namespace, secretName)
+	if clientAuthType != "RequireAndVerifyClientCert" {
+		name += "-optional"
+	}
 
 	tlsOpt := &traefik.TLSOption{
 		TypeMeta: metav1.TypeMeta{
@@ -35,21 +49,27 @@ func emitTLSOption(ctx configs.Context, secretName, clientAuthType string) {
 
 	ctx.Result.Warnings = append(ctx.Result.Warnings,
 		"auth-tls-secret must contain CA certificates only; server cert secrets cannot be reused",
-		"CA certificates must be mounted into Traefik via static configuration",
+		fmt.Sprintf("TLSOption %s is shared by the ingresses of namespace %s trusting the CA in %s", name, ctx.Namespace, secretName),
 	)
+
+	if namespace != ctx.Namespace {
+		ctx.Result.Warnings = append(ctx.Result.Warnings,
+			fmt.Sprintf("auth-tls-secret %s lives in namespace %s, Traefik reads the CA secrets of a TLSOption "+
+				"from its own namespace (%s); copy the secret there", secretName, namespace, ctx.Namespace),
+		)
+	}
 }
 
-// ApplyTLSOption applies TLS configs to ingress routes.
-func ApplyTLSOption(ingressRoute *traefik.IngressRoute, ctx configs.Context, scheme string) {
-	if scheme != "https" {
+// ApplyTLSOption references the TLSOption generated for the ingress from the TLS configs of the ingress route.
+// Ingress routes without TLS are left untouched.
+func ApplyTLSOption(ingressRoute *traefik.IngressRoute, ctx configs.Context) {
+	if ingressRoute.Spec.TLS == nil {
 		return
 	}
 
 	if opt, ok := ctx.Result.TLSOptionRefs[ctx.IngressName]; ok {
-		ingressRoute.Spec.TLS = &traefik.TLS{
-			Options: &traefik.TLSOptionRef{
-				Name: opt,
-			},
+		ingressRoute.Spec.TLS.Options = &traefik.TLSOptionRef{
+			Name: opt,
 		}
 	}
 }
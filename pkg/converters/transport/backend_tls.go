@@ -0,0 +1,158 @@
+package transport
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/nikhilsbhat/nginx-traefik-converter/pkg/configs"
+	"github.com/nikhilsbhat/nginx-traefik-converter/pkg/converters/models"
+	traefik "github.com/traefik/traefik/v3/pkg/provider/kubernetes/crd/traefikio/v1alpha1"
+)
+
+var proxySSLAnnotations = []models.Annotation{
+	models.ProxySSLVerify,
+	models.ProxySSLSecret,
+	models.ProxySSLName,
+	models.ProxySSLServerName,
+	models.ProxySSLVerifyDepth,
+}
+
+/* ---------------- BACKEND TLS ---------------- */
+
+// backendTLS handles the below annotations.
+// Annotations:
+//   - "nginx.ingress.kubernetes.io/proxy-ssl-verify"
+//   - "nginx.ingress.kubernetes.io/proxy-ssl-secret"
+//   - "nginx.ingress.kubernetes.io/proxy-ssl-name"
+//   - "nginx.ingress.kubernetes.io/proxy-ssl-server-name"
+//   - "nginx.ingress.kubernetes.io/proxy-ssl-verify-depth"
+//
+// NGINX does not verify upstream certificates unless proxy-ssl-verify is on, whereas Traefik always does.
+// For HTTPS and GRPCS backends the verification is therefore disabled explicitly unless it was enabled.
+// It reports whether any setting was applied to the ServersTransport spec.
+func backendTLS(ctx configs.Context, spec *traefik.ServersTransportSpec) bool {
+	if !isTLSBackend(ctx.Annotations) {
+		for _, ann := range proxySSLAnnotations {
+			if _, ok := ctx.Annotations[string(ann)]; ok {
+				ctx.ReportIgnored(string(ann), "the backend is not HTTPS or GRPCS, upstream TLS settings do not apply")
+			}
+		}
+
+		return false
+	}
+
+	annVerify := string(models.ProxySSLVerify)
+
+	if strings.ToLower(strings.TrimSpace(ctx.Annotations[annVerify])) != "on" {
+		spec.InsecureSkipVerify = true
+
+		msg := "upstream certificates are not verified, as ingress-nginx does without proxy-ssl-verify; " +
+			"enable proxy-ssl-verify with a CA in proxy-ssl-secret to verify them"
+
+		ctx.Result.Warnings = append(ctx.Result.Warnings, msg)
+
+		if _, ok := ctx.Annotations[annVerify]; ok {
+			ctx.ReportConverted(annVerify)
+		}
+
+		for _, ann := range proxySSLAnnotations[1:] {
+			if _, ok := ctx.Annotations[string(ann)]; ok {
+				ctx.ReportIgnored(string(ann), "proxy-ssl-verify is off, upstream certificates are not verified")
+			}
+		}
+
+		return true
+	}
+
+	ctx.ReportConverted(annVerify)
+
+	proxySSLSecret(ctx, spec)
+	proxySSLServerName(ctx, spec)
+
+	if _, ok := ctx.Annotations[string(models.ProxySSLVerifyDepth)]; ok {
+		msg := "proxy-ssl-verify-depth has no Traefik equivalent, the whole upstream certificate chain is verified"
+
+		ctx.Result.Warnings = append(ctx.Result.Warnings, msg)
+		ctx.ReportWarning(string(models.ProxySSLVerifyDepth), msg)
+	}
+
+	return true
+}
+
+func proxySSLSecret(ctx configs.Context, spec *traefik.ServersTransportSpec) {
+	ann := string(models.ProxySSLSecret)
+
+	val := strings.TrimSpace(ctx.Annotations[ann])
+	if val == "" {
+		msg := "proxy-ssl-verify is on without proxy-ssl-secret; upstream certificates are verified against the system CAs"
+
+		ctx.Result.Warnings = append(ctx.Result.Warnings, msg)
+
+		return
+	}
+
+	namespace, name, found := strings.Cut(val, "/")
+	if !found {
+		namespace, name = ctx.Namespace, val
+	}
+
+	spec.RootCAsSecrets = []string{name}
+
+	if namespace != ctx.Namespace {
+		msg := fmt.Sprintf("proxy-ssl-secret %s lives in namespace %s, "+
+			"Traefik reads rootCAsSecrets from the namespace of the ServersTransport (%s); copy the secret there",
+			name, namespace, ctx.Namespace)
+
+		ctx.Result.Warnings = append(ctx.Result.Warnings, msg)
+		ctx.ReportWarning(ann, msg)
+
+		return
+	}
+
+	ctx.Result.Warnings = append(ctx.Result.Warnings,
+		"proxy-ssl-secret was used as CA for upstream verification; if it also holds a client certificate, "+
+			"add it to certificatesSecrets of the generated ServersTransport",
+	)
+
+	ctx.ReportConverted(ann)
+}
+
+func proxySSLServerName(ctx configs.Context, spec *traefik.ServersTransportSpec) {
+	annName := string(models.ProxySSLName)
+	annServerName := string(models.ProxySSLServerName)
+
+	if name := strings.TrimSpace(ctx.Annotations[annName]); name != "" {
+		// Traefik verifies the certificate against serverName and sends it as SNI.
+		spec.ServerName = name
+
+		ctx.ReportConverted(annName)
+	}
+
+	val, ok := ctx.Annotations[annServerName]
+	if !ok {
+		return
+	}
+
+	switch {
+	case strings.ToLower(strings.TrimSpace(val)) != "on":
+		ctx.ReportIgnored(annServerName, "Traefik only sends SNI to the upstream when serverName is set")
+	case spec.ServerName != "":
+		ctx.ReportConverted(annServerName)
+	default:
+		msg := "proxy-ssl-server-name is on without proxy-ssl-name; set serverName of the generated ServersTransport " +
+			"to the host name expected by the upstream certificate"
+
+		ctx.Result.Warnings = append(ctx.Result.Warnings, msg)
+		ctx.ReportWarning(annServerName, msg)
+	}
+}
+
+// isTLSBackend reports whether the backend-protocol annotation selects a TLS upstream.
+func isTLSBackend(annotations map[string]string) bool {
+	switch strings.ToUpper(strings.TrimSpace(annotations[string(models.BackendProtocol)])) {
+	case "HTTPS", "GRPCS":
+		return true
+	default:
+		return false
+	}
+}
@@ -0,0 +1,117 @@
+package transport_test
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/nikhilsbhat/nginx-traefik-converter/pkg/configs"
+)
+
+func TestServersTransportBackendTLS(t *testing.T) {
+	tests := []struct {
+		name        string
+		annotations map[string]string
+		skipVerify  bool
+		rootCAs     []string
+		serverName  string
+		statuses    map[string]configs.AnnotationStatus
+		noTransport bool
+	}{
+		{
+			name:        "HTTPS backends are not verified by default",
+			annotations: map[string]string{"backend-protocol": "HTTPS"},
+			skipVerify:  true,
+		},
+		{
+			name: "settings without verification are ignored",
+			annotations: map[string]string{
+				"backend-protocol": "GRPCS",
+				"proxy-ssl-verify": "off",
+				"proxy-ssl-secret": "default/upstream-ca",
+			},
+			skipVerify: true,
+			statuses: map[string]configs.AnnotationStatus{
+				"proxy-ssl-verify": configs.AnnotationConverted,
+				"proxy-ssl-secret": configs.AnnotationIgnored,
+			},
+		},
+		{
+			name: "verification with a CA and a server name",
+			annotations: map[string]string{
+				"backend-protocol":       "HTTPS",
+				"proxy-ssl-verify":       "on",
+				"proxy-ssl-secret":       "default/upstream-ca",
+				"proxy-ssl-name":         "upstream.internal",
+				"proxy-ssl-server-name":  "on",
+				"proxy-ssl-verify-depth": "2",
+			},
+			rootCAs:    []string{"upstream-ca"},
+			serverName: "upstream.internal",
+			statuses: map[string]configs.AnnotationStatus{
+				"proxy-ssl-verify":       configs.AnnotationConverted,
+				"proxy-ssl-secret":       configs.AnnotationConverted,
+				"proxy-ssl-name":         configs.AnnotationConverted,
+				"proxy-ssl-server-name":  configs.AnnotationConverted,
+				"proxy-ssl-verify-depth": configs.AnnotationWarned,
+			},
+		},
+		{
+			name: "CA of another namespace",
+			annotations: map[string]string{
+				"backend-protocol":      "HTTPS",
+				"proxy-ssl-verify":      "on",
+				"proxy-ssl-secret":      "security/upstream-ca",
+				"proxy-ssl-server-name": "on",
+			},
+			rootCAs: []string{"upstream-ca"},
+			statuses: map[string]configs.AnnotationStatus{
+				"proxy-ssl-secret":      configs.AnnotationWarned,
+				"proxy-ssl-server-name": configs.AnnotationWarned,
+			},
+		},
+		{
+			name: "plain backends ignore the upstream TLS settings",
+			annotations: map[string]string{
+				"proxy-ssl-verify": "on",
+				"proxy-ssl-secret": "default/upstream-ca",
+			},
+			noTransport: true,
+			statuses: map[string]configs.AnnotationStatus{
+				"proxy-ssl-verify": configs.AnnotationIgnored,
+				"proxy-ssl-secret": configs.AnnotationIgnored,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := newContext(tt.annotations, nil)
+
+			serversTransport := serversTransport(t, ctx)
+
+			if tt.noTransport {
+				if serversTransport != nil {
+					t.Errorf("ServersTransport = %+v, want none", serversTransport.Spec)
+				}
+			} else {
+				if serversTransport == nil {
+					t.Fatal("no ServersTransport was generated")
+				}
+
+				spec := serversTransport.Spec
+
+				if spec.InsecureSkipVerify != tt.skipVerify || !slices.Equal(spec.RootCAsSecrets, tt.rootCAs) ||
+					spec.ServerName != tt.serverName {
+					t.Errorf("spec = insecureSkipVerify %t, rootCAsSecrets %v, serverName %q",
+						spec.InsecureSkipVerify, spec.RootCAsSecrets, spec.ServerName)
+				}
+			}
+
+			for annotation, status := range tt.statuses {
+				if got := statuses(ctx, annotation); !slices.Equal(got, []configs.AnnotationStatus{status}) {
+					t.Errorf("%s statuses = %v, want [%s]", annotation, got, status)
+				}
+			}
+		})
+	}
+}
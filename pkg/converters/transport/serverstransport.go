@@ -0,0 +1,52 @@
+package transport
+
+import (
+	"github.com/nikhilsbhat/nginx-traefik-converter/pkg/configs"
+	traefik "github.com/traefik/traefik/v3/pkg/provider/kubernetes/crd/traefikio/v1alpha1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// HandleServersTransport is responsible for handling the upstream connection configs of nginx annotations.
+// It generates a single ServersTransport per ingress, referenced by every service of its IngressRoutes.
+func HandleServersTransport(ctx configs.Context) {
+	ctx.Log.Debug("running converter ServersTransport")
+
+	spec := traefik.ServersTransportSpec{}
+
+	configured := forwardingTimeouts(ctx, &spec)
+	configured = keepalive(ctx, &spec) || configured
+	configured = backendTLS(ctx, &spec) || configured
+
+	if !configured {
+		return
+	}
+
+	emitServersTransport(ctx, spec)
+}
+
+// Reference returns the name of the ServersTransport generated for the ingress, empty when there is none.
+func Reference(ctx configs.Context) string {
+	return ctx.Result.ServersTransportRefs[ctx.IngressName]
+}
+
+func emitServersTransport(ctx configs.Context, spec traefik.ServersTransportSpec) {
+	if ctx.Result.ServersTransportRefs == nil {
+		ctx.Result.ServersTransportRefs = make(map[string]string)
+	}
+
+	name := ctx.IngressName + "-transport"
+
+	ctx.Result.ServersTransports = append(ctx.Result.ServersTransports, &traefik.ServersTransport{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: traefik.SchemeGroupVersion.String(),
+			Kind:       "ServersTransport",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: ctx.Namespace,
+		},
+		Spec: spec,
+	})
+
+	ctx.Result.ServersTransportRefs[ctx.IngressName] = name
+}
@@ -0,0 +1,176 @@
+package transport
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/nikhilsbhat/nginx-traefik-converter/pkg/configs"
+	"github.com/nikhilsbhat/nginx-traefik-converter/pkg/converters/models"
+	"github.com/nikhilsbhat/nginx-traefik-converter/pkg/errors"
+	traefik "github.com/traefik/traefik/v3/pkg/provider/kubernetes/crd/traefikio/v1alpha1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+)
+
+/* ---------------- TIMEOUTS ---------------- */
+
+// forwardingTimeouts handles the below annotations.
+// Annotations:
+//   - "nginx.ingress.kubernetes.io/proxy-connect-timeout"
+//   - "nginx.ingress.kubernetes.io/proxy-read-timeout"
+//   - "nginx.ingress.kubernetes.io/proxy-send-timeout"
+//
+// It reports whether any setting was applied to the ServersTransport spec.
+func forwardingTimeouts(ctx configs.Context, spec *traefik.ServersTransportSpec) bool {
+	timeouts := &traefik.ForwardingTimeouts{}
+
+	if dialTimeout := durationAnnotation(ctx, string(models.ProxyConnectTimeout)); dialTimeout != nil {
+		timeouts.DialTimeout = dialTimeout
+
+		ctx.ReportConverted(string(models.ProxyConnectTimeout))
+	}
+
+	if readTimeout := durationAnnotation(ctx, string(models.ProxyReadTimeout)); readTimeout != nil {
+		timeouts.ResponseHeaderTimeout = readTimeout
+
+		msg := "proxy-read-timeout was converted to responseHeaderTimeout, which bounds the wait for the response headers only; " +
+			"Traefik does not time out between two reads of the response body"
+
+		ctx.Result.Warnings = append(ctx.Result.Warnings, msg)
+		ctx.ReportWarning(string(models.ProxyReadTimeout), msg)
+	}
+
+	if _, ok := ctx.Annotation(string(models.ProxySendTimeout)); ok {
+		msg := "proxy-send-timeout has no Traefik equivalent, Traefik does not time out writes to the upstream; " +
+			"use entryPoints.<name>.transport.respondingTimeouts.readTimeout in static configuration to bound request bodies"
+
+		ctx.Result.Warnings = append(ctx.Result.Warnings, msg)
+		ctx.ReportSkipped(string(models.ProxySendTimeout), msg)
+	}
+
+	if timeouts.DialTimeout == nil && timeouts.ResponseHeaderTimeout == nil {
+		return false
+	}
+
+	spec.ForwardingTimeouts = timeouts
+
+	return true
+}
+
+/* ---------------- KEEPALIVE ---------------- */
+
+// keepalive handles the below annotations.
+// Annotations:
+//   - "nginx.ingress.kubernetes.io/upstream-keepalive-connections"
+//   - "nginx.ingress.kubernetes.io/upstream-keepalive-timeout"
+//   - "nginx.ingress.kubernetes.io/upstream-keepalive-requests"
+//   - "nginx.ingress.kubernetes.io/upstream-keepalive-time"
+//   - "nginx.ingress.kubernetes.io/proxy-http-version"
+//
+// It reports whether any setting was applied to the ServersTransport spec.
+func keepalive(ctx configs.Context, spec *traefik.ServersTransportSpec) bool {
+	var configured bool
+
+	annConnections := string(models.UpstreamKeepaliveConnections)
+
+	if val, ok := ctx.Annotation(annConnections); ok {
+		connections, err := strconv.Atoi(strings.TrimSpace(val))
+		if err != nil || connections < 0 {
+			msg := fmt.Sprintf("upstream-keepalive-connections has invalid value %q and was ignored", val)
+
+			ctx.Result.Warnings = append(ctx.Result.Warnings, msg)
+			ctx.ReportSkipped(annConnections, msg)
+		} else {
+			// nginx disables keepalive with 0, Traefik with a negative value.
+			if connections == 0 {
+				connections = -1
+			}
+
+			spec.MaxIdleConnsPerHost = connections
+			configured = true
+
+			ctx.ReportConverted(annConnections)
+		}
+	}
+
+	if idleTimeout := durationAnnotation(ctx, string(models.UpstreamKeepaliveTimeout)); idleTimeout != nil {
+		if spec.ForwardingTimeouts == nil {
+			spec.ForwardingTimeouts = &traefik.ForwardingTimeouts{}
+		}
+
+		spec.ForwardingTimeouts.IdleConnTimeout = idleTimeout
+		configured = true
+
+		ctx.ReportConverted(string(models.UpstreamKeepaliveTimeout))
+	}
+
+	for _, ann := range []string{string(models.UpstreamKeepaliveRequests), string(models.UpstreamKeepaliveTime)} {
+		if _, ok := ctx.Annotation(ann); ok {
+			msg := strings.TrimPrefix(ann, models.NginxAnnotationPrefix) +
+				" has no Traefik equivalent, idle upstream connections are only bounded by idleConnTimeout"
+
+			ctx.Result.Warnings = append(ctx.Result.Warnings, msg)
+			ctx.ReportSkipped(ann, msg)
+		}
+	}
+
+	proxyHTTPVersion(ctx)
+
+	return configured
+}
+
+func proxyHTTPVersion(ctx configs.Context) {
+	ann := string(models.ProxyHTTPVersion)
+
+	val, ok := ctx.Annotation(ann)
+	if !ok {
+		return
+	}
+
+	switch strings.TrimSpace(val) {
+	case "1.1":
+		ctx.ReportIgnored(ann, "Traefik talks HTTP/1.1 to plain HTTP backends by default")
+	default:
+		msg := fmt.Sprintf("proxy-http-version %q has no Traefik equivalent, Traefik talks HTTP/1.1 to plain HTTP backends", val)
+
+		ctx.Result.Warnings = append(ctx.Result.Warnings, msg)
+		ctx.ReportSkipped(ann, msg)
+	}
+}
+
+// durationAnnotation parses an nginx time annotation into a Traefik duration,
+// nil is returned when the annotation is not set or invalid, invalid values are reported as skipped.
+func durationAnnotation(ctx configs.Context, ann string) *intstr.IntOrString {
+	val, ok := ctx.Annotation(ann)
+	if !ok {
+		return nil
+	}
+
+	duration, err := parseNginxDuration(val)
+	if err != nil {
+		ctx.Result.Warnings = append(ctx.Result.Warnings, err.Error())
+		ctx.ReportSkipped(ann, err.Error())
+
+		return nil
+	}
+
+	value := intstr.FromString(duration)
+
+	return &value
+}
+
+// parseNginxDuration converts an nginx time value, where a plain number means seconds, into a Go duration string.
+func parseNginxDuration(val string) (string, error) {
+	value := strings.TrimSpace(val)
+
+	if seconds, err := strconv.Atoi(value); err == nil && seconds >= 0 {
+		return strconv.Itoa(seconds) + "s", nil
+	}
+
+	if _, err := time.ParseDuration(value); err == nil {
+		return value, nil
+	}
+
+	return "", &errors.ConverterError{Message: fmt.Sprintf("invalid time value %q", val)}
+}
@@ -0,0 +1,127 @@
+package transport_test
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/nikhilsbhat/nginx-traefik-converter/pkg/configs"
+	"k8s.io/apimachinery/pkg/util/intstr"
+)
+
+func durationString(value *intstr.IntOrString) string {
+	if value == nil {
+		return ""
+	}
+
+	return value.String()
+}
+
+func TestServersTransportTimeouts(t *testing.T) {
+	tests := []struct {
+		name           string
+		annotations    map[string]string
+		dial           string
+		responseHeader string
+		idle           string
+		maxIdle        int
+		statuses       map[string]configs.AnnotationStatus
+	}{
+		{
+			name: "timeouts in seconds and units",
+			annotations: map[string]string{
+				"proxy-connect-timeout": "5",
+				"proxy-read-timeout":    "2m",
+				"proxy-send-timeout":    "60",
+			},
+			dial:           "5s",
+			responseHeader: "2m",
+			statuses: map[string]configs.AnnotationStatus{
+				"proxy-connect-timeout": configs.AnnotationConverted,
+				"proxy-read-timeout":    configs.AnnotationWarned,
+				"proxy-send-timeout":    configs.AnnotationSkipped,
+			},
+		},
+		{
+			name: "keepalive",
+			annotations: map[string]string{
+				"upstream-keepalive-connections": "32",
+				"upstream-keepalive-timeout":     "60",
+				"upstream-keepalive-requests":    "1000",
+				"proxy-http-version":             "1.1",
+			},
+			idle:    "60s",
+			maxIdle: 32,
+			statuses: map[string]configs.AnnotationStatus{
+				"upstream-keepalive-connections": configs.AnnotationConverted,
+				"upstream-keepalive-timeout":     configs.AnnotationConverted,
+				"upstream-keepalive-requests":    configs.AnnotationSkipped,
+				"proxy-http-version":             configs.AnnotationIgnored,
+			},
+		},
+		{
+			name:        "keepalive disabled",
+			annotations: map[string]string{"upstream-keepalive-connections": "0"},
+			maxIdle:     -1,
+		},
+		{
+			name: "invalid values are skipped",
+			annotations: map[string]string{
+				"proxy-connect-timeout":          "soon",
+				"upstream-keepalive-connections": "many",
+				"upstream-keepalive-timeout":     "30",
+			},
+			idle: "30s",
+			statuses: map[string]configs.AnnotationStatus{
+				"proxy-connect-timeout":          configs.AnnotationSkipped,
+				"upstream-keepalive-connections": configs.AnnotationSkipped,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := newContext(tt.annotations, nil)
+
+			serversTransport := serversTransport(t, ctx)
+			if serversTransport == nil {
+				t.Fatal("no ServersTransport was generated")
+			}
+
+			spec := serversTransport.Spec
+
+			if serversTransport.Name != "app-transport" || serversTransport.Namespace != "default" {
+				t.Errorf("ServersTransport = %s/%s, want default/app-transport", serversTransport.Namespace, serversTransport.Name)
+			}
+
+			var dial, responseHeader, idle string
+			if timeouts := spec.ForwardingTimeouts; timeouts != nil {
+				dial = durationString(timeouts.DialTimeout)
+				responseHeader = durationString(timeouts.ResponseHeaderTimeout)
+				idle = durationString(timeouts.IdleConnTimeout)
+			}
+
+			if dial != tt.dial || responseHeader != tt.responseHeader || idle != tt.idle {
+				t.Errorf("timeouts = dial %q, responseHeader %q, idle %q, want %q, %q, %q",
+					dial, responseHeader, idle, tt.dial, tt.responseHeader, tt.idle)
+			}
+
+			if spec.MaxIdleConnsPerHost != tt.maxIdle {
+				t.Errorf("maxIdleConnsPerHost = %d, want %d", spec.MaxIdleConnsPerHost, tt.maxIdle)
+			}
+
+			for annotation, status := range tt.statuses {
+				if got := statuses(ctx, annotation); !slices.Equal(got, []configs.AnnotationStatus{status}) {
+					t.Errorf("%s statuses = %v, want [%s]", annotation, got, status)
+				}
+			}
+		})
+	}
+}
+
+func TestServersTransportNotNeeded(t *testing.T) {
+	ctx := newContext(map[string]string{"proxy-send-timeout": "60"}, nil)
+
+	if serversTransport := serversTransport(t, ctx); serversTransport != nil {
+		t.Errorf("ServersTransport = %+v, want none", serversTransport.Spec)
+	}
+}
@@ -1,28 +1,270 @@
+// Package ingress loads Kubernetes Ingress resources, and the ConfigMaps of the ingress-nginx
+// controller, from local manifests so that conversions can run without access to a cluster.
 package ingress
 
-// var scheme = runtime.NewScheme()
-//
-// func init() {
-//	_ = netv1.AddToScheme(scheme)
-// }
-//
-// func Load(path string) (*netv1.Ingress, error) {
-//	data, err := os.ReadFile(path)
-//	if err != nil {
-//		return nil, err
-//	}
-//
-//	decoder := serializer.NewCodecFactory(scheme).UniversalDeserializer()
-//
-//	obj, _, err := decoder.Decode(data, nil, nil)
-//	if err != nil {
-//		return nil, err
-//	}
-//
-//	ing, ok := obj.(*netv1.Ingress)
-//	if !ok {
-//		return nil, fmt.Errorf("file does not contain a networking.k8s.io/v1 Ingress")
-//	}
-//
-//	return ing, nil
-// }
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	netv1 "k8s.io/api/networking/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	utilyaml "k8s.io/apimachinery/pkg/util/yaml"
+	"sigs.k8s.io/yaml"
+)
+
+// Stdin is the path that instructs the loader to read manifests from standard input.
+const Stdin = "-"
+
+// Manifests holds the resources read from local manifests.
+type Manifests struct {
+	Ingresses  []netv1.Ingress
+	ConfigMaps []corev1.ConfigMap
+}
+
+// ConfigMap returns the ConfigMap with the given namespace and name, nil when it was not loaded.
+// ConfigMaps without namespace match any namespace.
+func (manifests *Manifests) ConfigMap(namespace, name string) *corev1.ConfigMap {
+	for index := range manifests.ConfigMaps {
+		configMap := &manifests.ConfigMaps[index]
+
+		if configMap.Name == name && (configMap.Namespace == namespace || configMap.Namespace == "") {
+			return configMap
+		}
+	}
+
+	return nil
+}
+
+func (manifests *Manifests) add(found *Manifests) {
+	manifests.Ingresses = append(manifests.Ingresses, found.Ingresses...)
+	manifests.ConfigMaps = append(manifests.ConfigMaps, found.ConfigMaps...)
+}
+
+// manifestList holds the items of a `kind: List` (or `kind: IngressList`) document,
+// for example the output of `kubectl get ingress -A -o yaml`.
+type manifestList struct {
+	Items []runtime.RawExtension `json:"items"`
+}
+
+// Load reads every networking.k8s.io/v1 Ingress found in the given paths.
+// A path can be a file, a directory (walked recursively for .yaml, .yml and .json files)
+// or "-" to read from stdin. Files may contain multiple YAML documents and List kinds,
+// all other resources are ignored.
+func Load(paths ...string) ([]netv1.Ingress, error) {
+	manifests, err := LoadManifests(paths...)
+	if err != nil {
+		return nil, err
+	}
+
+	return manifests.Ingresses, nil
+}
+
+// LoadManifests reads every networking.k8s.io/v1 Ingress and v1 ConfigMap found in the given paths,
+// which are resolved as in Load.
+func LoadManifests(paths ...string) (*Manifests, error) {
+	manifests := &Manifests{
+		Ingresses:  make([]netv1.Ingress, 0),
+		ConfigMaps: make([]corev1.ConfigMap, 0),
+	}
+
+	for _, path := range paths {
+		if path == Stdin {
+			found, err := DecodeManifests(os.Stdin)
+			if err != nil {
+				return nil, fmt.Errorf("reading ingresses from stdin: %w", err)
+			}
+
+			manifests.add(found)
+
+			continue
+		}
+
+		files, err := manifestFiles(path)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, file := range files {
+			found, err := loadFile(file)
+			if err != nil {
+				return nil, fmt.Errorf("reading ingresses from %s: %w", file, err)
+			}
+
+			manifests.add(found)
+		}
+	}
+
+	return manifests, nil
+}
+
+// Decode reads all YAML or JSON documents from the reader and returns the Ingresses in them.
+func Decode(reader io.Reader) ([]netv1.Ingress, error) {
+	manifests, err := DecodeManifests(reader)
+	if err != nil {
+		return nil, err
+	}
+
+	return manifests.Ingresses, nil
+}
+
+// DecodeManifests reads all YAML or JSON documents from the reader and returns the Ingresses and ConfigMaps in them.
+func DecodeManifests(reader io.Reader) (*Manifests, error) {
+	yamlReader := utilyaml.NewYAMLReader(bufio.NewReader(reader))
+
+	manifests := &Manifests{
+		Ingresses:  make([]netv1.Ingress, 0),
+		ConfigMaps: make([]corev1.ConfigMap, 0),
+	}
+
+	for {
+		doc, err := yamlReader.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+
+		if err != nil {
+			return nil, err
+		}
+
+		if err = decodeDocument(doc, manifests); err != nil {
+			return nil, err
+		}
+	}
+
+	return manifests, nil
+}
+
+func decodeDocument(doc []byte, manifests *Manifests) error {
+	if len(bytes.TrimSpace(doc)) == 0 {
+		return nil
+	}
+
+	var typeMeta metav1.TypeMeta
+	if err := yaml.Unmarshal(doc, &typeMeta); err != nil {
+		return err
+	}
+
+	switch {
+	case typeMeta.Kind == "Ingress" && typeMeta.APIVersion == netv1.SchemeGroupVersion.String():
+		var ing netv1.Ingress
+		if err := yaml.Unmarshal(doc, &ing); err != nil {
+			return err
+		}
+
+		defaultPathTypes(&ing)
+
+		manifests.Ingresses = append(manifests.Ingresses, ing)
+
+		return nil
+
+	case typeMeta.Kind == "ConfigMap" && typeMeta.APIVersion == corev1.SchemeGroupVersion.String():
+		var configMap corev1.ConfigMap
+		if err := yaml.Unmarshal(doc, &configMap); err != nil {
+			return err
+		}
+
+		manifests.ConfigMaps = append(manifests.ConfigMaps, configMap)
+
+		return nil
+
+	case typeMeta.Kind == "List" || strings.HasSuffix(typeMeta.Kind, "List"):
+		var list manifestList
+		if err := yaml.Unmarshal(doc, &list); err != nil {
+			return err
+		}
+
+		for _, item := range list.Items {
+			if err := decodeDocument(item.Raw, manifests); err != nil {
+				return err
+			}
+		}
+
+		return nil
+
+	default:
+		return nil
+	}
+}
+
+// defaultPathTypes sets the pathType the API server defaults to on the paths that do not set one,
+// as local manifests do not go through the API server.
+func defaultPathTypes(ing *netv1.Ingress) {
+	for ruleIndex := range ing.Spec.Rules {
+		http := ing.Spec.Rules[ruleIndex].HTTP
+		if http == nil {
+			continue
+		}
+
+		for pathIndex := range http.Paths {
+			if http.Paths[pathIndex].PathType == nil {
+				pathType := netv1.PathTypeImplementationSpecific
+				http.Paths[pathIndex].PathType = &pathType
+			}
+		}
+	}
+}
+
+func loadFile(path string) (manifests *Manifests, err error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+
+	defer func(f *os.File) {
+		if cerr := f.Close(); cerr != nil && err == nil {
+			err = cerr
+		}
+	}(file)
+
+	return DecodeManifests(file)
+}
+
+func manifestFiles(path string) ([]string, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, err
+	}
+
+	if !info.IsDir() {
+		return []string{path}, nil
+	}
+
+	files := make([]string, 0)
+
+	err = filepath.WalkDir(path, func(filePath string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+
+		if entry.IsDir() || !isManifest(filePath) {
+			return nil
+		}
+
+		files = append(files, filePath)
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return files, nil
+}
+
+func isManifest(path string) bool {
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".yaml", ".yml", ".json":
+		return true
+	default:
+		return false
+	}
+}
@@ -0,0 +1,199 @@
+package ingress_test
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/nikhilsbhat/nginx-traefik-converter/pkg/ingress"
+	netv1 "k8s.io/api/networking/v1"
+)
+
+const ingressWithoutPathType = `
+apiVersion: networking.k8s.io/v1
+kind: Ingress
+metadata:
+  name: app
+  namespace: default
+spec:
+  rules:
+  - host: app.example.com
+    http:
+      paths:
+      - path: /
+        backend:
+          service:
+            name: app
+            port:
+              number: 80
+      - path: /api
+        pathType: Prefix
+        backend:
+          service:
+            name: api
+            port:
+              number: 80
+`
+
+func TestDecodeManifests(t *testing.T) {
+	tests := []struct {
+		name       string
+		manifest   string
+		ingresses  []string
+		configMaps []string
+	}{
+		{
+			name:      "single ingress",
+			manifest:  ingressWithoutPathType,
+			ingresses: []string{"default/app"},
+		},
+		{
+			name: "multiple documents with other kinds",
+			manifest: ingressWithoutPathType + `
+---
+apiVersion: v1
+kind: Service
+metadata:
+  name: app
+---
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: ingress-nginx-controller
+  namespace: ingress-nginx
+data:
+  use-forwarded-headers: "true"
+`,
+			ingresses:  []string{"default/app"},
+			configMaps: []string{"ingress-nginx/ingress-nginx-controller"},
+		},
+		{
+			name: "list",
+			manifest: `
+apiVersion: v1
+kind: List
+items:
+- apiVersion: networking.k8s.io/v1
+  kind: Ingress
+  metadata:
+    name: one
+    namespace: a
+- apiVersion: networking.k8s.io/v1
+  kind: Ingress
+  metadata:
+    name: two
+    namespace: b
+`,
+			ingresses: []string{"a/one", "b/two"},
+		},
+		{
+			name: "other ingress versions are ignored",
+			manifest: `
+apiVersion: extensions/v1beta1
+kind: Ingress
+metadata:
+  name: legacy
+`,
+		},
+		{
+			name:     "empty documents",
+			manifest: "---\n---\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			manifests, err := ingress.DecodeManifests(strings.NewReader(tt.manifest))
+			if err != nil {
+				t.Fatalf("DecodeManifests() error = %v", err)
+			}
+
+			ingresses := make([]string, 0)
+			for _, ing := range manifests.Ingresses {
+				ingresses = append(ingresses, ing.Namespace+"/"+ing.Name)
+			}
+
+			configMaps := make([]string, 0)
+			for _, configMap := range manifests.ConfigMaps {
+				configMaps = append(configMaps, configMap.Namespace+"/"+configMap.Name)
+			}
+
+			if strings.Join(ingresses, ",") != strings.Join(tt.ingresses, ",") {
+				t.Errorf("ingresses = %v, want %v", ingresses, tt.ingresses)
+			}
+
+			if strings.Join(configMaps, ",") != strings.Join(tt.configMaps, ",") {
+				t.Errorf("configMaps = %v, want %v", configMaps, tt.configMaps)
+			}
+		})
+	}
+}
+
+func TestDecodeManifestsDefaultsPathType(t *testing.T) {
+	ingresses, err := ingress.Decode(strings.NewReader(ingressWithoutPathType))
+	if err != nil {
+		t.Fatalf("Decode() error = %v", err)
+	}
+
+	paths := ingresses[0].Spec.Rules[0].HTTP.Paths
+
+	want := []netv1.PathType{netv1.PathTypeImplementationSpecific, netv1.PathTypePrefix}
+	for index, path := range paths {
+		if path.PathType == nil {
+			t.Fatalf("path %s has no pathType", path.Path)
+		}
+
+		if *path.PathType != want[index] {
+			t.Errorf("path %s pathType = %s, want %s", path.Path, *path.PathType, want[index])
+		}
+	}
+}
+
+func TestDecodeManifestsInvalid(t *testing.T) {
+	_, err := ingress.DecodeManifests(strings.NewReader("apiVersion: [unterminated"))
+	if err == nil {
+		t.Fatal("DecodeManifests() expected an error for invalid YAML")
+	}
+}
+
+func TestLoadDirectory(t *testing.T) {
+	dir := t.TempDir()
+
+	files := map[string]string{
+		"app.yaml":               ingressWithoutPathType,
+		"nested/other.yml":       strings.ReplaceAll(ingressWithoutPathType, "name: app\n  namespace", "name: other\n  namespace"),
+		"nested/readme.txt":      "not a manifest",
+		"nested/deep/empty.json": "",
+	}
+
+	for name, content := range files {
+		path := filepath.Join(dir, name)
+
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatal(err)
+		}
+
+		if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	ingresses, err := ingress.Load(dir)
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+
+	names := make([]string, 0)
+	for _, ing := range ingresses {
+		names = append(names, ing.Name)
+	}
+
+	if strings.Join(names, ",") != "app,other" {
+		t.Errorf("ingresses = %v, want [app other]", names)
+	}
+
+	if _, err = ingress.Load(filepath.Join(dir, "missing.yaml")); err == nil {
+		t.Error("Load() expected an error for a missing path")
+	}
+}
@@ -0,0 +1,13 @@
+package kubernetes
+
+import (
+	"context"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// GetConfigMap fetches the ConfigMap with the given name from the given namespace.
+func (cfg *Config) GetConfigMap(namespace, name string) (*corev1.ConfigMap, error) {
+	return cfg.clientSet.CoreV1().ConfigMaps(namespace).Get(context.TODO(), name, metav1.GetOptions{})
+}
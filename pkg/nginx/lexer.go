@@ -0,0 +1,203 @@
+// Package nginx parses NGINX configuration, as found in the snippet annotations of ingress-nginx,
+// into a tree of directives.
+package nginx
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/nikhilsbhat/nginx-traefik-converter/pkg/errors"
+)
+
+type tokenKind int
+
+const (
+	tokenWord tokenKind = iota
+	tokenSemicolon
+	tokenBlockStart
+	tokenBlockEnd
+)
+
+// token is a lexical element of the configuration with the position it starts at.
+type token struct {
+	kind   tokenKind
+	value  string
+	quoted bool
+	line   int
+	column int
+}
+
+// lexer splits the configuration into words, semicolons and braces the way NGINX does:
+// words are separated by whitespace, quotes group a word and support backslash escapes,
+// # starts a comment running to the end of the line and ${name} variables may hold braces.
+type lexer struct {
+	src    []rune
+	pos    int
+	line   int
+	column int
+}
+
+func newLexer(src string) *lexer {
+	return &lexer{src: []rune(src), line: 1, column: 1}
+}
+
+func (lex *lexer) peek() (rune, bool) {
+	if lex.pos >= len(lex.src) {
+		return 0, false
+	}
+
+	return lex.src[lex.pos], true
+}
+
+func (lex *lexer) next() rune {
+	char := lex.src[lex.pos]
+	lex.pos++
+
+	if char == '\n' {
+		lex.line++
+		lex.column = 1
+	} else {
+		lex.column++
+	}
+
+	return char
+}
+
+func isSpace(char rune) bool {
+	return char == ' ' || char == '\t' || char == '\n' || char == '\r'
+}
+
+// tokens returns all the tokens of the configuration.
+func (lex *lexer) tokens() ([]token, error) {
+	tokens := make([]token, 0)
+
+	for {
+		char, ok := lex.peek()
+		if !ok {
+			return tokens, nil
+		}
+
+		line, column := lex.line, lex.column
+
+		switch {
+		case isSpace(char):
+			lex.next()
+
+		case char == '#':
+			lex.skipComment()
+
+		case char == ';':
+			lex.next()
+			tokens = append(tokens, token{kind: tokenSemicolon, value: ";", line: line, column: column})
+
+		case char == '{':
+			lex.next()
+			tokens = append(tokens, token{kind: tokenBlockStart, value: "{", line: line, column: column})
+
+		case char == '}':
+			lex.next()
+			tokens = append(tokens, token{kind: tokenBlockEnd, value: "}", line: line, column: column})
+
+		case char == '"' || char == '\'':
+			value, err := lex.quoted()
+			if err != nil {
+				return nil, err
+			}
+
+			tokens = append(tokens, token{kind: tokenWord, value: value, quoted: true, line: line, column: column})
+
+		default:
+			tokens = append(tokens, token{kind: tokenWord, value: lex.word(), line: line, column: column})
+		}
+	}
+}
+
+func (lex *lexer) skipComment() {
+	for {
+		char, ok := lex.peek()
+		if !ok || char == '\n' {
+			return
+		}
+
+		lex.next()
+	}
+}
+
+// quoted reads a quoted word, the quotes are dropped and the escaped quotes and backslashes unescaped.
+func (lex *lexer) quoted() (string, error) {
+	line, column := lex.line, lex.column
+	quote := lex.next()
+
+	var value strings.Builder
+
+	for {
+		char, ok := lex.peek()
+		if !ok {
+			return "", &errors.ConverterError{
+				Message: fmt.Sprintf("line %d, column %d: unterminated %c quoted string", line, column, quote),
+			}
+		}
+
+		lex.next()
+
+		if char == quote {
+			return value.String(), nil
+		}
+
+		if char == '\\' {
+			if escaped, ok := lex.peek(); ok && (escaped == quote || escaped == '\\') {
+				value.WriteRune(lex.next())
+
+				continue
+			}
+		}
+
+		value.WriteRune(char)
+	}
+}
+
+// word reads an unquoted word, which ends at whitespace, a semicolon, a brace or a quote.
+// The braces of ${name} variables are part of the word.
+func (lex *lexer) word() string {
+	var value strings.Builder
+
+	for {
+		char, ok := lex.peek()
+		if !ok || isSpace(char) || char == ';' || char == '{' || char == '}' || char == '"' || char == '\'' {
+			return value.String()
+		}
+
+		lex.next()
+		value.WriteRune(char)
+
+		if char == '\\' {
+			if _, ok := lex.peek(); ok {
+				value.WriteRune(lex.next())
+			}
+
+			continue
+		}
+
+		if char == '$' {
+			if brace, ok := lex.peek(); ok && brace == '{' {
+				lex.variable(&value)
+			}
+		}
+	}
+}
+
+// variable reads the braced name of a ${name} variable.
+func (lex *lexer) variable(value *strings.Builder) {
+	for {
+		char, ok := lex.peek()
+		if !ok {
+			return
+		}
+
+		value.WriteRune(lex.next())
+
+		if char == '}' {
+			return
+		}
+	}
+}
@@ -0,0 +1,231 @@
+package nginx
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/nikhilsbhat/nginx-traefik-converter/pkg/errors"
+)
+
+// Directive is a single NGINX directive with the position of its name in the configuration.
+// Block holds the nested directives of block directives such as location or if, it is nil for simple directives.
+type Directive struct {
+	Name   string
+	Args   []string
+	Line   int
+	Column int
+	Block  []*Directive
+}
+
+// IsBlock reports whether the directive opens a block.
+func (directive *Directive) IsBlock() bool {
+	return directive.Block != nil
+}
+
+// String renders the directive on a single line as written in the configuration, without its block.
+func (directive *Directive) String() string {
+	parts := append([]string{directive.Name}, directive.Args...)
+
+	if directive.IsBlock() {
+		return strings.Join(parts, " ") + " { ... }"
+	}
+
+	return strings.Join(parts, " ") + ";"
+}
+
+// Position returns the line and column of the directive, as used in the conversion warnings.
+func (directive *Directive) Position() string {
+	return fmt.Sprintf("line %d, column %d", directive.Line, directive.Column)
+}
+
+// Parse parses the configuration into its directives.
+// The parentheses around the condition of if directives are dropped, as NGINX does.
+func Parse(src string) ([]*Directive, error) {
+	tokens, err := newLexer(src).tokens()
+	if err != nil {
+		return nil, err
+	}
+
+	parser := &parser{tokens: tokens}
+
+	directives, err := parser.block(nil)
+	if err != nil {
+		return nil, err
+	}
+
+	return directives, nil
+}
+
+type parser struct {
+	tokens []token
+	pos    int
+}
+
+// block parses directives until the end of the configuration, or until the closing brace of the given opening one.
+func (parser *parser) block(open *token) ([]*Directive, error) {
+	directives := make([]*Directive, 0)
+
+	for parser.pos < len(parser.tokens) {
+		current := parser.tokens[parser.pos]
+
+		switch current.kind {
+		case tokenBlockEnd:
+			if open == nil {
+				return nil, unexpected(current)
+			}
+
+			parser.pos++
+
+			return directives, nil
+
+		case tokenWord:
+			directive, err := parser.directive()
+			if err != nil {
+				return nil, err
+			}
+
+			directives = append(directives, directive)
+
+		default:
+			return nil, unexpected(current)
+		}
+	}
+
+	if open != nil {
+		return nil, &errors.ConverterError{
+			Message: fmt.Sprintf("line %d, column %d: unexpected end of configuration, the block opened here expects \"}\"",
+				open.line, open.column),
+		}
+	}
+
+	return directives, nil
+}
+
+// directive parses a directive starting at its name, up to its semicolon or the end of its block.
+func (parser *parser) directive() (*Directive, error) {
+	name := parser.tokens[parser.pos]
+	parser.pos++
+
+	directive := &Directive{
+		Name:   name.value,
+		Args:   make([]string, 0),
+		Line:   name.line,
+		Column: name.column,
+	}
+
+	for parser.pos < len(parser.tokens) {
+		current := parser.tokens[parser.pos]
+		parser.pos++
+
+		switch current.kind {
+		case tokenWord:
+			directive.Args = append(directive.Args, current.value)
+
+		case tokenSemicolon:
+			directive.Args = conditionArgs(directive)
+
+			return directive, nil
+
+		case tokenBlockStart:
+			directive.Args = conditionArgs(directive)
+
+			block, err := parser.block(&current)
+			if err != nil {
+				return nil, err
+			}
+
+			directive.Block = block
+
+			return directive, nil
+
+		default:
+			return nil, unexpected(current)
+		}
+	}
+
+	return nil, &errors.ConverterError{
+		Message: fmt.Sprintf("line %d, column %d: directive %q is not terminated by \";\"", name.line, name.column, name.value),
+	}
+}
+
+// conditionArgs drops the parentheses around the condition of an if directive.
+func conditionArgs(directive *Directive) []string {
+	if directive.Name != "if" || len(directive.Args) == 0 {
+		return directive.Args
+	}
+
+	args := directive.Args
+
+	if args[0] == "(" {
+		args = args[1:]
+	} else {
+		args[0] = strings.TrimPrefix(args[0], "(")
+	}
+
+	if last := len(args) - 1; last >= 0 {
+		if args[last] == ")" {
+			args = args[:last]
+		} else {
+			args[last] = strings.TrimSuffix(args[last], ")")
+		}
+	}
+
+	return args
+}
+
+func unexpected(current token) error {
+	return &errors.ConverterError{
+		Message: fmt.Sprintf("line %d, column %d: unexpected %q", current.line, current.column, current.value),
+	}
+}
+
+// Walk calls fn for every directive of the tree, parents before their nested directives.
+// The parents of the directive are passed from the outermost one.
+func Walk(directives []*Directive, fn func(directive *Directive, parents []*Directive)) {
+	walk(directives, nil, fn)
+}
+
+func walk(directives, parents []*Directive, fn func(directive *Directive, parents []*Directive)) {
+	for _, directive := range directives {
+		fn(directive, parents)
+
+		if directive.IsBlock() {
+			walk(directive.Block, append(parents[:len(parents):len(parents)], directive), fn)
+		}
+	}
+}
+
+// Find returns the first directive of the tree, searched depth first, with one of the given names.
+func Find(directives []*Directive, names ...string) *Directive {
+	var found *Directive
+
+	Walk(directives, func(directive *Directive, _ []*Directive) {
+		if found == nil && slices.Contains(names, directive.Name) {
+			found = directive
+		}
+	})
+
+	return found
+}
+
+// FindPrefix returns the first directive of the tree whose name starts with one of the given prefixes.
+func FindPrefix(directives []*Directive, prefixes ...string) *Directive {
+	var found *Directive
+
+	Walk(directives, func(directive *Directive, _ []*Directive) {
+		if found != nil {
+			return
+		}
+
+		for _, prefix := range prefixes {
+			if strings.HasPrefix(directive.Name, prefix) {
+				found = directive
+
+				return
+			}
+		}
+	})
+
+	return found
+}
@@ -0,0 +1,211 @@
+package nginx_test
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/nikhilsbhat/nginx-traefik-converter/pkg/nginx"
+)
+
+// render describes the directives with their arguments, positions and blocks on a single line.
+func render(directives []*nginx.Directive) string {
+	parts := make([]string, 0, len(directives))
+
+	for _, directive := range directives {
+		part := fmt.Sprintf("%d:%d %s", directive.Line, directive.Column, strings.Join(append([]string{directive.Name}, directive.Args...), "|"))
+
+		if directive.IsBlock() {
+			part += " { " + render(directive.Block) + " }"
+		}
+
+		parts = append(parts, part)
+	}
+
+	return strings.Join(parts, "; ")
+}
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		name string
+		src  string
+		want string
+	}{
+		{
+			name: "empty",
+			src:  "  \n\t",
+			want: "",
+		},
+		{
+			name: "single directive",
+			src:  "add_header X-Frame-Options DENY;",
+			want: "1:1 add_header|X-Frame-Options|DENY",
+		},
+		{
+			name: "several directives on one line",
+			src:  "gzip on; expires 1h;  add_header X-A b;",
+			want: "1:1 gzip|on; 1:10 expires|1h; 1:23 add_header|X-A|b",
+		},
+		{
+			name: "multi-line directive",
+			src:  "more_set_headers\n  \"X-A: a\"\n  'X-B: b';",
+			want: "1:1 more_set_headers|X-A: a|X-B: b",
+		},
+		{
+			name: "quoted semicolon and braces",
+			src:  `add_header Content-Security-Policy "default-src 'self'; img-src {*}";`,
+			want: "1:1 add_header|Content-Security-Policy|default-src 'self'; img-src {*}",
+		},
+		{
+			name: "escaped quotes",
+			src:  `add_header X-A "say \"hi\" \\ bye";`,
+			want: `1:1 add_header|X-A|say "hi" \ bye`,
+		},
+		{
+			name: "comments",
+			src:  "# leading comment\nadd_header X-A a; # trailing; comment {\n  # indented\nexpires 1h;",
+			want: "2:1 add_header|X-A|a; 4:1 expires|1h",
+		},
+		{
+			name: "braced variable",
+			src:  "add_header X-A ${host}-suffix;",
+			want: "1:1 add_header|X-A|${host}-suffix",
+		},
+		{
+			name: "nested blocks",
+			src:  "location /api {\n  if ($request_method = OPTIONS) {\n    return 204;\n  }\n  proxy_pass http://api;\n}",
+			want: "1:1 location|/api { 2:3 if|$request_method|=|OPTIONS { 3:5 return|204 }; 5:3 proxy_pass|http://api }",
+		},
+		{
+			name: "if condition with spaced parentheses",
+			src:  "if ( $http_origin ~* (example.com) ) { add_header X-A a; }",
+			want: "1:1 if|$http_origin|~*|(example.com) { 1:40 add_header|X-A|a }",
+		},
+		{
+			name: "empty block",
+			src:  "location / {}",
+			want: "1:1 location|/ {  }",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			directives, err := nginx.Parse(test.src)
+			if err != nil {
+				t.Fatalf("Parse() error = %v", err)
+			}
+
+			if got := render(directives); got != test.want {
+				t.Errorf("Parse() =\n%s\nwant\n%s", got, test.want)
+			}
+		})
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	tests := []struct {
+		name string
+		src  string
+		want string
+	}{
+		{
+			name: "unterminated double quote",
+			src:  "add_header X-A a;\nadd_header X-B \"b;",
+			want: "line 2, column 16: unterminated \" quoted string",
+		},
+		{
+			name: "unterminated single quote",
+			src:  "add_header X-A 'a;",
+			want: "line 1, column 16: unterminated ' quoted string",
+		},
+		{
+			name: "unterminated brace",
+			src:  "location / {\n  if ($host) {\n    return 204;\n  }\n",
+			want: "line 1, column 12: unexpected end of configuration, the block opened here expects \"}\"",
+		},
+		{
+			name: "unexpected closing brace",
+			src:  "add_header X-A a;\n}",
+			want: "line 2, column 1: unexpected \"}\"",
+		},
+		{
+			name: "stray semicolon",
+			src:  "  ;",
+			want: "line 1, column 3: unexpected \";\"",
+		},
+		{
+			name: "missing semicolon",
+			src:  "add_header X-A a;\n  expires 1h",
+			want: "line 2, column 3: directive \"expires\" is not terminated by \";\"",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			_, err := nginx.Parse(test.src)
+			if err == nil {
+				t.Fatal("Parse() expected an error")
+			}
+
+			if err.Error() != test.want {
+				t.Errorf("Parse() error = %q, want %q", err.Error(), test.want)
+			}
+		})
+	}
+}
+
+func TestDirective(t *testing.T) {
+	directives, err := nginx.Parse("location /api {\n  return 204;\n}")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	location := directives[0]
+	if got := location.String(); got != "location /api { ... }" {
+		t.Errorf("String() = %q", got)
+	}
+
+	if got := location.Block[0].String(); got != "return 204;" {
+		t.Errorf("String() = %q", got)
+	}
+
+	if got := location.Block[0].Position(); got != "line 2, column 3" {
+		t.Errorf("Position() = %q", got)
+	}
+}
+
+func TestWalkAndFind(t *testing.T) {
+	directives, err := nginx.Parse("gzip on;\nlocation / {\n  if ($host) {\n    lua_code_cache off;\n    return 204;\n  }\n}\nreturn 200;")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	visited := make([]string, 0)
+
+	nginx.Walk(directives, func(directive *nginx.Directive, parents []*nginx.Directive) {
+		names := make([]string, 0, len(parents)+1)
+		for _, parent := range parents {
+			names = append(names, parent.Name)
+		}
+
+		visited = append(visited, strings.Join(append(names, directive.Name), "/"))
+	})
+
+	want := []string{"gzip", "location", "location/if", "location/if/lua_code_cache", "location/if/return", "return"}
+	if !slices.Equal(visited, want) {
+		t.Errorf("Walk() visited %v, want %v", visited, want)
+	}
+
+	if found := nginx.Find(directives, "return"); found == nil || found.Line != 5 {
+		t.Errorf("Find() = %v, want the nested return of line 5", found)
+	}
+
+	if found := nginx.FindPrefix(directives, "lua_"); found == nil || found.Name != "lua_code_cache" {
+		t.Errorf("FindPrefix() = %v, want lua_code_cache", found)
+	}
+
+	if found := nginx.Find(directives, "proxy_pass"); found != nil {
+		t.Errorf("Find() = %v, want nil", found)
+	}
+}
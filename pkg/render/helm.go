@@ -0,0 +1,153 @@
+package render
+
+import (
+	"fmt"
+	"maps"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/nikhilsbhat/nginx-traefik-converter/pkg/configs"
+	"sigs.k8s.io/yaml"
+)
+
+// HelmValuesFile is the name of the file holding the values of the official Traefik Helm chart
+// equivalent to the static configuration.
+const HelmValuesFile = "traefik-values.yaml"
+
+// WriteHelmValues writes the values of the official Traefik Helm chart equivalent to the static configuration
+// to the given path. Nothing is written when no value differs from the chart defaults, as when only
+// the default web and websecure entry points are used.
+func WriteHelmValues(cfg *configs.StaticConfig, path string) error {
+	if cfg == nil || cfg.IsEmpty() {
+		return nil
+	}
+
+	values := helmValues(cfg)
+	if len(values) == 0 {
+		return nil
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), dirPermission); err != nil {
+		return err
+	}
+
+	data, err := yaml.Marshal(values)
+	if err != nil {
+		return err
+	}
+
+	return os.WriteFile(path, data, filePermission)
+}
+
+// helmValues maps the static configuration onto the values of the chart. The web and websecure ports
+// keep the ports of the chart, the other entry points are exposed on the port they listen on.
+// Settings the chart has no value for are passed as additionalArguments.
+func helmValues(cfg *configs.StaticConfig) map[string]any {
+	values := make(map[string]any)
+
+	if cfg.Experimental != nil {
+		values["experimental"] = map[string]any{
+			"plugins": cfg.Experimental.Plugins,
+		}
+	}
+
+	ports := make(map[string]any)
+	arguments := make([]string, 0)
+
+	for _, name := range slices.Sorted(maps.Keys(cfg.EntryPoints)) {
+		entryPoint := cfg.EntryPoints[name]
+		port := make(map[string]any)
+
+		if name != configs.EntryPointWeb && name != configs.EntryPointWebSecure {
+			number, protocol := splitAddress(entryPoint.Address)
+
+			port["port"] = number
+			port["exposedPort"] = number
+			port["protocol"] = protocol
+			port["expose"] = map[string]any{"default": true}
+		}
+
+		if entryPoint.ProxyProtocol != nil {
+			port["proxyProtocol"] = entryPoint.ProxyProtocol
+		}
+
+		if entryPoint.ForwardedHeaders != nil {
+			port["forwardedHeaders"] = entryPoint.ForwardedHeaders
+		}
+
+		if entryPoint.HTTP != nil {
+			if len(entryPoint.HTTP.Middlewares) > 0 {
+				port["middlewares"] = entryPoint.HTTP.Middlewares
+			}
+
+			if entryPoint.HTTP.MaxHeaderBytes > 0 {
+				arguments = append(arguments,
+					fmt.Sprintf("--entryPoints.%s.http.maxHeaderBytes=%d", name, entryPoint.HTTP.MaxHeaderBytes))
+			}
+		}
+
+		if len(port) > 0 {
+			ports[name] = port
+		}
+	}
+
+	if len(ports) > 0 {
+		values["ports"] = ports
+	}
+
+	if len(arguments) > 0 {
+		values["additionalArguments"] = arguments
+	}
+
+	if cfg.Tracing != nil {
+		values["tracing"] = helmTracing(cfg.Tracing)
+	}
+
+	return values
+}
+
+func helmTracing(tracing *configs.Tracing) map[string]any {
+	values := make(map[string]any)
+
+	if tracing.ServiceName != "" {
+		values["serviceName"] = tracing.ServiceName
+	}
+
+	if tracing.SampleRate != nil {
+		values["sampleRate"] = *tracing.SampleRate
+	}
+
+	if tracing.OTLP != nil && tracing.OTLP.GRPC != nil {
+		grpc := map[string]any{
+			"enabled":  true,
+			"insecure": tracing.OTLP.GRPC.Insecure,
+		}
+
+		if tracing.OTLP.GRPC.Endpoint != "" {
+			grpc["endpoint"] = tracing.OTLP.GRPC.Endpoint
+		}
+
+		values["otlp"] = map[string]any{
+			"enabled": true,
+			"grpc":    grpc,
+		}
+	}
+
+	return values
+}
+
+// splitAddress splits an entry point address such as :9000/udp into its port and protocol, TCP by default.
+func splitAddress(address string) (int, string) {
+	address, protocol, found := strings.Cut(address, "/")
+	if !found {
+		protocol = "tcp"
+	}
+
+	_, rawPort, _ := strings.Cut(address, ":")
+	port, _ := strconv.Atoi(rawPort)
+
+	return port, strings.ToUpper(protocol)
+}
@@ -0,0 +1,67 @@
+package render_test
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/nikhilsbhat/nginx-traefik-converter/pkg/configs"
+	"github.com/nikhilsbhat/nginx-traefik-converter/pkg/render"
+	traefik "github.com/traefik/traefik/v3/pkg/provider/kubernetes/crd/traefikio/v1alpha1"
+)
+
+func TestWriteHelmValuesDefaultEntryPoints(t *testing.T) {
+	path := filepath.Join(t.TempDir(), render.HelmValuesFile)
+
+	static := configs.NewStaticConfig(nil)
+	static.Merge(configs.Result{
+		IngressRoutes: []*traefik.IngressRoute{
+			{Spec: traefik.IngressRouteSpec{EntryPoints: []string{configs.EntryPointWeb, configs.EntryPointWebSecure}}},
+		},
+	})
+
+	if err := render.WriteHelmValues(static, path); err != nil {
+		t.Fatalf("WriteHelmValues() error = %v", err)
+	}
+
+	// the chart already serves web and websecure, there is nothing to override
+	if _, err := os.Stat(path); !os.IsNotExist(err) {
+		t.Errorf("%s should not be written for the default entry points", render.HelmValuesFile)
+	}
+}
+
+func TestWriteHelmValues(t *testing.T) {
+	path := filepath.Join(t.TempDir(), render.HelmValuesFile)
+
+	static := configs.NewStaticConfig(nil)
+	static.Merge(configs.Result{
+		EntryPoints: map[string]*configs.EntryPoint{
+			"tcp-5432": {Address: ":5432/tcp"},
+			configs.EntryPointWeb: {
+				HTTP: &configs.EntryPointHTTP{MaxHeaderBytes: 65536, Middlewares: []string{"ingress-nginx-hsts@kubernetescrd"}},
+			},
+		},
+	})
+
+	if err := render.WriteHelmValues(static, path); err != nil {
+		t.Fatalf("WriteHelmValues() error = %v", err)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, want := range []string{
+		"tcp-5432:",
+		"exposedPort: 5432",
+		"protocol: TCP",
+		"- ingress-nginx-hsts@kubernetescrd",
+		"- --entryPoints.web.http.maxHeaderBytes=65536",
+	} {
+		if !strings.Contains(string(data), want) {
+			t.Errorf("%s does not contain %q:\n%s", render.HelmValuesFile, want, data)
+		}
+	}
+}
@@ -0,0 +1,32 @@
+package render
+
+import (
+	"os"
+	"path/filepath"
+
+	"github.com/nikhilsbhat/nginx-traefik-converter/pkg/configs"
+	"sigs.k8s.io/yaml"
+)
+
+const filePermission = 0o644
+
+// StaticConfigFile is the name of the file holding the Traefik static configuration required by the converted resources.
+const StaticConfigFile = "traefik-static.yaml"
+
+// WriteStaticConfig writes the Traefik static configuration to the given path, nothing is written when it is empty.
+func WriteStaticConfig(cfg *configs.StaticConfig, path string) error {
+	if cfg == nil || cfg.IsEmpty() {
+		return nil
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), dirPermission); err != nil {
+		return err
+	}
+
+	data, err := yaml.Marshal(cfg)
+	if err != nil {
+		return err
+	}
+
+	return os.WriteFile(path, data, filePermission)
+}
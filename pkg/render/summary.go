@@ -29,6 +29,9 @@ type SummaryCounts struct {
 
 	// Ignored is the number of annotations that were intentionally ignored.
 	Ignored int `yaml:"ignored,omitempty"   json:"ignored,omitempty"`
+
+	// Unhandled is the number of NGINX annotations that no converter processed.
+	Unhandled int `yaml:"unhandled,omitempty" json:"unhandled,omitempty"`
 }
 
 // Config controls how reports are rendered.
@@ -44,6 +47,7 @@ var statusLabel = map[configs.AnnotationStatus]string{
 	configs.AnnotationWarned:    "Warning",
 	configs.AnnotationSkipped:   "Skipped",
 	configs.AnnotationIgnored:   "Ignored",
+	configs.AnnotationUnhandled: "Unhandled",
 }
 
 const fixedStringLength = 80
@@ -103,6 +107,7 @@ func printSubSectionSeparator(title string) {
 // including a detailed per-annotation table and a summary table.
 func (cfg *Config) printIngressReportTable(ingressReport configs.IngressReport) error {
 	printSectionSeparator(fmt.Sprintf("INGRESS: %s/%s", ingressReport.Namespace, ingressReport.Name))
+	printRouteMode(ingressReport)
 
 	table := tablewriter.NewWriter(os.Stdout)
 	table.Header([]string{"Annotation", "Status", "Message"})
@@ -150,6 +155,7 @@ func renderSummaryTable(summaryCounts SummaryCounts) error {
 		{"Warnings", color.HiYellowString(strconv.Itoa(summaryCounts.Warnings))},
 		{"Skipped", color.HiRedString(strconv.Itoa(summaryCounts.Skipped))},
 		{"Ignored", color.HiBlueString(strconv.Itoa(summaryCounts.Ignored))},
+		{"Unhandled", color.HiMagentaString(strconv.Itoa(summaryCounts.Unhandled))},
 		{"Result", resultLabel(summaryCounts)},
 	}
 
@@ -165,6 +171,7 @@ func renderSummaryTable(summaryCounts SummaryCounts) error {
 // printIngressReport renders a single Ingress report in plain text format.
 func (cfg *Config) printIngressReport(ingressReport configs.IngressReport) {
 	printSectionSeparator(fmt.Sprintf("INGRESS: %s/%s", ingressReport.Namespace, ingressReport.Name))
+	printRouteMode(ingressReport)
 
 	for _, entries := range ingressReport.Entries {
 		switch entries.Status {
@@ -176,6 +183,8 @@ func (cfg *Config) printIngressReport(ingressReport configs.IngressReport) {
 			fmt.Printf("  ❌ %s\n      → %s\n", entries.Name, entries.Message)
 		case configs.AnnotationIgnored:
 			fmt.Printf("  ℹ️  %s\n", entries.Name)
+		case configs.AnnotationUnhandled:
+			fmt.Printf("  ❓ %s\n      → %s\n", entries.Name, entries.Message)
 		}
 	}
 
@@ -199,14 +208,29 @@ func printSummaryText(title string, summaryCounts SummaryCounts) {
 	fmt.Printf("Warnings:  %s\n", color.HiYellowString(strconv.Itoa(summaryCounts.Warnings)))
 	fmt.Printf("Skipped:   %s\n", color.HiRedString(strconv.Itoa(summaryCounts.Skipped)))
 	fmt.Printf("Ignored:   %s\n", color.HiBlueString(strconv.Itoa(summaryCounts.Ignored)))
+	fmt.Printf("Unhandled: %s\n", color.HiMagentaString(strconv.Itoa(summaryCounts.Unhandled)))
 	fmt.Printf("Result:    %s\n\n", resultLabel(summaryCounts))
 }
 
 // ---------------- Helpers ----------------
 
+// printRouteMode prints the kind of route generated for the Ingress.
+func printRouteMode(ingressReport configs.IngressReport) {
+	if ingressReport.RouteMode == "" {
+		return
+	}
+
+	mode := color.HiGreenString(ingressReport.RouteMode)
+	if ingressReport.RouteMode == configs.RoutedByNone {
+		mode = color.HiYellowString(ingressReport.RouteMode)
+	}
+
+	fmt.Printf("Route mode: %s\n\n", mode)
+}
+
 // resultLabel returns a human-readable overall result string based on summary counts.
 func resultLabel(summaryCounts SummaryCounts) string {
-	if summaryCounts.Skipped > 0 {
+	if summaryCounts.Skipped > 0 || summaryCounts.Unhandled > 0 {
 		return color.HiRedString("Manual action required")
 	}
 
@@ -231,6 +255,8 @@ func summarizeIngress(ingressReport configs.IngressReport) SummaryCounts {
 			summaryCounts.Skipped++
 		case configs.AnnotationIgnored:
 			summaryCounts.Ignored++
+		case configs.AnnotationUnhandled:
+			summaryCounts.Unhandled++
 		}
 	}
 
@@ -248,6 +274,7 @@ func summarizeGlobal(globalReport configs.GlobalReport) SummaryCounts {
 		total.Warnings += summarizedIngress.Warnings
 		total.Skipped += summarizedIngress.Skipped
 		total.Ignored += summarizedIngress.Ignored
+		total.Unhandled += summarizedIngress.Unhandled
 	}
 
 	return total
@@ -264,6 +291,8 @@ func statusLabelColored(annotationStatus configs.AnnotationStatus) string {
 		return color.HiRedString("Skipped")
 	case configs.AnnotationIgnored:
 		return color.HiBlueString("Ignored")
+	case configs.AnnotationUnhandled:
+		return color.HiMagentaString("Unhandled")
 	default:
 		return statusLabel[annotationStatus]
 	}
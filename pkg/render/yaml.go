@@ -2,6 +2,7 @@ package render
 
 import (
 	"fmt"
+	"io"
 	"log"
 	"os"
 	"path/filepath"
@@ -26,6 +27,20 @@ func WriteYAML(res configs.Result, outDir string) error {
 		return err
 	}
 
+	if err := writeObjects(
+		filepath.Join(outDir, "serverstransports.yaml"),
+		toClientObjects(res.ServersTransports),
+	); err != nil {
+		return err
+	}
+
+	if err := writeObjects(
+		filepath.Join(outDir, "traefikservices.yaml"),
+		toClientObjects(res.TraefikServices),
+	); err != nil {
+		return err
+	}
+
 	if err := writeObjects(
 		filepath.Join(outDir, "ingressroutes.yaml"),
 		toClientObjects(res.IngressRoutes),
@@ -33,6 +48,27 @@ func WriteYAML(res configs.Result, outDir string) error {
 		return err
 	}
 
+	if err := writeObjects(
+		filepath.Join(outDir, "ingressroutestcp.yaml"),
+		toClientObjects(res.IngressRoutesTCP),
+	); err != nil {
+		return err
+	}
+
+	if err := writeObjects(
+		filepath.Join(outDir, "ingressroutesudp.yaml"),
+		toClientObjects(res.IngressRoutesUDP),
+	); err != nil {
+		return err
+	}
+
+	if err := writeObjects(
+		filepath.Join(outDir, "serverstransportstcp.yaml"),
+		toClientObjects(res.ServersTransportsTCP),
+	); err != nil {
+		return err
+	}
+
 	if err := writeObjects(
 		filepath.Join(outDir, "tlsoptions.yaml"),
 		toClientObjects(res.TLSOptions),
@@ -40,6 +76,13 @@ func WriteYAML(res configs.Result, outDir string) error {
 		return err
 	}
 
+	if err := writeObjects(
+		filepath.Join(outDir, "ingresses.yaml"),
+		toClientObjects(res.Ingresses),
+	); err != nil {
+		return err
+	}
+
 	if len(res.Warnings) > 0 {
 		if err := writeWarnings(
 			filepath.Join(outDir, "warnings.txt"),
@@ -52,6 +95,77 @@ func WriteYAML(res configs.Result, outDir string) error {
 	return nil
 }
 
+// WriteBundle writes the translated configs of all the results into a single multi-document file.
+// Objects are ordered by kind so that the referenced resources precede the ones referencing them:
+// Middlewares, TLSOptions, ServersTransports, ServersTransportTCPs, TraefikServices, IngressRoutes,
+// IngressRouteTCPs, IngressRouteUDPs and then Ingresses.
+func WriteBundle(results []configs.Result, path string) error {
+	if dir := filepath.Dir(path); dir != "." {
+		if err := os.MkdirAll(dir, dirPermission); err != nil {
+			return err
+		}
+	}
+
+	objs := make([]client.Object, 0)
+
+	for _, res := range results {
+		objs = append(objs, toClientObjects(res.Middlewares)...)
+	}
+
+	for _, res := range results {
+		objs = append(objs, toClientObjects(res.TLSOptions)...)
+	}
+
+	for _, res := range results {
+		objs = append(objs, toClientObjects(res.ServersTransports)...)
+	}
+
+	for _, res := range results {
+		objs = append(objs, toClientObjects(res.ServersTransportsTCP)...)
+	}
+
+	for _, res := range results {
+		objs = append(objs, toClientObjects(res.TraefikServices)...)
+	}
+
+	for _, res := range results {
+		objs = append(objs, toClientObjects(res.IngressRoutes)...)
+	}
+
+	for _, res := range results {
+		objs = append(objs, toClientObjects(res.IngressRoutesTCP)...)
+	}
+
+	for _, res := range results {
+		objs = append(objs, toClientObjects(res.IngressRoutesUDP)...)
+	}
+
+	for _, res := range results {
+		objs = append(objs, toClientObjects(res.Ingresses)...)
+	}
+
+	return writeObjects(path, uniqueObjects(objs))
+}
+
+// uniqueObjects drops the repeated objects, as the ones shared by several ingresses, keeping the first occurrence.
+func uniqueObjects(objs []client.Object) []client.Object {
+	seen := make(map[string]struct{}, len(objs))
+	out := make([]client.Object, 0, len(objs))
+
+	for _, obj := range objs {
+		key := fmt.Sprintf("%s/%s/%s", obj.GetObjectKind().GroupVersionKind().Kind, obj.GetNamespace(), obj.GetName())
+		if _, exists := seen[key]; exists {
+			continue
+		}
+
+		seen[key] = struct{}{}
+
+		out = append(out, obj)
+	}
+
+	return out
+}
+
 func toClientObjects[T client.Object](in []T) []client.Object {
 	out := make([]client.Object, 0, len(in))
 	for _, o := range in {
@@ -76,6 +190,10 @@ func writeObjects(path string, objs []client.Object) error {
 		}
 	}(file)
 
+	return encodeObjects(file, objs)
+}
+
+func encodeObjects(writer io.Writer, objs []client.Object) error {
 	for index, obj := range objs {
 		data, err := yaml.Marshal(obj)
 		if err != nil {
@@ -83,12 +201,12 @@ func writeObjects(path string, objs []client.Object) error {
 		}
 
 		if index > 0 {
-			if _, err = file.WriteString("\n---\n"); err != nil {
+			if _, err = io.WriteString(writer, "\n---\n"); err != nil {
 				return err
 			}
 		}
 
-		if _, err = file.Write(data); err != nil {
+		if _, err = writer.Write(data); err != nil {
 			return err
 		}
 	}
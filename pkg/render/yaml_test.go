@@ -0,0 +1,122 @@
+package render_test
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/nikhilsbhat/nginx-traefik-converter/pkg/configs"
+	"github.com/nikhilsbhat/nginx-traefik-converter/pkg/render"
+	traefik "github.com/traefik/traefik/v3/pkg/provider/kubernetes/crd/traefikio/v1alpha1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func newMiddleware(name string) *traefik.Middleware {
+	return &traefik.Middleware{
+		TypeMeta:   metav1.TypeMeta{APIVersion: traefik.SchemeGroupVersion.String(), Kind: "Middleware"},
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "default"},
+	}
+}
+
+func newTLSOption(name string) *traefik.TLSOption {
+	return &traefik.TLSOption{
+		TypeMeta:   metav1.TypeMeta{APIVersion: traefik.SchemeGroupVersion.String(), Kind: "TLSOption"},
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "default"},
+	}
+}
+
+func newIngressRoute(name string) *traefik.IngressRoute {
+	return &traefik.IngressRoute{
+		TypeMeta:   metav1.TypeMeta{APIVersion: traefik.SchemeGroupVersion.String(), Kind: "IngressRoute"},
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "default"},
+	}
+}
+
+func TestWriteYAML(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "default", "app")
+
+	res := configs.Result{
+		Middlewares:   []*traefik.Middleware{newMiddleware("app-cors"), newMiddleware("app-ratelimit")},
+		IngressRoutes: []*traefik.IngressRoute{newIngressRoute("app")},
+		Warnings:      []string{"something to review"},
+	}
+
+	if err := render.WriteYAML(res, dir); err != nil {
+		t.Fatalf("WriteYAML() error = %v", err)
+	}
+
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	files := make([]string, 0, len(entries))
+	for _, entry := range entries {
+		files = append(files, entry.Name())
+	}
+
+	// files are only written for the kinds the result holds
+	if got := strings.Join(files, ","); got != "ingressroutes.yaml,middlewares.yaml,warnings.txt" {
+		t.Errorf("files = %s, want ingressroutes.yaml,middlewares.yaml,warnings.txt", got)
+	}
+
+	middlewares, err := os.ReadFile(filepath.Join(dir, "middlewares.yaml"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if strings.Count(string(middlewares), "\n---\n") != 1 {
+		t.Errorf("middlewares.yaml should hold two documents:\n%s", middlewares)
+	}
+
+	warnings, err := os.ReadFile(filepath.Join(dir, "warnings.txt"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if string(warnings) != "- something to review\n" {
+		t.Errorf("warnings.txt = %q", warnings)
+	}
+}
+
+func TestWriteBundle(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "out", "bundle.yaml")
+
+	results := []configs.Result{
+		{
+			IngressRoutes: []*traefik.IngressRoute{newIngressRoute("one")},
+			Middlewares:   []*traefik.Middleware{newMiddleware("one-cors")},
+			TLSOptions:    []*traefik.TLSOption{newTLSOption("mtls-ca")},
+		},
+		{
+			IngressRoutes: []*traefik.IngressRoute{newIngressRoute("two")},
+			Middlewares:   []*traefik.Middleware{newMiddleware("two-cors")},
+			TLSOptions:    []*traefik.TLSOption{newTLSOption("mtls-ca")},
+		},
+	}
+
+	if err := render.WriteBundle(results, path); err != nil {
+		t.Fatalf("WriteBundle() error = %v", err)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	documents := strings.Split(string(data), "\n---\n")
+
+	want := []string{"Middleware/one-cors", "Middleware/two-cors", "TLSOption/mtls-ca", "IngressRoute/one", "IngressRoute/two"}
+	if len(documents) != len(want) {
+		t.Fatalf("bundle holds %d documents, want %d:\n%s", len(documents), len(want), data)
+	}
+
+	for index, document := range documents {
+		kind, name, _ := strings.Cut(want[index], "/")
+
+		if !strings.Contains(document, "kind: "+kind) || !strings.Contains(document, "name: "+name) {
+			t.Errorf("document %d is not %s:\n%s", index, want[index], document)
+		}
+	}
+}